@@ -0,0 +1,248 @@
+package bridge
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/metrics"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/notify"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// Limits on inbound messages. Chat lines are at most 256 characters in
+// game, and player names 16.
+const (
+	maxBody   = 4 << 10
+	maxText   = 256
+	maxSender = 16
+)
+
+// Config configures the bridge.
+type Config struct {
+	ServerDir string
+	Manager   management.ServerManager
+	Output    *ui.UI
+}
+
+// Run serves the inbound endpoint and forwards game chat to the outbound
+// webhook, if there is one, until ctx is cancelled.
+func Run(ctx context.Context, cfg *Config) error {
+	if cfg.Manager == nil || cfg.Output == nil || cfg.ServerDir == "" {
+		return errors.New("the chat bridge needs a manager, output and server directory")
+	}
+	s, err := Load(cfg.ServerDir)
+	if err != nil {
+		return err
+	}
+	b := newBridge(cfg, s)
+	if !metrics.IsLoopback(s.Listen) {
+		cfg.Output.Warn("Chat bridge listens on %s — use HTTPS in front of it so the token isn't sent in the clear", s.Listen)
+	}
+
+	if s.Outbound != nil {
+		out, err := notify.New(&notify.Settings{Targets: []notify.Target{*s.Outbound}}, cfg.Output)
+		if err != nil {
+			return fmt.Errorf("%s outbound: %w", ConfigFile, err)
+		}
+		go b.forward(ctx, out)
+	}
+
+	lc := net.ListenConfig{}
+	ln, err := lc.Listen(ctx, "tcp", s.Listen)
+	if err != nil {
+		return fmt.Errorf("chat bridge listen: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/message", b)
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("chat bridge server: %w", err)
+	}
+	return nil
+}
+
+type bridge struct {
+	cfg       *Config
+	sender    string
+	limit     int
+	tokenHash [sha256.Size]byte
+
+	mu   sync.Mutex
+	sent []time.Time
+}
+
+func newBridge(cfg *Config, s *Settings) *bridge {
+	return &bridge{cfg: cfg, sender: s.Sender, limit: s.RateLimit, tokenHash: sha256.Sum256([]byte(s.Token))}
+}
+
+// forward sends chat, joins and leaves from the log to out.
+func (b *bridge) forward(ctx context.Context, out *notify.Notifier) {
+	stream := events.NewStream(b.cfg.ServerDir)
+	parser, err := events.LoadParser(b.cfg.ServerDir)
+	if err != nil {
+		b.cfg.Output.Warn("Ignoring custom chat formats: %s", err)
+	}
+	stream.Parser = parser
+	for ev := range stream.Subscribe(ctx, events.FromEnd) {
+		var msg notify.Event
+		switch ev.Kind {
+		case events.Chat:
+			msg = notify.Event{Event: notify.Chat, Player: ev.Player, Detail: ev.Message}
+		case events.Join:
+			msg = notify.Event{Event: notify.Join, Player: ev.Player}
+		case events.Leave:
+			msg = notify.Event{Event: notify.Leave, Player: ev.Player}
+		default:
+			continue
+		}
+		if err := out.Send(ctx, &msg); err != nil {
+			b.cfg.Output.Warn("Chat bridge: %s", err)
+		}
+	}
+}
+
+// inbound is the body of a POST to /message.
+type inbound struct {
+	Text string `json:"text"`
+	// From names the sender instead of the configured one.
+	From string `json:"from,omitempty"`
+}
+
+// ServeHTTP shows a posted message in game.
+func (b *bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "POST a JSON message", http.StatusMethodNotAllowed)
+		return
+	}
+	if !b.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="mc-dad-server"`)
+		http.Error(w, "missing or wrong token", http.StatusUnauthorized)
+		return
+	}
+	var in inbound
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(&in); err != nil {
+		http.Error(w, "body must be JSON like {\"text\": \"Dinner's ready!\"}", http.StatusBadRequest)
+		return
+	}
+	text := sanitize(in.Text, maxText)
+	if text == "" {
+		http.Error(w, "text is empty", http.StatusBadRequest)
+		return
+	}
+	sender := b.sender
+	if from := sanitizeName(in.From); from != "" {
+		sender = from
+	}
+	if wait := b.allow(time.Now()); wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		http.Error(w, "too many messages, try again shortly", http.StatusTooManyRequests)
+		return
+	}
+	if err := b.cfg.Manager.SendCommand(r.Context(), tellraw(sender, text)); err != nil {
+		b.cfg.Output.Warn("Chat bridge: sending message: %s", err)
+		http.Error(w, "the server isn't reachable", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorized checks the bearer token in constant time.
+func (b *bridge) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	got := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return subtle.ConstantTimeCompare(got[:], b.tokenHash[:]) == 1
+}
+
+// allow records a message at now if it is within the rate limit, and
+// otherwise returns how long until there is room.
+func (b *bridge) allow(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	recent := b.sent[:0]
+	for _, at := range b.sent {
+		if now.Sub(at) < time.Minute {
+			recent = append(recent, at)
+		}
+	}
+	b.sent = recent
+	if len(recent) >= b.limit {
+		return recent[0].Add(time.Minute).Sub(now)
+	}
+	b.sent = append(b.sent, now)
+	return 0
+}
+
+// tellraw builds the command that shows text from sender to everyone. The
+// message is always a plain text component, so it can't carry click
+// events, selectors or translations.
+func tellraw(sender, text string) string {
+	return management.Tellraw("@a",
+		management.Text{Text: "[Web] ", Color: "aqua"},
+		management.Text{Text: sender + ": ", Color: "aqua"},
+		management.Text{Text: text, Color: "white"})
+}
+
+// sanitize tidies untrusted text to show in game: formatting codes and
+// unprintable characters are removed, runs of spaces are collapsed, and
+// it is cut to at most limit characters. Tellraw makes it safe for the
+// console.
+func sanitize(text string, limit int) string {
+	var b strings.Builder
+	skip := false
+	for _, r := range text {
+		switch {
+		case skip:
+			skip = false
+		case r == '§':
+			// A formatting code: drop it and the character after.
+			skip = true
+		case unicode.IsSpace(r) || !unicode.IsPrint(r):
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	clean := strings.Join(strings.Fields(b.String()), " ")
+	if runes := []rune(clean); len(runes) > limit {
+		clean = strings.TrimSpace(string(runes[:limit]))
+	}
+	return clean
+}
+
+// sanitizeName keeps a sender name to letters, digits, spaces, '_' and
+// '-', and to at most maxSender characters.
+func sanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' || r == '_' || r == '-' {
+			return r
+		}
+		return -1
+	}, sanitize(name, len(name)))
+	return sanitize(name, maxSender)
+}
@@ -0,0 +1,156 @@
+package bridge
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+const testToken = "correct-horse-battery-staple"
+
+func newTestBridge(mgr *management.MockManager, limit int) *bridge {
+	cfg := &Config{ServerDir: "unused", Manager: mgr, Output: ui.NewWriter(&bytes.Buffer{}, false)}
+	return newBridge(cfg, &Settings{Token: testToken, Sender: DefaultSender, RateLimit: limit})
+}
+
+func post(b *bridge, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/message", strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServeHTTP(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		body   string
+		want   int
+		wantTo string
+	}{
+		{"message", testToken, `{"text":"Dinner's ready!"}`, http.StatusNoContent, `tellraw @a ["",{"text":"[Web] ","color":"aqua"},{"text":"Dad: ","color":"aqua"},{"text":"Dinner's ready!","color":"white"}]`},
+		{"screen escapes", testToken, `{"text":"say \"hi\" \\ ^Mop Dad"}`, http.StatusNoContent, `{"text":"say 'hi' / ˆMop Dad","color":"white"}`},
+		{"from someone else", testToken, `{"text":"Bedtime","from":"Mom"}`, http.StatusNoContent, `{"text":"Mom: ","color":"aqua"}`},
+		{"no token", "", `{"text":"hi"}`, http.StatusUnauthorized, ""},
+		{"wrong token", "guess", `{"text":"hi"}`, http.StatusUnauthorized, ""},
+		{"not json", testToken, `hi`, http.StatusBadRequest, ""},
+		{"empty", testToken, `{"text":"  §c "}`, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := management.NewMockManager()
+			rec := post(newTestBridge(mgr, 10), tt.token, tt.body)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.want, rec.Body)
+			}
+			sent := mgr.Commands()
+			if tt.wantTo == "" {
+				if len(sent) != 0 {
+					t.Errorf("sent %v, want nothing", sent)
+				}
+				return
+			}
+			if len(sent) != 1 || !strings.Contains(sent[0], tt.wantTo) {
+				t.Errorf("sent %v, want a command containing %s", sent, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestServeHTTPRateLimit(t *testing.T) {
+	b := newTestBridge(management.NewMockManager(), 2)
+	for i, want := range []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests} {
+		rec := post(b, testToken, `{"text":"hi"}`)
+		if rec.Code != want {
+			t.Errorf("message %d: status = %d, want %d", i+1, rec.Code, want)
+		}
+		if want == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+			t.Error("rate limited without Retry-After")
+		}
+	}
+}
+
+func TestServeHTTPServerDown(t *testing.T) {
+	mgr := management.NewMockManager()
+	mgr.SendErr = errors.New("no screen session")
+	if rec := post(newTestBridge(mgr, 10), testToken, `{"text":"hi"}`); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Dinner's ready!", "Dinner's ready!"},
+		{"  lots   of\n\tspace ", "lots of space"},
+		{"§cred §lbold", "red bold"},
+		{"bell\a\x00", "bell"},
+		{"line break", "line break"},
+		{strings.Repeat("a", 300), strings.Repeat("a", maxText)},
+	}
+	for _, tt := range tests {
+		if got := sanitize(tt.in, maxText); got != tt.want {
+			t.Errorf("sanitize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTellrawHasNoEscapes(t *testing.T) {
+	cmd := tellraw("Dad", sanitize(`<b>"quoted"</b> & \n ^M `+" ", maxText))
+	if strings.ContainsAny(cmd, `\^`) || strings.Contains(cmd, "\n") {
+		t.Errorf("tellraw() = %s; screen would interpret it", cmd)
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	for in, want := range map[string]string{
+		"Dad":                   "Dad",
+		`Mom"},{"text":"x`:      "Momtextx",
+		"A very long name here": "A very long name",
+		"§c":                    "",
+	} {
+		if got := sanitizeName(in); got != want {
+			t.Errorf("sanitizeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Load(dir); err == nil {
+		t.Error("Load() without bridge.json succeeded, want an error asking for a token")
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, ConfigFile), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"token":"short"}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("Load() with a short token succeeded")
+	}
+	write(`{"token":"` + testToken + `","outbound":{"type":"slack","url":"https://hooks.slack.com/x"}}`)
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Listen != DefaultListen || s.Sender != DefaultSender || s.RateLimit != DefaultRateLimit {
+		t.Errorf("Load() = %+v, want defaults filled in", s)
+	}
+	if got := strings.Join(s.Outbound.Events, ","); got != "chat,join,leave" {
+		t.Errorf("outbound events = %s, want chat,join,leave", got)
+	}
+}
@@ -0,0 +1,84 @@
+// Package bridge connects game chat to the outside world both ways: chat,
+// joins and leaves go out to a webhook, and messages posted to an
+// authenticated HTTP endpoint — from a parent's phone, say — are shown in
+// game as "[Web] Dad: ...".
+package bridge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/notify"
+)
+
+// ConfigFile configures the bridge in the server directory.
+const ConfigFile = "bridge.json"
+
+// Defaults for the optional Settings fields.
+const (
+	DefaultListen    = "127.0.0.1:8765"
+	DefaultSender    = "Dad"
+	DefaultRateLimit = 6
+)
+
+// minTokenLength keeps the inbound endpoint from being guarded by
+// something guessable.
+const minTokenLength = 16
+
+// Settings is the on-disk form of ConfigFile:
+//
+//	{
+//	  "listen": "0.0.0.0:8765",
+//	  "token": "a-long-random-secret",
+//	  "sender": "Dad",
+//	  "outbound": {"type": "discord", "url": "https://discord.com/api/webhooks/..."}
+//	}
+type Settings struct {
+	// Listen is the address of the inbound endpoint.
+	Listen string `json:"listen,omitempty"`
+	// Token must be sent as "Authorization: Bearer <token>" to post.
+	Token string `json:"token"`
+	// Sender names whoever posts, unless a message gives its own "from".
+	Sender string `json:"sender,omitempty"`
+	// RateLimit is the most inbound messages shown a minute.
+	RateLimit int `json:"rate_limit,omitempty"`
+	// Outbound, if set, is sent chat, joins and leaves. It takes the same
+	// fields as a notifications.json target; events default to those three.
+	Outbound *notify.Target `json:"outbound,omitempty"`
+}
+
+// Load reads ConfigFile from serverDir and fills in defaults. Unlike most
+// configs, a missing file is an error: the bridge can't run without a
+// token.
+func Load(serverDir string) (*Settings, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, ConfigFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("the chat bridge needs %s with a token in %s", ConfigFile, serverDir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ConfigFile, err)
+	}
+	s := &Settings{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ConfigFile, err)
+	}
+	if len(s.Token) < minTokenLength {
+		return nil, fmt.Errorf("%s: token must be at least %d characters", ConfigFile, minTokenLength)
+	}
+	if s.Listen == "" {
+		s.Listen = DefaultListen
+	}
+	if s.Sender = sanitizeName(s.Sender); s.Sender == "" {
+		s.Sender = DefaultSender
+	}
+	if s.RateLimit <= 0 {
+		s.RateLimit = DefaultRateLimit
+	}
+	if s.Outbound != nil && len(s.Outbound.Events) == 0 {
+		s.Outbound.Events = []string{notify.Chat, notify.Join, notify.Leave}
+	}
+	return s, nil
+}
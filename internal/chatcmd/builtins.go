@@ -0,0 +1,176 @@
+package chatcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/mappool"
+)
+
+// Builtins returns the built-in commands with their default permissions
+// and cooldowns. Anyone may look things up; changing the server is for
+// ops.
+func Builtins(cfg *Config) []Command {
+	b := &builtins{cfg: cfg}
+	return []Command{
+		{
+			Name: "help", Aliases: []string{"commands"}, Help: "list the commands you can use",
+			Permission: Everyone, Cooldown: 5 * time.Second, Run: help,
+		},
+		{
+			Name: "players", Aliases: []string{"online"}, Help: "show who is online",
+			Permission: Everyone, Cooldown: 10 * time.Second, Run: b.players,
+		},
+		{
+			Name: "time", Help: "show the time here and in game",
+			Permission: Everyone, Cooldown: 10 * time.Second, Run: b.time,
+		},
+		{
+			Name: "maps", Help: "list the maps",
+			Permission: Everyone, Cooldown: 10 * time.Second, Run: b.maps,
+		},
+		{
+			Name: "map", Usage: "<map>", MinArgs: 1, Help: "send everyone to a map",
+			Permission: Ops, GlobalCooldown: time.Minute, Run: b.setMap,
+		},
+		{
+			Name: "backup", Help: "back up the world",
+			Permission: Ops, GlobalCooldown: 10 * time.Minute, Run: b.backup,
+		},
+		{
+			Name: "restart", Help: "restart the server",
+			Permission: Ops, GlobalCooldown: 10 * time.Minute, Run: b.restart,
+		},
+	}
+}
+
+type builtins struct {
+	cfg *Config
+}
+
+// help lists the commands the caller may use.
+func help(ctx context.Context, c *Call) error {
+	r := c.router
+	lines := []string{"Commands:"}
+	for _, cmd := range r.available(c.Player) {
+		line := r.usage(cmd)
+		if cmd.Help != "" {
+			line += " — " + cmd.Help
+		}
+		lines = append(lines, line)
+	}
+	c.Reply(ctx, "%s", strings.Join(lines, "\n"))
+	return nil
+}
+
+func (b *builtins) querier() (management.Querier, error) {
+	q, ok := b.cfg.Manager.(management.Querier)
+	if !ok {
+		return nil, Refusal("That needs RCON, which this server doesn't have")
+	}
+	return q, nil
+}
+
+func (b *builtins) players(ctx context.Context, c *Call) error {
+	q, err := b.querier()
+	if err != nil {
+		return err
+	}
+	list, err := management.ListPlayers(ctx, q)
+	if err != nil {
+		return fmt.Errorf("listing players: %w", err)
+	}
+	c.Reply(ctx, "%d/%d online: %s", list.Online, list.Max, strings.Join(list.Names, ", "))
+	return nil
+}
+
+// gameTime matches the reply to "time query": "The time is 6000".
+var gameTime = regexp.MustCompile(`The time is (\d+)`)
+
+// time shows the real time and, over RCON, the in-game time and day.
+func (b *builtins) time(ctx context.Context, c *Call) error {
+	text := "It's " + time.Now().Format("3:04 PM")
+	if q, ok := b.cfg.Manager.(management.Querier); ok {
+		daytime, err1 := queryTime(ctx, q, "daytime")
+		day, err2 := queryTime(ctx, q, "day")
+		if err1 == nil && err2 == nil {
+			text += fmt.Sprintf(" here and %s on day %d in game", gameClock(daytime), day+1)
+		}
+	}
+	c.Reply(ctx, "%s", text)
+	return nil
+}
+
+// queryTime asks the server for "time query <what>".
+func queryTime(ctx context.Context, q management.Querier, what string) (int, error) {
+	reply, err := q.Query(ctx, "time query "+what)
+	if err != nil {
+		return 0, err
+	}
+	m := gameTime.FindStringSubmatch(management.StripFormatting(reply))
+	if m == nil {
+		return 0, fmt.Errorf("unrecognised time reply: %q", reply)
+	}
+	return strconv.Atoi(m[1])
+}
+
+// gameClock converts the time of day in ticks to a clock: tick 0 is
+// sunrise, 06:00, and a day is 24000 ticks.
+func gameClock(ticks int) string {
+	ticks %= 24000
+	hour := (ticks/1000 + 6) % 24
+	minute := ticks % 1000 * 60 / 1000
+	return fmt.Sprintf("%02d:%02d", hour, minute)
+}
+
+func (b *builtins) maps(ctx context.Context, c *Call) error {
+	pool, _, err := mappool.Playable(b.cfg.ServerDir)
+	if err != nil {
+		return err
+	}
+	if len(pool) == 0 {
+		return Refusal("There are no maps yet")
+	}
+	labels := make([]string, len(pool))
+	for i := range pool {
+		labels[i] = pool[i].Label()
+	}
+	c.Reply(ctx, "Maps: %s", strings.Join(labels, ", "))
+	return nil
+}
+
+// setMap sends everyone to the map named by the arguments.
+func (b *builtins) setMap(ctx context.Context, c *Call) error {
+	pool, _, err := mappool.Playable(b.cfg.ServerDir)
+	if err != nil {
+		return err
+	}
+	m, err := mappool.Find(pool, strings.Join(c.Args, " "))
+	switch {
+	case errors.Is(err, mappool.ErrNoMap):
+		return Refusal("No map by that name")
+	case err != nil:
+		return Refusal("More than one map starts like that; type more of the name")
+	}
+	return management.RotateToMap(ctx, &m, b.cfg.Manager, b.cfg.Output)
+}
+
+// backup backs up the world. Backup tells everyone when it starts and
+// finishes.
+func (b *builtins) backup(ctx context.Context, _ *Call) error {
+	return management.Backup(ctx, b.cfg.ServerDir, b.cfg.MaxBackups, b.cfg.Manager, b.cfg.Output)
+}
+
+func (b *builtins) restart(ctx context.Context, c *Call) error {
+	if b.cfg.Runner == nil {
+		return Refusal("Restarting isn't available here")
+	}
+	b.cfg.Output.Info("Restart requested by %s", c.Player)
+	return management.RestartServer(ctx, b.cfg.Manager, b.cfg.Runner, b.cfg.Port, b.cfg.Output)
+}
@@ -0,0 +1,324 @@
+// Package chatcmd runs "!" commands typed in game chat. Messages are read
+// from the server log, so it needs no plugins, and replies go only to the
+// player who asked. Built-in commands cover backups, restarts, the time,
+// the player list and maps; chat-commands.json in the server directory
+// changes who may use them and adds commands that run console commands.
+package chatcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/players"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// DefaultPrefix starts a chat command.
+const DefaultPrefix = "!"
+
+// Permission says who may use a command.
+type Permission string
+
+// Permissions.
+const (
+	// Everyone lets any player use the command.
+	Everyone Permission = "everyone"
+	// Ops limits the command to players in ops.json.
+	Ops Permission = "ops"
+)
+
+// Handler runs a command. An error of type Refusal is shown to the caller
+// as is; any other error is logged and the caller told the command failed.
+type Handler func(ctx context.Context, c *Call) error
+
+// Command is a chat command.
+type Command struct {
+	// Name is typed after the prefix: "backup" for "!backup".
+	Name    string
+	Aliases []string
+	// Usage describes the arguments, such as "<map>".
+	Usage string
+	Help  string
+	// MinArgs is the number of arguments the command needs.
+	MinArgs    int
+	Permission Permission
+	// Allow names players who may use the command whatever its
+	// permission.
+	Allow []string
+	// Cooldown is the least time between uses by one player.
+	Cooldown time.Duration
+	// GlobalCooldown is the least time between uses by anyone.
+	GlobalCooldown time.Duration
+	Run            Handler
+}
+
+// Refusal is an error meant for the player, such as a usage message.
+type Refusal string
+
+func (r Refusal) Error() string { return string(r) }
+
+// Call is one use of a command.
+type Call struct {
+	Player string
+	Args   []string
+	mgr    management.ServerManager
+	router *Router
+}
+
+// Reply sends text to the caller. Each line is sent separately.
+func (c *Call) Reply(ctx context.Context, format string, args ...any) {
+	for line := range strings.SplitSeq(fmt.Sprintf(format, args...), "\n") {
+		tell(ctx, c.mgr, c.Player, line, "yellow")
+	}
+}
+
+// Config configures the chat command router.
+type Config struct {
+	ServerDir  string
+	Manager    management.ServerManager
+	Runner     platform.CommandRunner
+	Port       int
+	MaxBackups int
+	Output     *ui.UI
+}
+
+// Run reads chat from the server log and runs the commands in it until ctx
+// is cancelled. Each command runs in its own goroutine, so a backup does
+// not hold up "!players".
+func Run(ctx context.Context, cfg *Config) error {
+	if cfg.Manager == nil || cfg.Output == nil || cfg.ServerDir == "" {
+		return errors.New("chat commands need a manager, output and server directory")
+	}
+	r, err := Load(cfg.ServerDir, Builtins(cfg))
+	if err != nil {
+		return err
+	}
+
+	stream := events.NewStream(cfg.ServerDir)
+	parser, err := events.LoadParser(cfg.ServerDir)
+	if err != nil {
+		cfg.Output.Warn("Ignoring custom chat formats: %s", err)
+	}
+	stream.Parser = parser
+	evs := stream.Subscribe(ctx, events.FromEnd)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for ev := range evs {
+		if ev.Kind != events.Chat {
+			continue
+		}
+		cmd, args, refusal := r.route(ev.Player, ev.Message, time.Now())
+		if cmd == nil {
+			continue
+		}
+		if refusal != "" {
+			tell(ctx, cfg.Manager, ev.Player, refusal, "red")
+			continue
+		}
+		cfg.Output.Info("%s used %s%s", ev.Player, r.prefix, cmd.Name)
+		call := &Call{Player: ev.Player, Args: args, mgr: cfg.Manager, router: r}
+		wg.Go(func() {
+			defer r.done(cmd)
+			err := cmd.Run(ctx, call)
+			var refusal Refusal
+			switch {
+			case err == nil:
+			case errors.As(err, &refusal):
+				tell(ctx, cfg.Manager, ev.Player, string(refusal), "red")
+			default:
+				cfg.Output.Warn("%s%s for %s: %s", r.prefix, cmd.Name, ev.Player, err)
+				tell(ctx, cfg.Manager, ev.Player, fmt.Sprintf("%s%s failed — check the server console", r.prefix, cmd.Name), "red")
+			}
+		})
+	}
+	return nil
+}
+
+// Router matches chat messages to commands and enforces permissions and
+// cooldowns.
+type Router struct {
+	prefix    string
+	serverDir string
+	commands  []*Command
+	// byName holds every name and alias, lower-cased.
+	byName map[string]*Command
+	// lastUsed is keyed by command name, and by command name and
+	// lower-cased player name for per-player cooldowns.
+	lastUsed map[string]time.Time
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+// NewRouter returns a router for commands typed after prefix. Ops are read
+// from serverDir's ops.json on each use, so changes apply at once. A later
+// command with the same name or alias as an earlier one replaces it.
+func NewRouter(prefix, serverDir string, cmds []Command) *Router {
+	r := &Router{
+		prefix:    prefix,
+		serverDir: serverDir,
+		byName:    make(map[string]*Command),
+		lastUsed:  make(map[string]time.Time),
+		running:   make(map[string]bool),
+	}
+	for i := range cmds {
+		cmd := &cmds[i]
+		r.commands = slices.DeleteFunc(r.commands, func(c *Command) bool { return strings.EqualFold(c.Name, cmd.Name) })
+		r.commands = append(r.commands, cmd)
+	}
+	for _, cmd := range r.commands {
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			r.byName[strings.ToLower(name)] = cmd
+		}
+	}
+	return r
+}
+
+// route finds the command in a chat message from player. It returns a nil
+// command for messages that aren't one of the router's commands, which
+// other services such as rock the vote may handle. Otherwise it returns
+// the arguments, or the reason to show the player when they may not run it
+// now. An accepted command starts its cooldowns.
+func (r *Router) route(player, msg string, now time.Time) (cmd *Command, args []string, refusal string) {
+	msg = strings.TrimSpace(msg)
+	if !strings.HasPrefix(msg, r.prefix) {
+		return nil, nil, ""
+	}
+	fields := splitArgs(strings.TrimPrefix(msg, r.prefix))
+	if len(fields) == 0 {
+		return nil, nil, ""
+	}
+	cmd = r.byName[strings.ToLower(fields[0])]
+	if cmd == nil {
+		return nil, nil, ""
+	}
+	args = fields[1:]
+
+	if !r.allowed(cmd, player) {
+		return cmd, nil, fmt.Sprintf("You don't have permission to use %s%s", r.prefix, cmd.Name)
+	}
+	if len(args) < cmd.MinArgs {
+		return cmd, nil, "Usage: " + r.usage(cmd)
+	}
+	playerKey := cmd.Name + "\x00" + strings.ToLower(player)
+	if wait := cooldownLeft(r.lastUsed[cmd.Name], cmd.GlobalCooldown, now); wait > 0 {
+		return cmd, nil, fmt.Sprintf("%s%s can be used again in %s", r.prefix, cmd.Name, waitText(wait))
+	}
+	if wait := cooldownLeft(r.lastUsed[playerKey], cmd.Cooldown, now); wait > 0 {
+		return cmd, nil, fmt.Sprintf("You can use %s%s again in %s", r.prefix, cmd.Name, waitText(wait))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running[cmd.Name] {
+		return cmd, nil, fmt.Sprintf("%s%s is already running", r.prefix, cmd.Name)
+	}
+	r.running[cmd.Name] = true
+	r.lastUsed[cmd.Name] = now
+	r.lastUsed[playerKey] = now
+	return cmd, args, ""
+}
+
+// done marks a command as no longer running.
+func (r *Router) done(cmd *Command) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.running, cmd.Name)
+}
+
+// allowed reports whether player may use cmd.
+func (r *Router) allowed(cmd *Command, player string) bool {
+	if cmd.Permission == Everyone {
+		return true
+	}
+	if slices.ContainsFunc(cmd.Allow, func(name string) bool { return strings.EqualFold(name, player) }) {
+		return true
+	}
+	if cmd.Permission != Ops {
+		return false
+	}
+	// An unreadable ops.json denies rather than guesses.
+	ops, err := players.LoadOps(r.serverDir)
+	return err == nil && ops.IsOp(player)
+}
+
+// available returns the commands player may use, in registration order.
+func (r *Router) available(player string) []*Command {
+	var out []*Command
+	for _, cmd := range r.commands {
+		if r.allowed(cmd, player) {
+			out = append(out, cmd)
+		}
+	}
+	return out
+}
+
+// usage returns how to type cmd, such as "!map <map>".
+func (r *Router) usage(cmd *Command) string {
+	if cmd.Usage == "" {
+		return r.prefix + cmd.Name
+	}
+	return r.prefix + cmd.Name + " " + cmd.Usage
+}
+
+// cooldownLeft returns how long until a cooldown that started at last runs
+// out.
+func cooldownLeft(last time.Time, cooldown time.Duration, now time.Time) time.Duration {
+	if last.IsZero() || cooldown <= 0 {
+		return 0
+	}
+	return last.Add(cooldown).Sub(now)
+}
+
+// splitArgs splits a command line on spaces. Double quotes group words, so
+// `!map "Sky Block"` has one argument.
+func splitArgs(s string) []string {
+	var (
+		args    []string
+		cur     strings.Builder
+		quoted  bool
+		started bool
+	)
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case r == ' ' && !quoted:
+			if started {
+				args = append(args, cur.String())
+				cur.Reset()
+				started = false
+			}
+		default:
+			cur.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		args = append(args, cur.String())
+	}
+	return args
+}
+
+// waitText formats a cooldown for chat: "7m" or "45s".
+func waitText(d time.Duration) string {
+	if d >= time.Minute {
+		return fmt.Sprintf("%dm", int((d+time.Minute-1)/time.Minute))
+	}
+	return fmt.Sprintf("%ds", int((d+time.Second-1)/time.Second))
+}
+
+// tell sends text to one player.
+func tell(ctx context.Context, mgr management.ServerManager, player, text, color string) {
+	_ = mgr.SendCommand(ctx, management.Tellraw(player, management.Text{Text: text, Color: color}))
+}
@@ -0,0 +1,190 @@
+package chatcmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/players"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// writeOps makes names operators in dir.
+func writeOps(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	var entries []string
+	for _, n := range names {
+		entries = append(entries, `{"uuid": "", "name": "`+n+`", "level": 4}`)
+	}
+	data := "[" + strings.Join(entries, ",") + "]"
+	if err := os.WriteFile(filepath.Join(dir, players.OpsFile), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func nop(context.Context, *Call) error { return nil }
+
+func TestRoute(t *testing.T) {
+	dir := t.TempDir()
+	writeOps(t, dir, "Dad")
+	r := NewRouter("!", dir, []Command{
+		{Name: "players", Aliases: []string{"online"}, Permission: Everyone, Cooldown: time.Minute, Run: nop},
+		{Name: "map", Usage: "<map>", MinArgs: 1, Permission: Ops, Allow: []string{"Mum"}, Run: nop},
+		{Name: "backup", Permission: Ops, GlobalCooldown: 10 * time.Minute, Run: nop},
+	})
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		player, msg string
+		at          time.Duration
+		cmd         string // "" when the message isn't a command
+		args        []string
+		refusal     string
+	}{
+		{"Steve", "hello !players", 0, "", nil, ""},
+		{"Steve", "!rtv", 0, "", nil, ""},
+		{"Steve", "!", 0, "", nil, ""},
+		{"Steve", "!players", 0, "players", []string{}, ""},
+		{"Steve", "!ONLINE", 10 * time.Second, "players", nil, "You can use !players again in 50s"},
+		{"Alex", "!online", 10 * time.Second, "players", []string{}, ""},
+		{"Steve", "!players", 2 * time.Minute, "players", []string{}, ""},
+		{"Steve", "!map skyblock", 0, "map", nil, "You don't have permission to use !map"},
+		{"dad", "!map", 0, "map", nil, "Usage: !map <map>"},
+		{"dad", `!map "Sky Block"`, 0, "map", []string{"Sky Block"}, ""},
+		{"mum", "!map spiral", 0, "map", []string{"spiral"}, ""},
+		{"Dad", "!backup", 0, "backup", []string{}, ""},
+		{"Dad", "!backup", 3 * time.Minute, "backup", nil, "!backup can be used again in 7m"},
+	}
+	for _, tt := range tests {
+		cmd, args, refusal := r.route(tt.player, tt.msg, now.Add(tt.at))
+		if tt.cmd == "" {
+			if cmd != nil {
+				t.Errorf("route(%s, %q) = %s, want no command", tt.player, tt.msg, cmd.Name)
+			}
+			continue
+		}
+		if cmd == nil || cmd.Name != tt.cmd || refusal != tt.refusal || (refusal == "" && !slices.Equal(args, tt.args)) {
+			t.Errorf("route(%s, %q) = %v %q %q, want %s %q %q", tt.player, tt.msg, cmd, args, refusal, tt.cmd, tt.args, tt.refusal)
+		}
+		if cmd != nil && refusal == "" {
+			r.done(cmd)
+		}
+	}
+}
+
+func TestRouteRunning(t *testing.T) {
+	r := NewRouter("!", t.TempDir(), []Command{{Name: "restart", Permission: Everyone, Run: nop}})
+	cmd, _, _ := r.route("Steve", "!restart", time.Now())
+	if _, _, refusal := r.route("Alex", "!restart", time.Now()); refusal != "!restart is already running" {
+		t.Errorf("second !restart: refusal = %q", refusal)
+	}
+	r.done(cmd)
+	if _, _, refusal := r.route("Alex", "!restart", time.Now()); refusal != "" {
+		t.Errorf("!restart after the first finished: refusal = %q", refusal)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"map  skyblock ", []string{"map", "skyblock"}},
+		{`map "Sky Block"`, []string{"map", "Sky Block"}},
+		{`say ""`, []string{"say", ""}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := splitArgs(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("splitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGameClock(t *testing.T) {
+	tests := []struct {
+		ticks int
+		want  string
+	}{
+		{0, "06:00"},
+		{6000, "12:00"},
+		{18000, "00:00"},
+		{23999, "05:59"},
+		{24500, "06:30"},
+	}
+	for _, tt := range tests {
+		if got := gameClock(tt.ticks); got != tt.want {
+			t.Errorf("gameClock(%d) = %s, want %s", tt.ticks, got, tt.want)
+		}
+	}
+}
+
+// newManager returns a manager that answers queries like a server with
+// two players online at noon on day 3.
+func newManager() *management.MockManager {
+	mgr := management.NewMockManager()
+	mgr.Replies["list"] = "There are 2 of a max of 20 players online: Steve, Alex"
+	mgr.Replies["time query daytime"] = "The time is 6000"
+	mgr.Replies["time query day"] = "The time is 2"
+	return mgr
+}
+
+func TestRunRepliesToCaller(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "logs", "latest.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := newManager()
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(ctx, &Config{ServerDir: dir, Manager: mgr, Output: ui.NewWriter(&bytes.Buffer{}, false)})
+	}()
+
+	// Give the follower time to open the log at its end.
+	time.Sleep(500 * time.Millisecond)
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("[15:00:01] [Server thread/INFO]: <Steve> !players\n" +
+		"[15:00:02] [Server thread/INFO]: <Alex> !time\n" +
+		"[15:00:03] [Server thread/INFO]: <Alex> !backup\n")
+	_ = f.Close()
+
+	want := []string{
+		`tellraw Steve ["",{"text":"2/20 online: Steve, Alex","color":"yellow"}]`,
+		` here and 12:00 on day 3 in game","color":"yellow"}]`,
+		`tellraw Alex ["",{"text":"You don't have permission to use !backup","color":"red"}]`,
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		sent := mgr.Commands()
+		missing := slices.DeleteFunc(slices.Clone(want), func(w string) bool {
+			return slices.ContainsFunc(sent, func(c string) bool { return strings.Contains(c, w) })
+		})
+		if len(missing) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("replies %q not sent; got %q", missing, sent)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package chatcmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigFile configures chat commands in the server directory.
+const ConfigFile = "chat-commands.json"
+
+// configFile is the on-disk form of ConfigFile:
+//
+//	{
+//	  "prefix": "!",
+//	  "commands": {
+//	    "restart": {"allow": ["Mum"]},
+//	    "time": {"enabled": false},
+//	    "day": {"run": ["time set day"], "reply": "Good morning!", "cooldown": "5m"}
+//	  }
+//	}
+type configFile struct {
+	Prefix   string                 `json:"prefix,omitempty"`
+	Commands map[string]commandFile `json:"commands,omitempty"`
+}
+
+// commandFile configures one command. For a built-in command it overrides
+// the defaults; with Run or Reply set it defines a custom command, which
+// replaces any built-in of the same name.
+type commandFile struct {
+	Enabled        *bool      `json:"enabled,omitempty"`
+	Permission     Permission `json:"permission,omitempty"`
+	Allow          []string   `json:"allow,omitempty"`
+	Aliases        []string   `json:"aliases,omitempty"`
+	Cooldown       string     `json:"cooldown,omitempty"`
+	GlobalCooldown string     `json:"global_cooldown,omitempty"`
+	Usage          string     `json:"usage,omitempty"`
+	Help           string     `json:"help,omitempty"`
+	// Run are console commands. "{player}" is replaced by the caller's
+	// name, "{1}" to "{9}" by their arguments and "{args}" by all of them.
+	Run []string `json:"run,omitempty"`
+	// Reply is sent to the caller, with the same placeholders.
+	Reply string `json:"reply,omitempty"`
+}
+
+// commandName restricts command names and aliases to what players can
+// type easily.
+var commandName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Load returns a router for the built-in commands as configured by
+// serverDir's chat-commands.json, plus its custom commands. A missing file
+// leaves the built-ins as they are.
+func Load(serverDir string, builtin []Command) (*Router, error) {
+	var cf configFile
+	data, err := os.ReadFile(filepath.Join(serverDir, ConfigFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading %s: %w", ConfigFile, err)
+	default:
+		if err := json.Unmarshal(data, &cf); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", ConfigFile, err)
+		}
+	}
+
+	prefix := cf.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	if strings.ContainsAny(prefix, " \t") {
+		return nil, fmt.Errorf("%s: prefix %q must not contain spaces", ConfigFile, prefix)
+	}
+
+	cmds := slices.Clone(builtin)
+	for _, name := range slices.Sorted(maps.Keys(cf.Commands)) {
+		f := cf.Commands[name]
+		i := slices.IndexFunc(cmds, func(c Command) bool { return strings.EqualFold(c.Name, name) })
+		if f.Run != nil || f.Reply != "" {
+			cmd, err := customCommand(name, &f)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", ConfigFile, err)
+			}
+			if i >= 0 {
+				cmds[i] = cmd
+			} else {
+				cmds = append(cmds, cmd)
+			}
+			i = slices.IndexFunc(cmds, func(c Command) bool { return c.Name == cmd.Name })
+		} else if i < 0 {
+			return nil, fmt.Errorf("%s: %q is not a built-in command; give it \"run\" commands or a \"reply\"", ConfigFile, name)
+		}
+		if f.Enabled != nil && !*f.Enabled {
+			cmds = slices.Delete(cmds, i, i+1)
+			continue
+		}
+		if err := f.apply(&cmds[i]); err != nil {
+			return nil, fmt.Errorf("%s: command %q: %w", ConfigFile, name, err)
+		}
+	}
+	return NewRouter(prefix, serverDir, cmds), nil
+}
+
+// apply overrides cmd's settings with those given in f.
+func (f *commandFile) apply(cmd *Command) error {
+	switch f.Permission {
+	case "":
+	case Everyone, Ops:
+		cmd.Permission = f.Permission
+	default:
+		return fmt.Errorf("unknown permission %q: use %q or %q", f.Permission, Everyone, Ops)
+	}
+	for _, alias := range f.Aliases {
+		if !commandName.MatchString(alias) {
+			return fmt.Errorf("alias %q: use letters, digits, '_' or '-'", alias)
+		}
+	}
+	if f.Aliases != nil {
+		cmd.Aliases = f.Aliases
+	}
+	if f.Allow != nil {
+		cmd.Allow = f.Allow
+	}
+	if f.Usage != "" {
+		cmd.Usage = f.Usage
+	}
+	if f.Help != "" {
+		cmd.Help = f.Help
+	}
+	var err error
+	if cmd.Cooldown, err = parseCooldown(f.Cooldown, cmd.Cooldown); err != nil {
+		return err
+	}
+	if cmd.GlobalCooldown, err = parseCooldown(f.GlobalCooldown, cmd.GlobalCooldown); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseCooldown parses a duration such as "90s" or "10m", returning def
+// for an empty string.
+func parseCooldown(s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("cooldown %q: use a duration such as 90s or 10m", s)
+	}
+	return d, nil
+}
+
+// placeholder matches the values substituted into custom commands.
+var placeholder = regexp.MustCompile(`\{(player|args|[1-9])\}`)
+
+// safeArg restricts arguments substituted into console commands to names,
+// numbers and coordinates, so a player can't slip in a selector such as
+// "@a" or a JSON component. A "^" only starts a local coordinate such as
+// "^" or "^2.5": screen reads "^" and the character after as a control
+// character, and "^M" or "^-" would end the command and start another.
+var safeArg = regexp.MustCompile(`^(?:[A-Za-z0-9_.~+-]{1,64}|\^(?:[0-9]{1,8}(?:\.[0-9]{1,8})?)?)$`)
+
+// customCommand builds a command that runs f's console commands. Custom
+// commands are for ops unless configured otherwise, and need as many
+// arguments as their placeholders use.
+func customCommand(name string, f *commandFile) (Command, error) {
+	if !commandName.MatchString(name) {
+		return Command{}, fmt.Errorf("command %q: use letters, digits, '_' or '-'", name)
+	}
+	cmd := Command{Name: name, Permission: Ops}
+	var usage []string
+	for _, s := range append(slices.Clone(f.Run), f.Reply) {
+		for _, m := range placeholder.FindAllStringSubmatch(s, -1) {
+			switch m[1] {
+			case "player":
+			case "args":
+				cmd.MinArgs = max(cmd.MinArgs, 1)
+			default:
+				n, _ := strconv.Atoi(m[1])
+				cmd.MinArgs = max(cmd.MinArgs, n)
+			}
+		}
+	}
+	for i := range cmd.MinArgs {
+		usage = append(usage, fmt.Sprintf("<%d>", i+1))
+	}
+	cmd.Usage = strings.Join(usage, " ")
+
+	run := make([]string, 0, len(f.Run))
+	for _, c := range f.Run {
+		// Commands are sent from the console, where the slash is optional.
+		if c = strings.TrimPrefix(strings.TrimSpace(c), "/"); c != "" {
+			run = append(run, c)
+		}
+	}
+	reply := f.Reply
+	cmd.Run = func(ctx context.Context, c *Call) error {
+		if len(run) > 0 {
+			for _, arg := range c.Args {
+				if !safeArg.MatchString(arg) {
+					return Refusal("Arguments can only be names, numbers and coordinates")
+				}
+			}
+		}
+		for _, line := range run {
+			line = expand(line, c)
+			if err := c.mgr.SendCommand(ctx, line); err != nil {
+				return fmt.Errorf("running %q: %w", line, err)
+			}
+		}
+		if reply != "" {
+			c.Reply(ctx, "%s", expand(reply, c))
+		}
+		return nil
+	}
+	return cmd, nil
+}
+
+// expand fills in the placeholders in s for call c.
+func expand(s string, c *Call) string {
+	return placeholder.ReplaceAllStringFunc(s, func(p string) string {
+		switch p = strings.Trim(p, "{}"); p {
+		case "player":
+			return c.Player
+		case "args":
+			return strings.Join(c.Args, " ")
+		}
+		n, _ := strconv.Atoi(p)
+		return c.Args[n-1]
+	})
+}
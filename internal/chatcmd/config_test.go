@@ -0,0 +1,134 @@
+package chatcmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, dir, data string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ConfigFile), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	builtin := Builtins(&Config{ServerDir: dir})
+
+	r, err := Load(dir, builtin)
+	if err != nil {
+		t.Fatalf("Load() without a file: %v", err)
+	}
+	if r.prefix != DefaultPrefix || len(r.commands) != len(builtin) {
+		t.Errorf("Load() without a file = %q with %d commands, want the built-ins", r.prefix, len(r.commands))
+	}
+
+	writeConfig(t, dir, `{
+  "prefix": "#",
+  "commands": {
+    "time": {"enabled": false},
+    "restart": {"allow": ["Mum"], "global_cooldown": "30m", "aliases": ["reboot"]},
+    "players": {"permission": "ops"},
+    "day": {"run": ["/time set day"], "reply": "Good morning, {player}!", "cooldown": "5m"},
+    "rules": {"reply": "Be kind. No griefing.", "permission": "everyone"}
+  }
+}`)
+	r, err = Load(dir, builtin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.prefix != "#" {
+		t.Errorf("prefix = %q, want #", r.prefix)
+	}
+	if r.byName["time"] != nil {
+		t.Error("disabled !time still routed")
+	}
+	restart := r.byName["reboot"]
+	if restart == nil || restart.Name != "restart" || !slices.Equal(restart.Allow, []string{"Mum"}) || restart.GlobalCooldown != 30*time.Minute {
+		t.Errorf("restart = %+v, want overridden allow, cooldown and alias", restart)
+	}
+	if p := r.byName["players"]; p.Permission != Ops || p.Cooldown != 10*time.Second {
+		t.Errorf("players = %+v, want ops with the default cooldown", p)
+	}
+	if day := r.byName["day"]; day == nil || day.Permission != Ops || day.Cooldown != 5*time.Minute || day.MinArgs != 0 {
+		t.Errorf("day = %+v, want an ops command with a 5m cooldown", day)
+	}
+	if rules := r.byName["rules"]; rules == nil || rules.Permission != Everyone {
+		t.Errorf("rules = %+v, want a command for everyone", rules)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name, data string
+	}{
+		{"bad JSON", `{`},
+		{"unknown built-in", `{"commands": {"fly": {"permission": "ops"}}}`},
+		{"bad permission", `{"commands": {"time": {"permission": "kids"}}}`},
+		{"bad cooldown", `{"commands": {"time": {"cooldown": "soon"}}}`},
+		{"bad name", `{"commands": {"go home": {"run": ["spawn"]}}}`},
+		{"bad alias", `{"commands": {"time": {"aliases": ["what time"]}}}`},
+		{"spaced prefix", `{"prefix": "! "}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfig(t, dir, tt.data)
+			if _, err := Load(dir, Builtins(&Config{ServerDir: dir})); err == nil {
+				t.Error("Load() = nil error")
+			}
+		})
+	}
+}
+
+func TestCustomCommand(t *testing.T) {
+	cmd, err := customCommand("tpto", &commandFile{
+		Run:   []string{"tp {player} {1}", "say {player} went to {1}: {args}"},
+		Reply: "Off you go",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.MinArgs != 1 || cmd.Usage != "<1>" {
+		t.Errorf("MinArgs, Usage = %d, %q; want 1, <1>", cmd.MinArgs, cmd.Usage)
+	}
+
+	mgr := newManager()
+	call := &Call{Player: "Steve", Args: []string{"Alex", "now"}, mgr: mgr}
+	if err := cmd.Run(t.Context(), call); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"tp Steve Alex",
+		"say Steve went to Alex: Alex now",
+		`tellraw Steve ["",{"text":"Off you go","color":"yellow"}]`,
+	}
+	if got := mgr.Commands(); !slices.Equal(got, want) {
+		t.Errorf("sent %q, want %q", got, want)
+	}
+
+	// Selectors, and anything screen would read as a line break, are
+	// refused without repeating them.
+	for _, args := range [][]string{{"@a"}, {"a^Mop", "Me^M"}, {"^-", "op", "Me"}, {"^m"}, {`a\015op`}} {
+		call.Args = args
+		var refusal Refusal
+		err := cmd.Run(t.Context(), call)
+		if !errors.As(err, &refusal) {
+			t.Errorf("Run(%q) = %v, want a refusal", args, err)
+		} else if strings.Contains(string(refusal), args[0]) {
+			t.Errorf("Run(%q) refusal %q repeats the argument", args, refusal)
+		}
+	}
+
+	// Local coordinates are fine.
+	call.Args = []string{"^", "^2", "^0.5"}
+	if err := cmd.Run(t.Context(), call); err != nil {
+		t.Errorf("Run(%q) = %v, want the commands run", call.Args, err)
+	}
+}
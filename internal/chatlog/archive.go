@@ -0,0 +1,213 @@
+// Package chatlog keeps an archive of game chat taken from the server logs,
+// including rotated ones, so parents can search and export what was said
+// after the logs themselves are gone.
+package chatlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/serverctl"
+)
+
+// ArchiveFile holds the archive in the server directory.
+const ArchiveFile = "chat-archive.json"
+
+// defaultWorld is the world name when server.properties doesn't give one.
+const defaultWorld = "world"
+
+// Message is one line of chat.
+type Message struct {
+	Time   time.Time `json:"time"`
+	Player string    `json:"player"`
+	// World is the server's world (level-name) when the message was
+	// archived.
+	World string `json:"world"`
+	Text  string `json:"text"`
+}
+
+// Archive is the on-disk form of ArchiveFile.
+type Archive struct {
+	// RetentionDays is how long messages are kept; zero keeps them
+	// forever.
+	RetentionDays int `json:"retention_days,omitempty"`
+	// Scanned names the rotated logs already archived.
+	Scanned  []string  `json:"scanned"`
+	Messages []Message `json:"messages"`
+}
+
+// Load brings the archive in serverDir up to date with the rotated logs
+// and returns every message, oldest first, including those in latest.log
+// that aren't archived yet. Messages older than the retention are dropped.
+func Load(serverDir string, now time.Time) ([]Message, error) {
+	a, err := LoadArchive(serverDir)
+	if err != nil {
+		return nil, err
+	}
+	parser, _ := events.LoadParser(serverDir)
+	world := serverctl.ReadProperty(serverDir, "level-name")
+	if world == "" {
+		world = defaultWorld
+	}
+
+	logs, err := events.RotatedLogs(serverDir)
+	if err != nil {
+		return nil, err
+	}
+	changed := false
+	for _, l := range logs {
+		if slices.Contains(a.Scanned, l.Name) {
+			continue
+		}
+		msgs, err := readChat(filepath.Join(serverDir, "logs", l.Name), l.Date, world, parser)
+		if err != nil {
+			return nil, err
+		}
+		a.Messages = append(a.Messages, msgs...)
+		a.Scanned = append(a.Scanned, l.Name)
+		changed = true
+	}
+	if a.prune(now) {
+		changed = true
+	}
+	if changed {
+		sortMessages(a.Messages)
+		if err := a.Save(serverDir); err != nil {
+			return nil, err
+		}
+	}
+
+	all := a.Messages
+	latest := filepath.Join(serverDir, "logs", "latest.log")
+	info, err := os.Stat(latest)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading latest.log: %w", err)
+	default:
+		msgs, err := readChat(latest, info.ModTime(), world, parser)
+		if err != nil {
+			return nil, err
+		}
+		all = slices.Concat(all, msgs)
+	}
+	return all, nil
+}
+
+// LoadArchive reads the archive from serverDir. A missing file is an empty
+// archive.
+func LoadArchive(serverDir string) (*Archive, error) {
+	a := &Archive{}
+	data, err := os.ReadFile(filepath.Join(serverDir, ArchiveFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading %s: %w", ArchiveFile, err)
+	default:
+		if err := json.Unmarshal(data, a); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", ArchiveFile, err)
+		}
+	}
+	return a, nil
+}
+
+// Save writes the archive to serverDir. It may hold what children said, so
+// only the owner can read it.
+func (a *Archive) Save(serverDir string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", ArchiveFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(serverDir, ArchiveFile), append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing %s: %w", ArchiveFile, err)
+	}
+	return nil
+}
+
+// prune drops messages older than the retention and reports whether any
+// were dropped.
+func (a *Archive) prune(now time.Time) bool {
+	if a.RetentionDays <= 0 {
+		return false
+	}
+	cutoff := now.AddDate(0, 0, -a.RetentionDays)
+	n := len(a.Messages)
+	a.Messages = slices.DeleteFunc(a.Messages, func(m Message) bool { return m.Time.Before(cutoff) })
+	return len(a.Messages) != n
+}
+
+// SetRetention keeps messages in serverDir's archive for days, or forever
+// if days is zero, dropping any older ones now.
+func SetRetention(serverDir string, days int, now time.Time) (dropped int, err error) {
+	if days < 0 {
+		return 0, fmt.Errorf("retention must not be negative, got %d days", days)
+	}
+	a, err := LoadArchive(serverDir)
+	if err != nil {
+		return 0, err
+	}
+	a.RetentionDays = days
+	n := len(a.Messages)
+	a.prune(now)
+	return n - len(a.Messages), a.Save(serverDir)
+}
+
+// readChat returns the chat in a log whose last line was logged on end's
+// date.
+func readChat(path string, end time.Time, world string, parser *events.Parser) ([]Message, error) {
+	lines, err := events.ReadLog(path, end)
+	if err != nil {
+		return nil, err
+	}
+	var msgs []Message
+	for _, line := range lines {
+		if line.Time.IsZero() {
+			continue
+		}
+		ev, ok := parser.Parse(line.Text, line.Time)
+		if !ok || ev.Kind != events.Chat {
+			continue
+		}
+		msgs = append(msgs, Message{Time: line.Time, Player: ev.Player, World: world, Text: ev.Message})
+	}
+	return msgs, nil
+}
+
+func sortMessages(msgs []Message) {
+	slices.SortStableFunc(msgs, func(a, b Message) int { return a.Time.Compare(b.Time) })
+}
+
+// Query selects messages. Empty fields match everything.
+type Query struct {
+	// Text is found anywhere in the message, ignoring case.
+	Text   string
+	Player string
+	World  string
+	Since  time.Time
+	Until  time.Time
+}
+
+// Search returns the messages matching q, oldest first.
+func Search(msgs []Message, q *Query) []Message {
+	text := strings.ToLower(q.Text)
+	var out []Message
+	for _, m := range msgs {
+		switch {
+		case !q.Since.IsZero() && m.Time.Before(q.Since),
+			!q.Until.IsZero() && !m.Time.Before(q.Until),
+			q.Player != "" && !strings.EqualFold(m.Player, q.Player),
+			q.World != "" && !strings.EqualFold(m.World, q.World),
+			text != "" && !strings.Contains(strings.ToLower(m.Text), text):
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
@@ -0,0 +1,148 @@
+package chatlog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeLog(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, "logs", name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var data bytes.Buffer
+	if strings.HasSuffix(name, ".gz") {
+		gz := gzip.NewWriter(&data)
+		_, _ = gz.Write([]byte(content))
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		data.WriteString(content)
+	}
+	if err := os.WriteFile(path, data.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func at(day, clock string) time.Time {
+	t, err := time.ParseInLocation(time.DateTime, day+" "+clock, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "server.properties"), []byte("level-name=survival\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeLog(t, dir, "2026-10-01-1.log.gz", `[18:00:00] [Server thread/INFO]: Steve joined the game
+[18:00:05] [Server thread/INFO]: <Steve> hi everyone
+`)
+	writeLog(t, dir, "2026-10-16-1.log.gz", `[19:00:00] [Async Chat Thread - #0/INFO]: <Alex> want to build a castle?
+[19:00:10] [Async Chat Thread - #0/INFO]: <Steve> yes!
+`)
+	writeLog(t, dir, "latest.log", `[09:00:00] [Server thread/INFO]: <Kid> good morning
+`)
+	mtime := at("2026-10-18", "09:00:00")
+	if err := os.Chtimes(filepath.Join(dir, "logs", "latest.log"), mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	now := at("2026-10-18", "12:00:00")
+
+	msgs, err := Load(dir, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Message{
+		{at("2026-10-01", "18:00:05"), "Steve", "survival", "hi everyone"},
+		{at("2026-10-16", "19:00:00"), "Alex", "survival", "want to build a castle?"},
+		{at("2026-10-16", "19:00:10"), "Steve", "survival", "yes!"},
+		{at("2026-10-18", "09:00:00"), "Kid", "survival", "good morning"},
+	}
+	checkMessages(t, "first load", msgs, want)
+
+	// Archived chat outlives the logs, and latest.log isn't archived.
+	if err := os.RemoveAll(filepath.Join(dir, "logs")); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err = Load(dir, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMessages(t, "after deleting the logs", msgs, want[:3])
+
+	// A week's retention drops the oldest message.
+	dropped, err := SetRetention(dir, 7, now)
+	if err != nil || dropped != 1 {
+		t.Errorf("SetRetention() = %d, %v; want 1 dropped", dropped, err)
+	}
+	msgs, err = Load(dir, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMessages(t, "after setting retention", msgs, want[1:3])
+}
+
+func checkMessages(t *testing.T, label string, got, want []Message) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d messages %v, want %d", label, len(got), got, len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if !g.Time.Equal(w.Time) || g.Player != w.Player || g.World != w.World || g.Text != w.Text {
+			t.Errorf("%s: message %d = %+v, want %+v", label, i, g, w)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	msgs := []Message{
+		{at("2026-10-16", "19:00:00"), "Alex", "world", "Want to build a castle?"},
+		{at("2026-10-17", "19:00:10"), "Steve", "world", "castle time"},
+		{at("2026-10-18", "09:00:00"), "Steve", "creative", "good morning"},
+	}
+	tests := []struct {
+		name string
+		q    Query
+		want int
+	}{
+		{"everything", Query{}, 3},
+		{"text ignores case", Query{Text: "CASTLE"}, 2},
+		{"player", Query{Player: "steve"}, 2},
+		{"text and player", Query{Text: "castle", Player: "Steve"}, 1},
+		{"world", Query{World: "creative"}, 1},
+		{"since", Query{Since: at("2026-10-17", "00:00:00")}, 2},
+		{"until", Query{Until: at("2026-10-17", "00:00:00")}, 1},
+		{"nothing", Query{Text: "dragon"}, 0},
+	}
+	for _, tt := range tests {
+		if got := Search(msgs, &tt.q); len(got) != tt.want {
+			t.Errorf("%s: Search() = %d messages, want %d", tt.name, len(got), tt.want)
+		}
+	}
+}
+
+func TestWriteHTMLEscapes(t *testing.T) {
+	msgs := []Message{{at("2026-10-18", "09:00:00"), "Steve", "world", "<script>alert(1)</script>"}}
+	var b bytes.Buffer
+	if err := WriteHTML(&b, msgs, "Chat", at("2026-10-18", "12:00:00")); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if strings.Contains(out, "<script>") || !strings.Contains(out, "&lt;script&gt;") {
+		t.Errorf("WriteHTML() did not escape the message:\n%s", out)
+	}
+	if !strings.Contains(out, "Sunday, October 18, 2026") {
+		t.Errorf("WriteHTML() has no heading for the day:\n%s", out)
+	}
+}
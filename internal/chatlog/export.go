@@ -0,0 +1,78 @@
+package chatlog
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// WriteCSV writes msgs as CSV with a header row.
+func WriteCSV(w io.Writer, msgs []Message) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"time", "world", "player", "message"})
+	for _, m := range msgs {
+		_ = cw.Write([]string{m.Time.Format(time.RFC3339), m.World, m.Player, m.Text})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// transcriptDay is a day's messages in the HTML transcript.
+type transcriptDay struct {
+	Date     string
+	Messages []Message
+}
+
+var transcript = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+h1 { font-size: 1.4rem; }
+h2 { font-size: 1.1rem; margin-top: 2rem; border-bottom: 1px solid #ccc; }
+table { border-collapse: collapse; width: 100%; }
+td { padding: 0.2rem 0.5rem; vertical-align: top; }
+.time, .world { color: #777; white-space: nowrap; font-variant-numeric: tabular-nums; }
+.player { font-weight: bold; white-space: nowrap; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Count}} messages. Exported {{.Exported}}.</p>
+{{- range .Days}}
+<h2>{{.Date}}</h2>
+<table>
+{{- range .Messages}}
+<tr><td class="time">{{.Time.Format "15:04:05"}}</td><td class="world">{{.World}}</td><td class="player">{{.Player}}</td><td>{{.Text}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes msgs as a standalone HTML transcript, a table per day.
+func WriteHTML(w io.Writer, msgs []Message, title string, now time.Time) error {
+	var days []transcriptDay
+	for _, m := range msgs {
+		date := m.Time.Format("Monday, January 2, 2006")
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, transcriptDay{Date: date})
+		}
+		days[len(days)-1].Messages = append(days[len(days)-1].Messages, m)
+	}
+	err := transcript.Execute(w, map[string]any{
+		"Title":    title,
+		"Count":    len(msgs),
+		"Exported": now.Format("Mon Jan 2 2006 15:04"),
+		"Days":     days,
+	})
+	if err != nil {
+		return fmt.Errorf("writing transcript: %w", err)
+	}
+	return nil
+}
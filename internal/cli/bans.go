@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/moderation"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/players"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// BanCmd bans a player, for a while or for good.
+type BanCmd struct {
+	Player string `arg:"" help:"Player to ban"`
+	For    string `help:"How long the ban lasts, such as 30m, 2h or 7d (default: for good)" default:"" name:"for"`
+	Reason string `help:"Reason shown to the player" default:""`
+}
+
+// Run bans the player through the server, or in banned-players.json if it
+// is stopped. Timed bans made through the server are lifted by the daemon
+// when they run out, so Run warns if none is running.
+func (cmd *BanCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
+	var d time.Duration
+	if cmd.For != "" {
+		var err error
+		if d, err = moderation.ParseDuration(cmd.For); err != nil {
+			return fmt.Errorf("--for: %w", err)
+		}
+	}
+	ctx := context.Background()
+	cfg := globalsToConfig(globals)
+	res := resolveManager(ctx, globals, runner, output)
+	defer func() { _ = res.Close() }()
+
+	b := &moderation.Banner{
+		ServerDir: cfg.Dir,
+		Manager:   res.Manager,
+		Running:   management.IsServerRunning(ctx, res.Manager, runner, cfg.Port),
+		Resolver:  players.NewResolver(cfg.Dir, players.NewWebLookup()),
+	}
+	until, err := b.Ban(ctx, cmd.Player, cmd.Reason, d, time.Now())
+	if err != nil {
+		return err
+	}
+	if until.IsZero() {
+		output.Success("Banned %s", cmd.Player)
+		return nil
+	}
+	output.Success("Banned %s for %s, until %s", cmd.Player, moderation.FormatDuration(d), until.Local().Format("Mon Jan 2 15:04"))
+	switch {
+	case !b.Running:
+		output.Info("The server lifts the ban then; see what's left with: mc-dad-server bans")
+	case moderation.LiftingBans(cfg.Dir, time.Now()):
+		output.Info("The daemon lifts the ban then; see what's left with: mc-dad-server bans")
+	default:
+		output.Warn("No daemon is running to lift the ban then; start one with: mc-dad-server daemon")
+	}
+	return nil
+}
+
+// BansCmd shows who is banned.
+type BansCmd struct {
+	List BansListCmd `cmd:"" default:"1" help:"Show banned players and how long their bans have left"`
+}
+
+// BansListCmd lists the bans.
+type BansListCmd struct{}
+
+// Run prints the timed bans in the ledger, soonest lifted first, then the
+// other bans in banned-players.json.
+func (cmd *BansListCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	ledger, err := moderation.LoadLedger(globals.Dir)
+	if err != nil {
+		return err
+	}
+	banned, err := players.LoadBannedPlayers(globals.Dir)
+	if err != nil {
+		return err
+	}
+	timed := slices.SortedFunc(maps.Values(ledger.Bans), func(a, b moderation.Ban) int { return a.Until.Compare(b.Until) })
+	var others players.BannedPlayers
+	for _, p := range banned {
+		if _, ok := ledger.Bans[strings.ToLower(p.Name)]; !ok {
+			others = append(others, p)
+		}
+	}
+	if len(timed) == 0 && len(others) == 0 {
+		output.Info("No one is banned")
+		return nil
+	}
+
+	width := len("Player")
+	for _, b := range timed {
+		width = max(width, len(b.Player))
+	}
+	for _, p := range others {
+		width = max(width, len(p.Name))
+	}
+	now := time.Now()
+	output.Step("Bans (%d)", len(timed)+len(others))
+	output.Info("%-*s  %-28s  %s", width, "Player", "Lifted", "Reason")
+	for _, b := range timed {
+		left := "once the server is up"
+		if d := b.Until.Sub(now); d > 0 {
+			left = fmt.Sprintf("in %s (%s)", formatLeft(d), b.Until.Local().Format("Mon Jan 2 15:04"))
+		}
+		output.Info("%-*s  %-28s  %s", width, b.Player, left, b.Reason)
+	}
+	for _, p := range others {
+		lifted := "never"
+		if until, err := time.Parse(players.BanTimeFormat, p.Expires); err == nil {
+			lifted = "by the server " + until.Local().Format("Mon Jan 2 15:04")
+		}
+		output.Info("%-*s  %-28s  %s", width, p.Name, lifted, p.Reason)
+	}
+	return nil
+}
+
+// formatLeft writes how long a ban has left, rounded up to the minute:
+// "45m", "1h05m", "2d3h".
+func formatLeft(d time.Duration) string {
+	d = (d + time.Minute - 1).Truncate(time.Minute)
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+	return formatPlaytime(d)
+}
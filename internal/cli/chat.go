@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/chatlog"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/sessions"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+	"github.com/alecthomas/kong"
+)
+
+// ChatCmd searches and exports the chat archive.
+type ChatCmd struct {
+	Search    ChatSearchCmd    `cmd:"" default:"withargs" help:"Search what was said in chat"`
+	Export    ChatExportCmd    `cmd:"" help:"Export a chat transcript as HTML or CSV"`
+	Retention ChatRetentionCmd `cmd:"" help:"Show or set how long chat is kept"`
+}
+
+// chatFilter selects messages; it is shared by search and export.
+type chatFilter struct {
+	Player string `help:"Only this player's messages" default:""`
+	World  string `help:"Only messages in this world" default:""`
+	Since  string `help:"Start: days (7d), weeks (2w), a duration (12h) or a date (2026-10-01)" default:"30d"`
+}
+
+// messages loads the archive and returns the messages matching f and text.
+func (f *chatFilter) messages(dir, text string) ([]chatlog.Message, time.Time, error) {
+	now := time.Now()
+	since, err := sessions.ParseSince(f.Since, now)
+	if err != nil {
+		return nil, since, err
+	}
+	all, err := chatlog.Load(dir, now)
+	if err != nil {
+		return nil, since, err
+	}
+	return chatlog.Search(all, &chatlog.Query{Text: text, Player: f.Player, World: f.World, Since: since}), since, nil
+}
+
+// ChatSearchCmd finds chat messages.
+type ChatSearchCmd struct {
+	Text string `arg:"" optional:"" help:"Text to find, ignoring case (default: every message)"`
+	chatFilter
+}
+
+// Run prints the matching messages, oldest first.
+func (cmd *ChatSearchCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	msgs, since, err := cmd.messages(globals.Dir, cmd.Text)
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		output.Info("No chat found since %s", since.Format("Mon Jan 2 15:04"))
+		return nil
+	}
+	width := 0
+	for i := range msgs {
+		width = max(width, len(msgs[i].Player))
+	}
+	output.Step("Chat since %s (%d messages)", since.Format("Mon Jan 2 15:04"), len(msgs))
+	for i := range msgs {
+		m := &msgs[i]
+		output.Info("%s  %-*s  %s", m.Time.Local().Format("2006-01-02 15:04"), width, m.Player, m.Text)
+	}
+	return nil
+}
+
+// ChatExportCmd writes a chat transcript.
+type ChatExportCmd struct {
+	Format string `help:"Transcript format" enum:"html,csv" default:"html"`
+	Output string `help:"File to write (default: standard output)" short:"o" default:""`
+	Text   string `help:"Only messages containing this text" default:""`
+	chatFilter
+}
+
+// Run writes the matching messages as HTML or CSV.
+func (cmd *ChatExportCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI, kctx *kong.Context) error {
+	msgs, since, err := cmd.messages(globals.Dir, cmd.Text)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = kctx.Stdout
+	if cmd.Output != "" {
+		f, err := os.OpenFile(cmd.Output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", cmd.Output, err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	switch cmd.Format {
+	case "csv":
+		err = chatlog.WriteCSV(w, msgs)
+	default:
+		title := "Chat since " + since.Format("Monday, January 2, 2006")
+		if cmd.Player != "" {
+			title = cmd.Player + "'s chat since " + since.Format("Monday, January 2, 2006")
+		}
+		err = chatlog.WriteHTML(w, msgs, title, time.Now())
+	}
+	if err != nil {
+		return err
+	}
+	if cmd.Output != "" {
+		output.Success("Wrote %d messages to %s", len(msgs), cmd.Output)
+	}
+	return nil
+}
+
+// ChatRetentionCmd shows or sets how long the archive keeps chat.
+type ChatRetentionCmd struct {
+	Days *int `arg:"" optional:"" help:"Days to keep chat; 0 keeps it forever"`
+}
+
+// Run prints the retention, or sets it and drops older messages.
+func (cmd *ChatRetentionCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	if cmd.Days == nil {
+		a, err := chatlog.LoadArchive(globals.Dir)
+		if err != nil {
+			return err
+		}
+		if a.RetentionDays == 0 {
+			output.Info("Chat is kept forever (%d messages archived)", len(a.Messages))
+		} else {
+			output.Info("Chat is kept for %d days (%d messages archived)", a.RetentionDays, len(a.Messages))
+		}
+		return nil
+	}
+	dropped, err := chatlog.SetRetention(globals.Dir, *cmd.Days, time.Now())
+	if err != nil {
+		return err
+	}
+	if *cmd.Days == 0 {
+		output.Success("Chat will be kept forever")
+	} else {
+		output.Success("Chat will be kept for %d days (%d older messages removed)", *cmd.Days, dropped)
+	}
+	return nil
+}
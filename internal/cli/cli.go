@@ -36,9 +36,21 @@ type CLI struct {
 	Status            StatusCmd            `cmd:"" help:"Show server status and resource usage"`
 	Backup            BackupCmd            `cmd:"" help:"Backup world data with rotation"`
 	Console           ConsoleCmd           `cmd:"" help:"Interactive console with live server log"`
+	Daemon            DaemonCmd            `cmd:"" help:"Run background services (metrics, idle shutdown, LAN discovery)"`
 	SetupParkour      SetupParkourCmd      `cmd:"setup-parkour" help:"Set up parkour world (first-time setup)"`
 	RotateParkour     RotateParkourCmd     `cmd:"rotate-parkour" help:"Rotate the featured parkour map"`
 	VoteMap           VoteMapCmd           `cmd:"vote-map" help:"Start a map vote (CS:GO style)"`
+	Maps              MapsCmd              `cmd:"" help:"Manage the map pool for votes and rotation"`
+	Poll              PollCmd              `cmd:"" help:"Ask players a question and run the winning option's commands"`
+	Whitelist         WhitelistCmd         `cmd:"" help:"Add, remove and list whitelisted players, with or without the server running"`
+	Players           PlayersCmd           `cmd:"" help:"Show who is online and when everyone was last seen"`
+	Playtime          PlaytimeCmd          `cmd:"" help:"Report how long each player played"`
+	Chat              ChatCmd              `cmd:"" help:"Search and export the chat archive"`
+	Moderation        ModerationCmd        `cmd:"" help:"Review the chat filter's log and strikes, and forgive players"`
+	Ban               BanCmd               `cmd:"" help:"Ban a player, for a while (--for 2h) or for good"`
+	Bans              BansCmd              `cmd:"" help:"Show banned players and how long their bans have left"`
+	Parental          ParentalCmd          `cmd:"" help:"Show, override and report on parental playtime rules"`
+	Notify            NotifyCmd            `cmd:"" help:"Show and test the Discord, Slack and JSON webhooks"`
 	ValidateLicense   ValidateLicenseCmd   `cmd:"validate-license" help:"Validate your license key"`
 	ActivateLicense   ActivateLicenseCmd   `cmd:"activate-license" help:"Activate a license key for this server"`
 	DeactivateLicense DeactivateLicenseCmd `cmd:"deactivate-license" help:"Deactivate the license for this server"`
@@ -3,10 +3,14 @@ package cli
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/KevinTCoughlin/mc-dad-server/internal/config"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/mappool"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/nag"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/serverctl"
@@ -19,7 +23,7 @@ type StartCmd struct{}
 
 // Run starts the server.
 func (cmd *StartCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
-	ctx := context.Background()
+	ctx := withNotifier(context.Background(), globals.Dir, output)
 	cfg := globalsToConfig(globals)
 	res := resolveManager(ctx, globals, runner, output)
 	defer func() { _ = res.Close() }()
@@ -54,7 +58,7 @@ type StopCmd struct{}
 
 // Run stops the server.
 func (cmd *StopCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
-	ctx := context.Background()
+	ctx := withNotifier(context.Background(), globals.Dir, output)
 	cfg := globalsToConfig(globals)
 	res := resolveManager(ctx, globals, runner, output)
 	defer func() { _ = res.Close() }()
@@ -127,7 +131,7 @@ type BackupCmd struct{}
 
 // Run performs a backup.
 func (cmd *BackupCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
-	ctx := context.Background()
+	ctx := withNotifier(context.Background(), globals.Dir, output)
 	cfg := globalsToConfig(globals)
 	res := resolveManager(ctx, globals, runner, output)
 	defer func() { _ = res.Close() }()
@@ -215,14 +219,24 @@ func (cmd *RotateParkourCmd) Run(globals *Globals, runner platform.CommandRunner
 	return management.RotateParkour(ctx, cfg.Dir, mgr, output)
 }
 
-// VoteMapCmd starts a map vote (CS:GO style).
+// VoteMapCmd runs map votes and reports on past ones.
 type VoteMapCmd struct {
-	Duration int `help:"Vote duration in seconds" default:"30"`
-	Choices  int `help:"Number of maps to vote on" default:"5" name:"choices"`
+	Start   VoteMapStartCmd   `cmd:"" default:"withargs" help:"Start a map vote (CS:GO style)"`
+	History VoteMapHistoryCmd `cmd:"" help:"Show recent votes"`
+	Stats   VoteMapStatsCmd   `cmd:"" help:"Show plays and win rates per map"`
+}
+
+// VoteMapStartCmd starts a map vote.
+type VoteMapStartCmd struct {
+	Duration      int    `help:"Vote duration in seconds" default:"30"`
+	Choices       int    `help:"Number of maps to vote on" default:"5" name:"choices"`
+	ExcludeRecent int    `help:"Leave out the winners of this many recent votes" default:"2"`
+	Tally         string `help:"How votes are counted: plurality, runoff (ranked), approval, or weighted (ops count extra)" enum:"plurality,runoff,approval,weighted" default:"plurality"`
+	OpWeight      int    `help:"Votes an operator's ballot counts as with --tally weighted" default:"2"`
 }
 
 // Run starts a map vote.
-func (cmd *VoteMapCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
+func (cmd *VoteMapStartCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
 	ctx := context.Background()
 	cfg := globalsToConfig(globals)
 	res := resolveManager(ctx, globals, runner, output)
@@ -234,12 +248,14 @@ func (cmd *VoteMapCmd) Run(globals *Globals, runner platform.CommandRunner, outp
 	}
 
 	result, err := vote.RunVote(ctx, &vote.Config{
-		Maps:       management.ParkourMaps,
-		Duration:   time.Duration(cmd.Duration) * time.Second,
-		MaxChoices: cmd.Choices,
-		ServerDir:  cfg.Dir,
-		Manager:    mgr,
-		Output:     output,
+		Duration:      time.Duration(cmd.Duration) * time.Second,
+		MaxChoices:    cmd.Choices,
+		ExcludeRecent: cmd.ExcludeRecent,
+		Mode:          vote.Mode(cmd.Tally),
+		OpWeight:      cmd.OpWeight,
+		ServerDir:     cfg.Dir,
+		Manager:       mgr,
+		Output:        output,
 	})
 	if err != nil {
 		return err
@@ -249,6 +265,140 @@ func (cmd *VoteMapCmd) Run(globals *Globals, runner platform.CommandRunner, outp
 	return nil
 }
 
+// PollCmd asks players a question and runs the winning option's commands.
+type PollCmd struct {
+	Question string   `arg:"" help:"Question to ask, e.g. \"Day or night?\""`
+	Option   []string `help:"Answer as Label=command, with several commands separated by ';' (repeatable)" required:"" sep:"none" placeholder:"LABEL=COMMANDS"`
+	Duration int      `help:"Poll duration in seconds" default:"30"`
+	Tally    string   `help:"How votes are counted: plurality, runoff (ranked), approval, or weighted (ops count extra)" enum:"plurality,runoff,approval,weighted" default:"plurality"`
+	OpWeight int      `help:"Votes an operator's ballot counts as with --tally weighted" default:"2"`
+}
+
+// Run holds the poll.
+func (cmd *PollCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
+	options := make([]vote.Option, len(cmd.Option))
+	for i, s := range cmd.Option {
+		opt, err := vote.ParseOption(s)
+		if err != nil {
+			return err
+		}
+		options[i] = opt
+	}
+
+	ctx := context.Background()
+	cfg := globalsToConfig(globals)
+	res := resolveManager(ctx, globals, runner, output)
+	defer func() { _ = res.Close() }()
+	mgr := res.Manager
+
+	if !management.IsServerRunning(ctx, mgr, runner, cfg.Port) {
+		return fmt.Errorf("server not running — start it first with: mc-dad-server start")
+	}
+
+	result, err := vote.RunPoll(ctx, &vote.PollConfig{
+		Question:  cmd.Question,
+		Options:   options,
+		Duration:  time.Duration(cmd.Duration) * time.Second,
+		Mode:      vote.Mode(cmd.Tally),
+		OpWeight:  cmd.OpWeight,
+		ServerDir: cfg.Dir,
+		Manager:   mgr,
+		Output:    output,
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.Winner == "" {
+		output.Info("No one voted, so nothing was run")
+		return nil
+	}
+	output.Success("Poll complete: %s (%d voters)", result.Winner, result.Voters)
+	return nil
+}
+
+// VoteMapHistoryCmd lists recent votes.
+type VoteMapHistoryCmd struct {
+	Limit   int  `help:"Number of votes to show" default:"10"`
+	Ballots bool `help:"Show who voted for what"`
+}
+
+// Run prints the most recent votes, newest first.
+func (cmd *VoteMapHistoryCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	records, err := vote.LoadHistory(globals.Dir)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		output.Info("No votes yet. Start one with: mc-dad-server vote-map")
+		return nil
+	}
+
+	output.Step("Recent Votes")
+	for i := len(records) - 1; i >= max(len(records)-cmd.Limit, 0); i-- {
+		rec := &records[i]
+		mode := ""
+		if rec.Mode != "" {
+			mode = ", " + string(rec.Mode)
+		}
+		output.Info("%s  %s (%d voters%s)", rec.Time.Local().Format("2006-01-02 15:04"), output.Bold(rec.Winner), rec.Voters, mode)
+		counts := make([]string, len(rec.Candidates))
+		for j, c := range rec.Candidates {
+			counts[j] = fmt.Sprintf("%s %d", c, rec.Votes[c])
+		}
+		output.Info("    %s", strings.Join(counts, ", "))
+		if cmd.Ballots {
+			for _, player := range slices.Sorted(maps.Keys(rec.Ballots)) {
+				choice := rec.Ballots[player]
+				if ranked, ok := rec.Choices[player]; ok {
+					choice = strings.Join(ranked, ", ")
+				}
+				output.Info("    %s -> %s", player, choice)
+			}
+		}
+	}
+	return nil
+}
+
+// VoteMapStatsCmd summarises the vote history per map.
+type VoteMapStatsCmd struct{}
+
+// Run prints plays, votes and win rates for every map that has been in a
+// vote or is in the pool.
+func (cmd *VoteMapStatsCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	records, err := vote.LoadHistory(globals.Dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	if pool, err := mappool.Load(globals.Dir); err == nil {
+		for i := range pool {
+			names = append(names, pool[i].Name)
+		}
+	}
+	stats := vote.Stats(records, names)
+	if len(stats) == 0 {
+		output.Info("No votes yet and no maps in the pool")
+		return nil
+	}
+
+	width := len("Map")
+	for i := range stats {
+		width = max(width, len(stats[i].Name))
+	}
+	output.Step("Map Stats (%d votes)", len(records))
+	output.Info("%-*s  %5s  %7s  %5s  %8s  %s", width, "Map", "Plays", "Offered", "Votes", "Win rate", "Last played")
+	for i := range stats {
+		s := &stats[i]
+		last := "never"
+		if !s.LastPlayed.IsZero() {
+			last = s.LastPlayed.Local().Format("2006-01-02 15:04")
+		}
+		output.Info("%-*s  %5d  %7d  %5d  %7.0f%%  %s", width, s.Name, s.Wins, s.Offered, s.Votes, 100*s.WinRate(), last)
+	}
+	return nil
+}
+
 // target builds a serverctl.Target from the global flags.
 func target(g *Globals) serverctl.Target {
 	return serverctl.Target{Mode: g.Mode, Dir: g.Dir, Session: g.Session}
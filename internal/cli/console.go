@@ -1,8 +1,11 @@
 package cli
 
 import (
+	"io"
+
 	"github.com/KevinTCoughlin/mc-dad-server/internal/console"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
 )
 
 // ConsoleCmd opens an interactive console with live server log.
@@ -10,9 +13,14 @@ type ConsoleCmd struct{}
 
 // Run starts the interactive console TUI.
 func (cmd *ConsoleCmd) Run(globals *Globals, runner platform.CommandRunner) error {
-	return console.Run(&console.Options{
+	opts := &console.Options{
 		Dir:     globals.Dir,
 		Session: globals.Session,
 		Mode:    globals.Mode,
-	}, runner)
+	}
+	// Delivery warnings would scribble over the TUI, so they're dropped.
+	if n := loadNotifier(globals.Dir, ui.NewWriter(io.Discard, false)); n != nil {
+		opts.Notifier = n
+	}
+	return console.Run(opts, runner)
 }
@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/bridge"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/chatcmd"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/daemon"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/idle"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/lan"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/metrics"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/moderation"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/notify"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/parental"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/vote"
+)
+
+// DaemonCmd runs background services alongside the server.
+type DaemonCmd struct {
+	Metrics       bool   `help:"Serve Prometheus/OpenMetrics metrics" default:"true" negatable:""`
+	MetricsListen string `help:"Metrics listen address" default:"127.0.0.1:9225" name:"metrics-listen"`
+
+	IdleTimeout time.Duration `help:"Stop the server after this long with no players, then wake it when someone joins (0 disables)" default:"0s" name:"idle-timeout"`
+	WakeMOTD    string        `help:"Server list MOTD while the server is asleep" default:"Sleeping — join to wake" name:"wake-motd"`
+	WakeMessage string        `help:"Message shown to the player whose join wakes the server" default:"Server is starting — rejoin in 60 seconds" name:"wake-message"`
+
+	LAN          bool   `help:"Announce the server under LAN Worlds on the local network" default:"false" name:"lan"`
+	LANInterface string `help:"Network interface for LAN announcements (default: system route)" default:"" name:"lan-interface"`
+
+	ChatCommands bool `help:"Run !help, !players, !backup and other chat commands (see chat-commands.json)" default:"false" name:"chat-commands"`
+
+	ChatFilter bool `help:"Filter chat against blocked-words.txt with escalating warnings, kicks and temporary bans" default:"false" name:"chat-filter"`
+
+	Bans bool `help:"Lift timed bans from the ban command and the chat filter when they run out" default:"true" negatable:""`
+
+	Parental bool `help:"Enforce the playtime rules in parental.json: warn, kick and take players off the whitelist" default:"false" name:"parental"`
+
+	Requests bool `help:"Queue players the whitelist turns away for whitelist approve, announcing each to any webhooks in notifications.json" default:"true" negatable:""`
+
+	Notify bool `help:"Send joins, leaves, crashes and license expiry to the webhooks in notifications.json" default:"false" name:"notify"`
+
+	Bridge bool `help:"Bridge game chat to a webhook and let bridge.json's token holders post messages into the game" default:"false" name:"bridge"`
+
+	RTV          bool          `help:"Let players start map votes with !rtv and nominate maps with !nominate" default:"false" name:"rtv"`
+	RTVThreshold float64       `help:"Fraction of online players who must type !rtv to start a vote" default:"0.6" name:"rtv-threshold"`
+	RTVCooldown  time.Duration `help:"Least time between player-started votes" default:"10m" name:"rtv-cooldown"`
+}
+
+// Run starts the enabled services and blocks until interrupted.
+func (cmd *DaemonCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg := globalsToConfig(globals)
+	res := resolveManager(ctx, globals, runner, output)
+	defer func() { _ = res.Close() }()
+	mgr := res.Manager
+
+	// Starts, stops and backups made by the services below are announced
+	// whenever webhooks are set up; --notify adds what only the log shows.
+	notifier := loadNotifier(cfg.Dir, output)
+	if notifier != nil {
+		ctx = management.WithNotifier(ctx, notifier)
+	}
+
+	var services []daemon.Service
+
+	if cmd.Metrics {
+		if !metrics.IsLoopback(cmd.MetricsListen) {
+			output.Warn("Metrics listen address %s is not loopback — anyone who can reach it can scrape the server", cmd.MetricsListen)
+		}
+		collector := metrics.NewCollector(mgr, runner, cfg.Dir, cfg.Port)
+		services = append(services, daemon.Service{
+			Name: "metrics on http://" + cmd.MetricsListen + "/metrics",
+			Run: func(ctx context.Context) error {
+				return metrics.Serve(ctx, cmd.MetricsListen, collector)
+			},
+		})
+	}
+
+	if cmd.IdleTimeout > 0 {
+		idleCfg := &idle.Config{
+			Manager:     mgr,
+			Runner:      runner,
+			Port:        cfg.Port,
+			Timeout:     cmd.IdleTimeout,
+			MOTD:        cmd.WakeMOTD,
+			WakeMessage: cmd.WakeMessage,
+			MaxPlayers:  cfg.MaxPlayers,
+			Output:      output,
+		}
+		services = append(services, daemon.Service{
+			Name: fmt.Sprintf("idle shutdown after %s", cmd.IdleTimeout),
+			Run: func(ctx context.Context) error {
+				return idle.Run(ctx, idleCfg)
+			},
+		})
+	}
+
+	if cmd.LAN {
+		lanCfg := &lan.Config{
+			Manager:   mgr,
+			Runner:    runner,
+			Port:      cfg.Port,
+			MOTD:      cfg.MOTD,
+			Interface: cmd.LANInterface,
+			Output:    output,
+		}
+		services = append(services, daemon.Service{
+			Name: "LAN announcer to " + lan.MulticastAddr,
+			Run: func(ctx context.Context) error {
+				return lan.Run(ctx, lanCfg)
+			},
+		})
+	}
+
+	if cmd.ChatCommands {
+		chatCfg := &chatcmd.Config{
+			ServerDir:  cfg.Dir,
+			Manager:    mgr,
+			Runner:     runner,
+			Port:       cfg.Port,
+			MaxBackups: cfg.MaxBackups,
+			Output:     output,
+		}
+		services = append(services, daemon.Service{
+			Name: "chat commands",
+			Run: func(ctx context.Context) error {
+				return chatcmd.Run(ctx, chatCfg)
+			},
+		})
+	}
+
+	modCfg := &moderation.Config{
+		ServerDir: cfg.Dir,
+		Manager:   mgr,
+		Output:    output,
+	}
+	if cmd.ChatFilter {
+		services = append(services, daemon.Service{
+			Name: "chat filter",
+			Run: func(ctx context.Context) error {
+				return moderation.Run(ctx, modCfg)
+			},
+		})
+		if !cmd.Bans {
+			output.Warn("--no-bans: the chat filter's temporary bans won't be lifted")
+		}
+	}
+
+	if cmd.Bans {
+		services = append(services, daemon.Service{
+			Name: "timed bans",
+			Run: func(ctx context.Context) error {
+				return moderation.RunBans(ctx, modCfg)
+			},
+		})
+	}
+
+	if cmd.Parental {
+		parentalCfg := &parental.Config{
+			ServerDir: cfg.Dir,
+			Manager:   mgr,
+			Output:    output,
+		}
+		services = append(services, daemon.Service{
+			Name: "parental controls",
+			Run: func(ctx context.Context) error {
+				return parental.Run(ctx, parentalCfg)
+			},
+		})
+	}
+
+	if cmd.Requests {
+		requestsCfg := &notify.Config{
+			ServerDir: cfg.Dir,
+			Notifier:  notifier,
+			Output:    output,
+		}
+		services = append(services, daemon.Service{
+			Name: "join requests",
+			Run: func(ctx context.Context) error {
+				return notify.RunRequests(ctx, requestsCfg)
+			},
+		})
+	}
+
+	if cmd.Notify {
+		if notifier == nil {
+			return fmt.Errorf("--notify needs at least one webhook target in %s", notify.ConfigFile)
+		}
+		notifyCfg := &notify.Config{
+			ServerDir: cfg.Dir,
+			Manager:   mgr,
+			Runner:    runner,
+			Port:      cfg.Port,
+			Notifier:  notifier,
+			Output:    output,
+		}
+		services = append(services, daemon.Service{
+			Name: "notifications",
+			Run: func(ctx context.Context) error {
+				return notify.Run(ctx, notifyCfg)
+			},
+		})
+	}
+
+	if cmd.Bridge {
+		bridgeCfg := &bridge.Config{
+			ServerDir: cfg.Dir,
+			Manager:   mgr,
+			Output:    output,
+		}
+		services = append(services, daemon.Service{
+			Name: "chat bridge",
+			Run: func(ctx context.Context) error {
+				return bridge.Run(ctx, bridgeCfg)
+			},
+		})
+	}
+
+	if cmd.RTV {
+		if cmd.RTVThreshold <= 0 || cmd.RTVThreshold > 1 {
+			return fmt.Errorf("--rtv-threshold must be between 0 and 1, got %g", cmd.RTVThreshold)
+		}
+		rtvCfg := &vote.RTVConfig{
+			Vote: vote.Config{
+				Duration:      time.Duration(cfg.VoteDuration) * time.Second,
+				MaxChoices:    cfg.VoteChoices,
+				ExcludeRecent: cfg.VoteExcludeRecent,
+				ServerDir:     cfg.Dir,
+				Manager:       mgr,
+				Output:        output,
+			},
+			Threshold: cmd.RTVThreshold,
+			Cooldown:  cmd.RTVCooldown,
+		}
+		services = append(services, daemon.Service{
+			Name: fmt.Sprintf("rock the vote (%.0f%% of players)", 100*cmd.RTVThreshold),
+			Run: func(ctx context.Context) error {
+				return vote.RockTheVote(ctx, rtvCfg)
+			},
+		})
+	}
+
+	output.Info("mc-dad-server daemon running (%s mode) — Ctrl+C to stop", res.Mode)
+	return daemon.Run(ctx, output, services...)
+}
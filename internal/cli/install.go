@@ -34,30 +34,35 @@ type InstallCmd struct {
 	ChatFilter bool   `help:"Install chat filter plugin" default:"true" name:"chat-filter" negatable:""`
 	Playit     bool   `help:"Set up playit.gg tunnel" default:"true" negatable:""`
 	Bun        bool   `help:"[Experimental] Enable Bun scripting sidecar" default:"false" name:"experimental-bun"`
+	LAN        bool   `help:"Announce the server under LAN Worlds on the home network" default:"false" name:"lan-broadcast"`
+	LANIface   string `help:"Network interface for LAN announcements (default: system route)" default:"" name:"lan-interface"`
 	MCVersion  string `help:"Minecraft version" default:"latest" name:"mc-version"`
 }
 
 func (cmd *InstallCmd) toConfig(globals *Globals) *config.ServerConfig {
 	return &config.ServerConfig{
-		Edition:      cmd.Edition,
-		Dir:          globals.Dir,
-		Port:         cmd.Port,
-		Memory:       cmd.Memory,
-		ServerType:   cmd.Type,
-		MOTD:         cmd.MOTD,
-		MaxPlayers:   cmd.Players,
-		Difficulty:   cmd.Difficulty,
-		GameMode:     cmd.Gamemode,
-		GCType:       strings.ToLower(cmd.GC),
-		Whitelist:    cmd.Whitelist,
-		ChatFilter:   cmd.ChatFilter,
-		EnablePlayit: cmd.Playit,
-		EnableBun:    cmd.Bun,
-		Version:      cmd.MCVersion,
-		SessionName:  globals.Session,
-		MaxBackups:   5,
-		VoteDuration: 30,
-		VoteChoices:  5,
+		Edition:           cmd.Edition,
+		Dir:               globals.Dir,
+		Port:              cmd.Port,
+		Memory:            cmd.Memory,
+		ServerType:        cmd.Type,
+		MOTD:              cmd.MOTD,
+		MaxPlayers:        cmd.Players,
+		Difficulty:        cmd.Difficulty,
+		GameMode:          cmd.Gamemode,
+		GCType:            strings.ToLower(cmd.GC),
+		Whitelist:         cmd.Whitelist,
+		ChatFilter:        cmd.ChatFilter,
+		EnablePlayit:      cmd.Playit,
+		EnableBun:         cmd.Bun,
+		LANBroadcast:      cmd.LAN,
+		LANInterface:      cmd.LANIface,
+		Version:           cmd.MCVersion,
+		SessionName:       globals.Session,
+		MaxBackups:        5,
+		VoteDuration:      30,
+		VoteChoices:       5,
+		VoteExcludeRecent: 2,
 	}
 }
 
@@ -102,10 +107,16 @@ func (cmd *InstallCmd) Run(globals *Globals, runner platform.CommandRunner, outp
 	output.Success("Configs deployed with tuned PaperMC defaults")
 	output.Info("RCON password saved to server.properties (port 25575)")
 
-	// Chat filter
-	if cfg.ChatFilter && cfg.ServerType == "paper" {
-		if err := plugins.SetupChatFilter(deployer, cfg.Dir, output); err != nil {
+	// Chat filter: ChatSentry on Paper, the daemon's filter elsewhere.
+	if cfg.ChatFilter {
+		if cfg.ServerType == "paper" {
+			if err := plugins.SetupChatFilter(deployer, cfg.Dir, output); err != nil {
+				output.Warn("Chat filter setup failed: %v", err)
+			}
+		} else if err := deployer.DeployBlockedWords(cfg.Dir); err != nil {
 			output.Warn("Chat filter setup failed: %v", err)
+		} else {
+			output.Success("Blocked words list deployed — run the daemon with --chat-filter to use it")
 		}
 	}
 
@@ -181,6 +192,7 @@ func (cmd *InstallCmd) Run(globals *Globals, runner platform.CommandRunner, outp
 		ChatFilter:   cfg.ChatFilter,
 		PlayitSetup:  cfg.EnablePlayit,
 		BunEnabled:   cfg.EnableBun,
+		LANBroadcast: cfg.LANBroadcast,
 		LicenseLabel: nag.StatusLabel(nagInfo),
 		InitSystem:   plat.InitSystem,
 	})
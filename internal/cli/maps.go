@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/mappool"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// MapsCmd manages the map pool used by votes and rotation.
+type MapsCmd struct {
+	List   MapsListCmd   `cmd:"" default:"1" help:"List the map pool and check each map"`
+	Add    MapsAddCmd    `cmd:"" help:"Add a map to the pool"`
+	Remove MapsRemoveCmd `cmd:"" help:"Remove a map from the pool"`
+}
+
+// MapsListCmd lists the map pool.
+type MapsListCmd struct{}
+
+// Run lists each map with any problems that would keep it out of votes.
+func (cmd *MapsListCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	pool, err := mappool.Load(globals.Dir)
+	if err != nil {
+		return err
+	}
+	if len(pool) == 0 {
+		output.Info("No maps in the pool. Add one with: mc-dad-server maps add <world>")
+		return nil
+	}
+
+	output.Step("Map Pool")
+	for _, s := range mappool.Check(globals.Dir, pool) {
+		source := ""
+		if s.Discovered {
+			source = " (discovered)"
+		}
+		output.Info("  %s%s", output.Bold(s.Name), source)
+		if s.Label() != s.Name {
+			output.Info("    Display: %s", s.Label())
+		}
+		if s.World != s.Name {
+			output.Info("    World:   %s", s.World)
+		}
+		if s.Weight != 1 {
+			output.Info("    Weight:  %d", s.Weight)
+		}
+		if len(s.Tags) > 0 {
+			output.Info("    Tags:    %s", strings.Join(s.Tags, ", "))
+		}
+		if !s.OK() {
+			output.Warn("    %s", s.Problem())
+		}
+	}
+	return nil
+}
+
+// MapsAddCmd adds a map to the pool.
+type MapsAddCmd struct {
+	Name    string   `arg:"" help:"Map name, used in votes and history"`
+	Display string   `help:"Name shown to players" default:""`
+	World   string   `help:"World folder (default: the map name)" default:""`
+	Weight  int      `help:"Relative chance of being offered in a vote" default:"1"`
+	Tag     []string `help:"Tag such as a difficulty (repeatable)"`
+}
+
+// Run adds the map to maps.json and imports it into Multiverse when the
+// server is running.
+func (cmd *MapsAddCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
+	cfg, err := mappool.LoadConfig(globals.Dir)
+	if err != nil {
+		return err
+	}
+	m := mappool.Map{
+		Name:        cmd.Name,
+		DisplayName: cmd.Display,
+		World:       cmd.World,
+		Weight:      cmd.Weight,
+		Tags:        cmd.Tag,
+	}
+	if err := cfg.Add(m); err != nil {
+		return err
+	}
+
+	// The map just added is the last listed one, ahead of any
+	// discovered folders.
+	status := mappool.Check(globals.Dir, cfg.Pool(globals.Dir))[len(cfg.Maps)-1]
+	if status.Missing {
+		return fmt.Errorf("map %s: %s", m.Name, status.Problem())
+	}
+	if status.NotImported {
+		if err := importWorld(globals, runner, output, status.World); err != nil {
+			return err
+		}
+	}
+
+	if err := cfg.Save(globals.Dir); err != nil {
+		return err
+	}
+	output.Success("Added %s to the map pool", m.Name)
+	return nil
+}
+
+// importWorld imports world into Multiverse when the server is running, or
+// says how to once it is.
+func importWorld(globals *Globals, runner platform.CommandRunner, output *ui.UI, world string) error {
+	ctx := context.Background()
+	res := resolveManager(ctx, globals, runner, output)
+	defer func() { _ = res.Close() }()
+	if !management.IsServerRunning(ctx, res.Manager, runner, globalsToConfig(globals).Port) {
+		output.Warn("%s is not imported in Multiverse yet; once the server is up, run: mv import %s normal", world, world)
+		return nil
+	}
+	output.Info("Importing %s into Multiverse...", world)
+	if err := res.Manager.SendCommand(ctx, fmt.Sprintf("mv import %s normal", world)); err != nil {
+		return fmt.Errorf("importing %s: %w", world, err)
+	}
+	return nil
+}
+
+// MapsRemoveCmd removes a map from the pool.
+type MapsRemoveCmd struct {
+	Name string `arg:"" help:"Map name"`
+}
+
+// Run removes the map from maps.json, excluding its folder from discovery.
+// The world itself is left on disk.
+func (cmd *MapsRemoveCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	cfg, err := mappool.LoadConfig(globals.Dir)
+	if err != nil {
+		return err
+	}
+	if !cfg.Remove(globals.Dir, cmd.Name) {
+		return fmt.Errorf("no map %q in the pool", cmd.Name)
+	}
+	if err := cfg.Save(globals.Dir); err != nil {
+		return err
+	}
+	output.Success("Removed %s from the map pool (the world folder is untouched)", cmd.Name)
+	return nil
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/moderation"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/sessions"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// ModerationCmd reviews and undoes what the chat filter did.
+type ModerationCmd struct {
+	Log     ModerationLogCmd     `cmd:"" default:"1" help:"List blocked messages and the action taken"`
+	Strikes ModerationStrikesCmd `cmd:"" help:"Show players' strikes and temporary bans"`
+	Forgive ModerationForgiveCmd `cmd:"" help:"Clear a player's strikes and lift their ban"`
+}
+
+// ModerationLogCmd lists the moderation log.
+type ModerationLogCmd struct {
+	Since  string `help:"Start of the log: days (7d), weeks (2w), a duration (12h) or a date (2026-10-01)" default:"7d"`
+	Player string `help:"Only show this player" default:""`
+}
+
+// Run prints each moderation action since the start of the log.
+func (cmd *ModerationLogCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	since, err := sessions.ParseSince(cmd.Since, time.Now())
+	if err != nil {
+		return err
+	}
+	entries, err := moderation.LoadLog(globals.Dir)
+	if err != nil {
+		return err
+	}
+	var shown []moderation.Entry
+	for _, e := range entries {
+		if e.Time.Before(since) || (cmd.Player != "" && !strings.EqualFold(e.Player, cmd.Player)) {
+			continue
+		}
+		shown = append(shown, e)
+	}
+	if len(shown) == 0 {
+		output.Info("Nothing moderated since %s", since.Format("Mon Jan 2 15:04"))
+		return nil
+	}
+
+	width := len("Player")
+	for i := range shown {
+		width = max(width, len(shown[i].Player))
+	}
+	output.Step("Moderation since %s", since.Format("Mon Jan 2 15:04"))
+	output.Info("%-16s  %-*s  %-7s  %s", "Time", width, "Player", "Action", "Message")
+	for i := range shown {
+		e := &shown[i]
+		action := e.Action
+		if e.Action == moderation.ActionBan {
+			action += " " + e.Detail
+		}
+		detail := e.Message
+		if e.Strike > 0 {
+			detail = fmt.Sprintf("strike %d: %q", e.Strike, e.Message)
+		}
+		output.Info("%-16s  %-*s  %-7s  %s", e.Time.Local().Format("Mon Jan 2 15:04"), width, e.Player, action, detail)
+	}
+	return nil
+}
+
+// ModerationStrikesCmd shows the strike ledger.
+type ModerationStrikesCmd struct{}
+
+// Run prints each player with strikes that still count, and each ban.
+func (cmd *ModerationStrikesCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	policy, err := moderation.LoadPolicy(globals.Dir)
+	if err != nil {
+		return err
+	}
+	ledger, err := moderation.LoadLedger(globals.Dir)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	shown := false
+	for _, key := range slices.Sorted(maps.Keys(ledger.Strikes)) {
+		s := ledger.Strikes[key]
+		n := ledger.Active(s.Player, now, policy.Forget)
+		if n == 0 {
+			continue
+		}
+		if !shown {
+			output.Step("Strikes (each counts for %s)", moderation.FormatDuration(policy.Forget))
+			shown = true
+		}
+		output.Info("  %s: %d — next is %s", output.Bold(s.Player), n, policy.Step(n+1))
+	}
+	if len(ledger.Bans) > 0 {
+		output.Step("Temporary bans")
+		for _, key := range slices.Sorted(maps.Keys(ledger.Bans)) {
+			b := ledger.Bans[key]
+			output.Info("  %s: until %s", output.Bold(b.Player), b.Until.Local().Format("Mon Jan 2 15:04"))
+		}
+		shown = true
+	}
+	if !shown {
+		output.Info("No strikes or bans")
+	}
+	return nil
+}
+
+// ModerationForgiveCmd clears a player's strikes and lifts their ban.
+type ModerationForgiveCmd struct {
+	Player string `arg:"" help:"Player name"`
+}
+
+// Run clears the player's strikes. A ban is lifted now if the server is
+// running, or by the daemon once it is.
+func (cmd *ModerationForgiveCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
+	ledger, err := moderation.LoadLedger(globals.Dir)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	key := strings.ToLower(cmd.Player)
+	_, hadStrikes := ledger.Strikes[key]
+	ban, banned := ledger.Bans[key]
+	if !hadStrikes && !banned {
+		return fmt.Errorf("%s has no strikes or bans", cmd.Player)
+	}
+
+	lifted := false
+	if banned {
+		ctx := context.Background()
+		res := resolveManager(ctx, globals, runner, output)
+		defer func() { _ = res.Close() }()
+		lifted = management.IsServerRunning(ctx, res.Manager, runner, globalsToConfig(globals).Port) &&
+			res.Manager.SendCommand(ctx, "pardon "+ban.Player) == nil
+		if !lifted {
+			output.Warn("The server is not running; the daemon will lift %s's ban once it is", ban.Player)
+		}
+	}
+	err = moderation.UpdateLedger(globals.Dir, func(l *moderation.Ledger) error {
+		l.Forgive(cmd.Player)
+		// A ban made since it was read isn't forgiven.
+		if cur, ok := l.Bans[key]; banned && ok && cur.Until.Equal(ban.Until) {
+			if lifted {
+				delete(l.Bans, key)
+			} else {
+				// The daemon lifts it when the server is next up.
+				cur.Until = now
+				l.Bans[key] = cur
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := moderation.AppendLog(globals.Dir, &moderation.Entry{Time: now, Player: cmd.Player, Action: moderation.ActionForgive}); err != nil {
+		return err
+	}
+	output.Success("Forgave %s", cmd.Player)
+	return nil
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"strings"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/notify"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// NotifyCmd shows and tests the webhooks in notifications.json.
+type NotifyCmd struct {
+	List NotifyListCmd `cmd:"" default:"1" help:"Show the webhook targets and the events each hears about"`
+	Test NotifyTestCmd `cmd:"" help:"Send a sample notification"`
+}
+
+// loadNotifier returns the notifier for the targets in dir, or nil if there
+// are none. A broken config is reported rather than returned, so it never
+// stops the server starting or a backup running.
+func loadNotifier(dir string, output *ui.UI) *notify.Notifier {
+	s, err := notify.Load(dir)
+	if err != nil {
+		output.Warn("Notifications disabled: %s", err)
+		return nil
+	}
+	n, err := notify.New(s, output)
+	if err != nil {
+		output.Warn("Notifications disabled: %s", err)
+		return nil
+	}
+	if !n.Enabled() {
+		return nil
+	}
+	return n
+}
+
+// withNotifier returns ctx with the server's webhooks attached, so the
+// management lifecycle functions announce starts, stops and backups.
+func withNotifier(ctx context.Context, dir string, output *ui.UI) context.Context {
+	if n := loadNotifier(dir, output); n != nil {
+		return management.WithNotifier(ctx, n)
+	}
+	return ctx
+}
+
+// NotifyListCmd lists the webhook targets.
+type NotifyListCmd struct{}
+
+// Run prints each target and its subscriptions, without the secret URLs.
+func (cmd *NotifyListCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	s, err := notify.Load(globals.Dir)
+	if err != nil {
+		return err
+	}
+	if _, err := notify.New(s, output); err != nil {
+		return err
+	}
+	if len(s.Targets) == 0 {
+		output.Info("No webhooks set up — add targets to %s", notify.ConfigFile)
+		return nil
+	}
+	output.Step("Notifications (%d targets)", len(s.Targets))
+	for i := range s.Targets {
+		t := &s.Targets[i]
+		events := "every event but chat"
+		if len(t.Events) > 0 {
+			events = strings.Join(t.Events, ", ")
+		}
+		output.Info("  %-24s %-8s %s", t.Label(), t.Type, events)
+	}
+	return nil
+}
+
+// NotifyTestCmd sends a sample event.
+type NotifyTestCmd struct {
+	Event  string `arg:"" optional:"" help:"Event to send: start, stop, crash, join, leave, chat, join_request, backup, backup_failed or license_expiry" enum:"start,stop,crash,join,leave,chat,join_request,backup,backup_failed,license_expiry" default:"start"`
+	Player string `help:"Player named in join, leave, chat and join request messages" default:"Steve"`
+}
+
+// sampleDetails fill in the detail of test events.
+var sampleDetails = map[string]string{
+	notify.Chat:          "anyone want to build a castle?",
+	notify.Backup:        "world_20261018_030000.tar.gz (512.0 MB)",
+	notify.BackupFailed:  "no world directories found",
+	notify.LicenseExpiry: "expires in 7 days",
+}
+
+// Run sends the event to every target subscribed to it.
+func (cmd *NotifyTestCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	s, err := notify.Load(globals.Dir)
+	if err != nil {
+		return err
+	}
+	n, err := notify.New(s, output)
+	if err != nil {
+		return err
+	}
+	var labels []string
+	for i := range s.Targets {
+		if s.Targets[i].Wants(cmd.Event) {
+			labels = append(labels, s.Targets[i].Label())
+		}
+	}
+	if len(labels) == 0 {
+		output.Info("No webhook hears about %s events — check %s", cmd.Event, notify.ConfigFile)
+		return nil
+	}
+	ev := &notify.Event{Event: cmd.Event, Detail: sampleDetails[cmd.Event]}
+	if cmd.Event == notify.Join || cmd.Event == notify.Leave || cmd.Event == notify.Chat || cmd.Event == notify.JoinRequest {
+		ev.Player = cmd.Player
+	}
+	if err := n.Send(context.Background(), ev); err != nil {
+		return err
+	}
+	output.Success("Sent a %s notification to %s", cmd.Event, strings.Join(labels, ", "))
+	return nil
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/parental"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/sessions"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// ParentalCmd shows and overrides the playtime rules in parental.json.
+type ParentalCmd struct {
+	Status ParentalStatusCmd `cmd:"" default:"1" help:"Show each player's time today and whether they may play"`
+	Allow  ParentalAllowCmd  `cmd:"" help:"Let a player play regardless of their rules for a while"`
+	Extend ParentalExtendCmd `cmd:"" help:"Give a player extra time today"`
+	Revoke ParentalRevokeCmd `cmd:"" help:"Cancel a player's override and extra time"`
+	Report ParentalReportCmd `cmd:"" help:"List warnings, kicks and whitelist changes"`
+}
+
+// ParentalStatusCmd shows where each player with a rule stands.
+type ParentalStatusCmd struct{}
+
+// Run prints each player's playtime today and what their rules allow now.
+func (cmd *ParentalStatusCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
+	rules, err := parental.Load(globals.Dir)
+	if err != nil {
+		return err
+	}
+	limits := rules.Players()
+	if len(limits) == 0 {
+		output.Info("No parental rules. Add some to %s in the server directory.", parental.ConfigFile)
+		return nil
+	}
+	st, err := parental.LoadState(globals.Dir)
+	if err != nil {
+		return err
+	}
+	all, err := loadSessions(globals, runner, output)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	y, m, d := now.Date()
+	played := make(map[string]time.Duration)
+	for _, t := range sessions.Playtime(all, time.Date(y, m, d, 0, 0, 0, 0, now.Location()), now, false) {
+		played[strings.ToLower(t.Player)] = t.Playtime
+	}
+
+	width := len("Player")
+	for _, l := range limits {
+		width = max(width, len(l.Player))
+	}
+	output.Step("Parental Controls")
+	output.Info("%-*s  %-8s  %s", width, "Player", "Today", "Status")
+	for _, l := range limits {
+		key := strings.ToLower(l.Player)
+		today := formatPlaytime(played[key])
+		if l.Daily > 0 {
+			today += "/" + formatPlaytime(l.Daily+st.ExtraTime(l.Player, now))
+		}
+		output.Info("%-*s  %-8s  %s", width, l.Player, today, parentalStatus(l, st, played[key], now))
+	}
+	for _, r := range st.Removed {
+		output.Info("%s is off the whitelist until %s (%s)", r.Player, parental.When(r.Until, now), r.Reason)
+	}
+	return nil
+}
+
+// parentalStatus describes what a player's rules allow at now.
+func parentalStatus(l *parental.Limits, st *parental.State, played time.Duration, now time.Time) string {
+	if o, ok := st.Overrides[strings.ToLower(l.Player)]; ok && now.Before(o.Until) {
+		return "allowed by a parent until " + o.Until.Format("15:04")
+	}
+	s := l.Check(now, played, st.ExtraTime(l.Player, now))
+	switch {
+	case s.Allowed && s.Until.IsZero():
+		return "may play"
+	case s.Allowed:
+		return fmt.Sprintf("may play until %s (%s)", s.Until.Format("15:04"), s.Reason)
+	case s.Reopens.IsZero():
+		return fmt.Sprintf("may not play (%s)", s.Reason)
+	default:
+		return fmt.Sprintf("may not play until %s (%s)", parental.When(s.Reopens, now), s.Reason)
+	}
+}
+
+// ParentalAllowCmd lets a player play regardless of their rules.
+type ParentalAllowCmd struct {
+	Player string        `arg:"" help:"Player name"`
+	For    time.Duration `help:"How long the override lasts" default:"1h"`
+}
+
+// Run records the override. The daemon puts the player back on the
+// whitelist at its next check.
+func (cmd *ParentalAllowCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	if cmd.For <= 0 {
+		return fmt.Errorf("--for must be positive, got %s", cmd.For)
+	}
+	now := time.Now()
+	until := now.Add(cmd.For)
+	err := updateParental(globals.Dir, &parental.Action{
+		Time: now, Player: cmd.Player, Action: parental.ActionAllow, Detail: "until " + until.Format("Mon Jan 2 15:04"),
+	}, func(st *parental.State) error {
+		st.Allow(cmd.Player, until)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	output.Success("%s may play until %s", cmd.Player, until.Format("15:04"))
+	return nil
+}
+
+// ParentalExtendCmd gives a player extra time today.
+type ParentalExtendCmd struct {
+	Player string        `arg:"" help:"Player name"`
+	Time   time.Duration `arg:"" help:"Extra time, such as 30m"`
+}
+
+// Run adds the extra time to today's daily limit.
+func (cmd *ParentalExtendCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	if cmd.Time < time.Minute {
+		return fmt.Errorf("extra time must be at least a minute, got %s", cmd.Time)
+	}
+	now := time.Now()
+	err := updateParental(globals.Dir, &parental.Action{
+		Time: now, Player: cmd.Player, Action: parental.ActionExtend, Detail: formatPlaytime(cmd.Time) + " today",
+	}, func(st *parental.State) error {
+		st.Extend(cmd.Player, cmd.Time, now)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	output.Success("Gave %s an extra %s today", cmd.Player, formatPlaytime(cmd.Time))
+	return nil
+}
+
+// ParentalRevokeCmd cancels a player's override and extra time.
+type ParentalRevokeCmd struct {
+	Player string `arg:"" help:"Player name"`
+}
+
+// Run drops the player's override and extra time; their rules apply again
+// at the daemon's next check.
+func (cmd *ParentalRevokeCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	err := updateParental(globals.Dir, &parental.Action{
+		Time: time.Now(), Player: cmd.Player, Action: parental.ActionRevoke,
+	}, func(st *parental.State) error {
+		if !st.Revoke(cmd.Player) {
+			return fmt.Errorf("%s has no override or extra time", cmd.Player)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	output.Success("%s's rules apply again", cmd.Player)
+	return nil
+}
+
+// updateParental changes the parental state and logs what a parent did.
+func updateParental(dir string, a *parental.Action, change func(*parental.State) error) error {
+	if err := parental.UpdateState(dir, change); err != nil {
+		return err
+	}
+	return parental.AppendLog(dir, a)
+}
+
+// ParentalReportCmd lists enforcement actions.
+type ParentalReportCmd struct {
+	Since  string `help:"Start of the report: days (7d), weeks (2w), a duration (12h) or a date (2026-10-01)" default:"7d"`
+	Player string `help:"Only show this player" default:""`
+}
+
+// Run prints each action since the start of the report, then a count per
+// player.
+func (cmd *ParentalReportCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	now := time.Now()
+	since, err := sessions.ParseSince(cmd.Since, now)
+	if err != nil {
+		return err
+	}
+	actions, err := parental.LoadLog(globals.Dir)
+	if err != nil {
+		return err
+	}
+
+	var shown []parental.Action
+	for _, a := range actions {
+		if a.Time.Before(since) || (cmd.Player != "" && !strings.EqualFold(a.Player, cmd.Player)) {
+			continue
+		}
+		shown = append(shown, a)
+	}
+	if len(shown) == 0 {
+		output.Info("Nothing enforced since %s", since.Format("Mon Jan 2 15:04"))
+		return nil
+	}
+
+	width := len("Player")
+	for i := range shown {
+		width = max(width, len(shown[i].Player))
+	}
+	output.Step("Parental controls since %s", since.Format("Mon Jan 2 15:04"))
+	output.Info("%-16s  %-*s  %-7s  %s", "Time", width, "Player", "Action", "Detail")
+	type counts struct{ warnings, kicks, overrides int }
+	perPlayer := make(map[string]*counts)
+	var order []string
+	for i := range shown {
+		a := &shown[i]
+		output.Info("%-16s  %-*s  %-7s  %s", a.Time.Local().Format("Mon Jan 2 15:04"), width, a.Player, a.Action, a.Detail)
+		key := strings.ToLower(a.Player)
+		c, ok := perPlayer[key]
+		if !ok {
+			c = &counts{}
+			perPlayer[key] = c
+			order = append(order, a.Player)
+		}
+		switch a.Action {
+		case parental.ActionWarn:
+			c.warnings++
+		case parental.ActionKick:
+			c.kicks++
+		case parental.ActionAllow, parental.ActionExtend:
+			c.overrides++
+		}
+	}
+	output.Step("Totals")
+	for _, p := range order {
+		c := perPlayer[strings.ToLower(p)]
+		output.Info("%-*s  %d warnings, %d kicks, %d overrides", width, p, c.warnings, c.kicks, c.overrides)
+	}
+	return nil
+}
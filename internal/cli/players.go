@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/sessions"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+	"github.com/alecthomas/kong"
+)
+
+// PlayersCmd shows who is online and when everyone was last seen.
+type PlayersCmd struct{}
+
+// Run prints every player in the logs, online players first.
+func (cmd *PlayersCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
+	all, err := loadSessions(globals, runner, output)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	list := sessions.Players(all, now)
+	if len(list) == 0 {
+		output.Info("No one has played yet")
+		return nil
+	}
+
+	width := len("Player")
+	for i := range list {
+		width = max(width, len(list[i].Name))
+	}
+	output.Step("Players")
+	output.Info("%-*s  %-22s  %8s  %s", width, "Player", "Last seen", "Sessions", "Playtime")
+	for i := range list {
+		p := &list[i]
+		seen := p.LastSeen.Local().Format("2006-01-02 15:04")
+		if p.Online {
+			seen = "online since " + p.LastSeen.Local().Format("15:04")
+		}
+		output.Info("%-*s  %-22s  %8d  %s", width, p.Name, seen, p.Sessions, formatPlaytime(p.Playtime))
+	}
+	return nil
+}
+
+// PlaytimeCmd reports how long each player played.
+type PlaytimeCmd struct {
+	Since  string `help:"Start of the report: days (7d), weeks (2w), a duration (12h) or a date (2026-10-01)" default:"7d"`
+	By     string `help:"Total per player, or per player per day" enum:"player,day" default:"player"`
+	Format string `help:"Output format" enum:"table,csv,json" default:"table"`
+}
+
+// playtimeRow is a line of CSV or JSON output.
+type playtimeRow struct {
+	Day      string `json:"day,omitempty"`
+	Player   string `json:"player"`
+	Sessions int    `json:"sessions"`
+	Minutes  int    `json:"minutes"`
+}
+
+// Run prints the playtime report.
+func (cmd *PlaytimeCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI, kctx *kong.Context) error {
+	now := time.Now()
+	since, err := sessions.ParseSince(cmd.Since, now)
+	if err != nil {
+		return err
+	}
+	all, err := loadSessions(globals, runner, output)
+	if err != nil {
+		return err
+	}
+	byDay := cmd.By == "day"
+	totals := sessions.Playtime(all, since, now, byDay)
+
+	rows := make([]playtimeRow, len(totals))
+	for i := range totals {
+		t := &totals[i]
+		rows[i] = playtimeRow{Player: t.Player, Sessions: t.Sessions, Minutes: int(t.Playtime.Minutes())}
+		if byDay {
+			rows[i].Day = t.Day.Format(time.DateOnly)
+		}
+	}
+
+	switch cmd.Format {
+	case "csv":
+		return writePlaytimeCSV(kctx.Stdout, rows, byDay)
+	case "json":
+		enc := json.NewEncoder(kctx.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	if len(totals) == 0 {
+		output.Info("No one played since %s", since.Format("Mon Jan 2 15:04"))
+		return nil
+	}
+	width := len("Player")
+	for i := range totals {
+		width = max(width, len(totals[i].Player))
+	}
+	output.Step("Playtime since %s", since.Format("Mon Jan 2 15:04"))
+	if byDay {
+		output.Info("%-10s  %-*s  %8s  %s", "Day", width, "Player", "Sessions", "Playtime")
+		for i := range totals {
+			t := &totals[i]
+			output.Info("%-10s  %-*s  %8d  %s", t.Day.Format("Mon Jan 2"), width, t.Player, t.Sessions, formatPlaytime(t.Playtime))
+		}
+		output.Step("Totals")
+		totals = sessions.Playtime(all, since, now, false)
+	}
+	output.Info("%-*s  %8s  %s", width, "Player", "Sessions", "Playtime")
+	for i := range totals {
+		t := &totals[i]
+		output.Info("%-*s  %8d  %s", width, t.Player, t.Sessions, formatPlaytime(t.Playtime))
+	}
+	return nil
+}
+
+func writePlaytimeCSV(w io.Writer, rows []playtimeRow, byDay bool) error {
+	cw := csv.NewWriter(w)
+	header := []string{"player", "sessions", "minutes"}
+	if byDay {
+		header = append([]string{"day"}, header...)
+	}
+	_ = cw.Write(header)
+	for _, r := range rows {
+		rec := []string{r.Player, strconv.Itoa(r.Sessions), strconv.Itoa(r.Minutes)}
+		if byDay {
+			rec = append([]string{r.Day}, rec...)
+		}
+		_ = cw.Write(rec)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// loadSessions reads the sessions in the server's logs. Whether anyone is
+// online depends on the server running: a crash leaves no leave lines.
+func loadSessions(globals *Globals, runner platform.CommandRunner, output *ui.UI) ([]sessions.Session, error) {
+	ctx := context.Background()
+	cfg := globalsToConfig(globals)
+	res := resolveManager(ctx, globals, runner, output)
+	defer func() { _ = res.Close() }()
+	running := management.IsServerRunning(ctx, res.Manager, runner, cfg.Port)
+	return sessions.Load(cfg.Dir, running)
+}
+
+// formatPlaytime formats a playtime as "45m" or "3h05m".
+func formatPlaytime(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
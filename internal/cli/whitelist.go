@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/parental"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/players"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/serverctl"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// WhitelistCmd manages who may join.
+type WhitelistCmd struct {
+	List    WhitelistListCmd    `cmd:"" default:"1" help:"Show who is on the whitelist"`
+	Add     WhitelistAddCmd     `cmd:"" help:"Let players join"`
+	Remove  WhitelistRemoveCmd  `cmd:"" help:"Stop players joining"`
+	Pending WhitelistPendingCmd `cmd:"" help:"Show players who tried to join and aren't on the whitelist"`
+	Approve WhitelistApproveCmd `cmd:"" help:"Whitelist a player who asked to join"`
+	Deny    WhitelistDenyCmd    `cmd:"" help:"Turn down a player who asked to join"`
+}
+
+// whitelistNames are the players an add or remove is for.
+type whitelistNames struct {
+	Names   []string `arg:"" help:"Player names; Bedrock players with their Floodgate prefix (.Name) or --bedrock"`
+	Bedrock bool     `help:"The names are Bedrock gamertags: add the Floodgate prefix" default:"false"`
+}
+
+// editWhitelist calls edit with an Editor for the server and each name,
+// prefixed for Bedrock players when asked. Every name is tried; the errors
+// are returned together.
+func editWhitelist(globals *Globals, runner platform.CommandRunner, output *ui.UI, names *whitelistNames, edit func(context.Context, *players.Editor, string) error) error {
+	ctx := context.Background()
+	ed, closeFn := newEditor(ctx, globals, runner, output)
+	defer closeFn()
+	if names.Bedrock && ed.Resolver.BedrockPrefix == "" {
+		return errors.New("--bedrock needs Floodgate, which isn't installed on this server")
+	}
+	var errs []error
+	for _, name := range names.Names {
+		if names.Bedrock && !ed.Resolver.IsBedrock(name) {
+			name = ed.Resolver.BedrockPrefix + name
+		}
+		if err := edit(ctx, ed, name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newEditor returns a whitelist Editor for the server and a function
+// that releases its manager.
+func newEditor(ctx context.Context, globals *Globals, runner platform.CommandRunner, output *ui.UI) (*players.Editor, func()) {
+	cfg := globalsToConfig(globals)
+	res := resolveManager(ctx, globals, runner, output)
+	return &players.Editor{
+		ServerDir: cfg.Dir,
+		Manager:   res.Manager,
+		Running:   management.IsServerRunning(ctx, res.Manager, runner, cfg.Port),
+		Resolver:  players.NewResolver(cfg.Dir, players.NewWebLookup()),
+	}, func() { _ = res.Close() }
+}
+
+// WhitelistAddCmd whitelists players.
+type WhitelistAddCmd struct {
+	whitelistNames
+}
+
+// Run adds each player, through the server if it is running.
+func (cmd *WhitelistAddCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
+	return editWhitelist(globals, runner, output, &cmd.whitelistNames, func(ctx context.Context, ed *players.Editor, name string) error {
+		entry, added, err := ed.Add(ctx, name)
+		switch {
+		case err != nil:
+			return err
+		case !added:
+			output.Info("%s is already on the whitelist", entry.Name)
+		case entry.UUID == "":
+			output.Success("Added %s to the whitelist", entry.Name)
+		default:
+			output.Success("Added %s (%s) to the whitelist", entry.Name, entry.UUID)
+		}
+		return nil
+	})
+}
+
+// WhitelistRemoveCmd takes players off the whitelist.
+type WhitelistRemoveCmd struct {
+	whitelistNames
+}
+
+// Run removes each player, through the server if it is running. Players
+// already online stay until they leave.
+func (cmd *WhitelistRemoveCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
+	return editWhitelist(globals, runner, output, &cmd.whitelistNames, func(ctx context.Context, ed *players.Editor, name string) error {
+		removed, err := ed.Remove(ctx, name)
+		switch {
+		case err != nil:
+			return err
+		case removed:
+			output.Success("Removed %s from the whitelist", name)
+		default:
+			output.Info("%s isn't on the whitelist", name)
+		}
+		return nil
+	})
+}
+
+// WhitelistListCmd shows the whitelist.
+type WhitelistListCmd struct{}
+
+// Run prints whitelist.json, marking Bedrock players.
+func (cmd *WhitelistListCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	wl, err := players.LoadWhitelist(globals.Dir)
+	if err != nil {
+		return err
+	}
+	if serverctl.ReadProperty(globals.Dir, "white-list") == "false" {
+		output.Warn("The whitelist is off in server.properties — anyone can join")
+	}
+	if len(wl) == 0 {
+		output.Info("No one is on the whitelist — add players with: mc-dad-server whitelist add <name>")
+		return nil
+	}
+	resolver := players.NewResolver(globals.Dir, nil)
+	width := len("Player")
+	for i := range wl {
+		width = max(width, len(wl[i].Name))
+	}
+	output.Step("Whitelist (%d players)", len(wl))
+	for i := range wl {
+		edition := "Java"
+		if resolver.IsBedrock(wl[i].Name) {
+			edition = "Bedrock"
+		}
+		output.Info("%-*s  %-7s  %s", width, wl[i].Name, edition, wl[i].UUID)
+	}
+	return nil
+}
+
+// updateRequests brings the join requests up to date, leaving out players
+// parental controls have taken off the whitelist, and returns them with
+// the parental state.
+func updateRequests(serverDir string) (players.Requests, *parental.State, error) {
+	st, err := parental.LoadState(serverDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	reqs, err := players.UpdateRequests(serverDir, func(name string) bool {
+		_, held := st.Held(name)
+		return held
+	})
+	return reqs, st, err
+}
+
+// WhitelistPendingCmd shows the players asking to join.
+type WhitelistPendingCmd struct{}
+
+// Run lists the refused players not yet approved or denied.
+func (cmd *WhitelistPendingCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	reqs, _, err := updateRequests(globals.Dir)
+	if err != nil {
+		return err
+	}
+	pending := reqs.Pending()
+	if denied := len(reqs) - len(pending); denied > 0 {
+		defer output.Info("Not showing %d denied; approve still works for them", denied)
+	}
+	if len(pending) == 0 {
+		output.Info("No one is waiting to join")
+		return nil
+	}
+	width := len("Player")
+	for i := range pending {
+		width = max(width, len(pending[i].Name))
+	}
+	output.Step("Waiting to join (%d)", len(pending))
+	output.Info("%-*s  %-16s  %8s  %s", width, "Player", "Last tried", "Attempts", "UUID")
+	for i := range pending {
+		output.Info("%-*s  %-16s  %8d  %s", width, pending[i].Name, pending[i].Last.Local().Format("2006-01-02 15:04"), pending[i].Attempts, pending[i].UUID)
+	}
+	output.Info("Let them in with: mc-dad-server whitelist approve <name>")
+	return nil
+}
+
+// WhitelistApproveCmd whitelists a player from the pending requests.
+type WhitelistApproveCmd struct {
+	Name string `arg:"" help:"Player to let in, as shown by whitelist pending"`
+}
+
+// Run adds the player to the whitelist and takes them out of the queue.
+// Players parental controls took off the whitelist stay off; a parent lets
+// them back early with parental allow, which the daemon enforces.
+func (cmd *WhitelistApproveCmd) Run(globals *Globals, runner platform.CommandRunner, output *ui.UI) error {
+	reqs, st, err := updateRequests(globals.Dir)
+	if err != nil {
+		return err
+	}
+	if r, held := st.Held(cmd.Name); held {
+		return fmt.Errorf("%s is off the whitelist until %s (%s); let them play sooner with: mc-dad-server parental allow %s",
+			r.Player, parental.When(r.Until, time.Now()), r.Reason, r.Player)
+	}
+	req, ok := reqs.Find(cmd.Name)
+	if !ok {
+		return fmt.Errorf("%s hasn't asked to join; add them anyway with: mc-dad-server whitelist add %s", cmd.Name, cmd.Name)
+	}
+	ctx := context.Background()
+	ed, closeFn := newEditor(ctx, globals, runner, output)
+	defer closeFn()
+	if _, _, err := ed.Add(ctx, req.Name); err != nil {
+		return err
+	}
+	err = players.EditRequests(globals.Dir, func(reqs *players.Requests) error {
+		*reqs, _ = reqs.Remove(req.Name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	output.Success("Approved %s — they can join now", req.Name)
+	return nil
+}
+
+// WhitelistDenyCmd turns down a pending request.
+type WhitelistDenyCmd struct {
+	Name string `arg:"" help:"Player to turn down, as shown by whitelist pending"`
+}
+
+// Run marks the request denied, so the player's later attempts aren't
+// shown or announced.
+func (cmd *WhitelistDenyCmd) Run(globals *Globals, _ platform.CommandRunner, output *ui.UI) error {
+	reqs, _, err := updateRequests(globals.Dir)
+	if err != nil {
+		return err
+	}
+	req, ok := reqs.Find(cmd.Name)
+	if !ok {
+		return fmt.Errorf("%s hasn't asked to join", cmd.Name)
+	}
+	err = players.EditRequests(globals.Dir, func(reqs *players.Requests) error {
+		reqs.Deny(req.Name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	output.Success("Denied %s; their requests won't be shown again", req.Name)
+	return nil
+}
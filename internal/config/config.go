@@ -12,25 +12,28 @@ const BedrockPort = 19132
 
 // ServerConfig holds all configuration for a Minecraft server install.
 type ServerConfig struct {
-	Edition      string `json:"edition"`
-	Dir          string `json:"dir"`
-	Port         int    `json:"port"`
-	Memory       string `json:"memory"`
-	ServerType   string `json:"server_type"`
-	MOTD         string `json:"motd"`
-	MaxPlayers   int    `json:"max_players"`
-	Difficulty   string `json:"difficulty"`
-	GameMode     string `json:"gamemode"`
-	GCType       string `json:"gc_type"`
-	Whitelist    bool   `json:"whitelist"`
-	ChatFilter   bool   `json:"chat_filter"`
-	EnablePlayit bool   `json:"enable_playit"`
-	EnableBun    bool   `json:"enable_bun"`
-	Version      string `json:"version"`
-	SessionName  string `json:"session_name"`
-	MaxBackups   int    `json:"max_backups"`
-	VoteDuration int    `json:"vote_duration"`
-	VoteChoices  int    `json:"vote_choices"`
+	Edition           string `json:"edition"`
+	Dir               string `json:"dir"`
+	Port              int    `json:"port"`
+	Memory            string `json:"memory"`
+	ServerType        string `json:"server_type"`
+	MOTD              string `json:"motd"`
+	MaxPlayers        int    `json:"max_players"`
+	Difficulty        string `json:"difficulty"`
+	GameMode          string `json:"gamemode"`
+	GCType            string `json:"gc_type"`
+	Whitelist         bool   `json:"whitelist"`
+	ChatFilter        bool   `json:"chat_filter"`
+	EnablePlayit      bool   `json:"enable_playit"`
+	EnableBun         bool   `json:"enable_bun"`
+	LANBroadcast      bool   `json:"lan_broadcast"`
+	LANInterface      string `json:"lan_interface"`
+	Version           string `json:"version"`
+	SessionName       string `json:"session_name"`
+	MaxBackups        int    `json:"max_backups"`
+	VoteDuration      int    `json:"vote_duration"`
+	VoteChoices       int    `json:"vote_choices"`
+	VoteExcludeRecent int    `json:"vote_exclude_recent"`
 
 	// Generated at runtime
 	RCONPassword string `json:"-"`
@@ -39,25 +42,27 @@ type ServerConfig struct {
 // DefaultConfig returns a ServerConfig with sensible defaults matching install.sh.
 func DefaultConfig() *ServerConfig {
 	return &ServerConfig{
-		Edition:      "java",
-		Dir:          "",
-		Port:         25565,
-		Memory:       "2G",
-		ServerType:   "paper",
-		MOTD:         "Dads Minecraft Server",
-		MaxPlayers:   20,
-		Difficulty:   "normal",
-		GameMode:     "survival",
-		GCType:       "g1gc",
-		Whitelist:    true,
-		ChatFilter:   true,
-		EnablePlayit: true,
-		EnableBun:    false,
-		Version:      "latest",
-		SessionName:  "minecraft",
-		MaxBackups:   5,
-		VoteDuration: 30,
-		VoteChoices:  5,
+		Edition:           "java",
+		Dir:               "",
+		Port:              25565,
+		Memory:            "2G",
+		ServerType:        "paper",
+		MOTD:              "Dads Minecraft Server",
+		MaxPlayers:        20,
+		Difficulty:        "normal",
+		GameMode:          "survival",
+		GCType:            "g1gc",
+		Whitelist:         true,
+		ChatFilter:        true,
+		EnablePlayit:      true,
+		EnableBun:         false,
+		LANBroadcast:      false,
+		Version:           "latest",
+		SessionName:       "minecraft",
+		MaxBackups:        5,
+		VoteDuration:      30,
+		VoteChoices:       5,
+		VoteExcludeRecent: 2,
 	}
 }
 
@@ -136,6 +141,10 @@ func (c *ServerConfig) Validate() error {
 	if !sessionNamePattern.MatchString(c.SessionName) {
 		return fmt.Errorf("invalid session name %q: use only letters, digits, dot, dash, or underscore", c.SessionName)
 	}
+	// The LAN interface lands in a shell assignment in the start script.
+	if c.LANInterface != "" && !sessionNamePattern.MatchString(c.LANInterface) {
+		return fmt.Errorf("invalid lan interface %q: use only letters, digits, dot, dash, or underscore", c.LANInterface)
+	}
 
 	return nil
 }
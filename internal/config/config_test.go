@@ -140,6 +140,11 @@ func TestValidateRejectsInjectableValues(t *testing.T) {
 			mutate:  func(c *ServerConfig) { c.SessionName = "" },
 			wantErr: "session name must be set",
 		},
+		{
+			name:    "lan interface with shell metacharacters",
+			mutate:  func(c *ServerConfig) { c.LANInterface = `eth0"; reboot; "` },
+			wantErr: "invalid lan interface",
+		},
 	}
 
 	for _, tt := range tests {
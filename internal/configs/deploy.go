@@ -316,8 +316,10 @@ func (d *Deployer) DeployStartScript(cfg *config.ServerConfig) error {
 	defer func() { _ = f.Close() }()
 
 	return tmpl.Execute(f, map[string]any{
-		"Memory":    cfg.Memory,
-		"GCType":    cfg.GCType,
-		"EnableBun": cfg.EnableBun,
+		"Memory":       cfg.Memory,
+		"GCType":       cfg.GCType,
+		"EnableBun":    cfg.EnableBun,
+		"LANBroadcast": cfg.LANBroadcast,
+		"LANInterface": cfg.LANInterface,
 	})
 }
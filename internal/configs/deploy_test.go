@@ -137,6 +137,39 @@ func TestDeployStartScript(t *testing.T) {
 	if !strings.Contains(content, "4G") {
 		t.Error("start.sh missing memory setting")
 	}
+	if strings.Contains(content, "daemon --no-metrics") {
+		t.Error("start.sh launches the LAN announcer without --lan-broadcast")
+	}
+}
+
+func TestDeployStartScriptLANBroadcast(t *testing.T) {
+	d := setupTestFS(t)
+
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Dir = dir
+	cfg.LANBroadcast = true
+	cfg.LANInterface = "wlan0"
+
+	if err := d.DeployStartScript(cfg); err != nil {
+		t.Fatalf("d.DeployStartScript() error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "start.sh"))
+	if err != nil {
+		t.Fatalf("reading start.sh: %v", err)
+	}
+	content := string(data)
+
+	for _, want := range []string{"daemon --no-metrics --no-bans --no-requests", "MC_LAN_INTERFACE:-wlan0", "trap cleanup"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("start.sh missing %q", want)
+		}
+	}
+	// Java must not replace the shell, or the announcer would never be stopped.
+	if strings.Contains(content, "exec \"$JAVA_CMD\"") {
+		t.Error("start.sh still execs java with the LAN announcer enabled")
+	}
 }
 
 func TestDeployCompose(t *testing.T) {
@@ -3,7 +3,10 @@ package console
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -71,16 +74,25 @@ func dispatch(ctx context.Context, input string, opts *Options, runner platform.
 		}
 
 	case "vote-map":
+		mode := vote.Plurality
+		if len(args) > 0 {
+			var err error
+			if mode, err = vote.ParseMode(args[0]); err != nil {
+				output.Warn("%s", err)
+				break
+			}
+		}
 		if !running {
 			output.Warn("Server not running — start it first")
 		} else {
 			result, err := vote.RunVote(ctx, &vote.Config{
-				Maps:       management.ParkourMaps,
-				Duration:   time.Duration(cfg.VoteDuration) * time.Second,
-				MaxChoices: cfg.VoteChoices,
-				ServerDir:  cfg.Dir,
-				Manager:    mgr,
-				Output:     output,
+				Duration:      time.Duration(cfg.VoteDuration) * time.Second,
+				MaxChoices:    cfg.VoteChoices,
+				ExcludeRecent: cfg.VoteExcludeRecent,
+				Mode:          mode,
+				ServerDir:     cfg.Dir,
+				Manager:       mgr,
+				Output:        output,
 			})
 			if err != nil {
 				output.Warn("Vote failed: %s", err)
@@ -105,12 +117,7 @@ func dispatch(ctx context.Context, input string, opts *Options, runner platform.
 		if len(args) == 0 {
 			output.Warn("Usage: cmd <raw minecraft command>")
 		} else {
-			raw := strings.Join(args, " ")
-			if err := mgr.SendCommand(ctx, raw); err != nil {
-				output.Warn("%s", err)
-			} else {
-				output.Success("Sent: %s", raw)
-			}
+			sendRaw(ctx, mgr, strings.Join(args, " "), &buf, output)
 		}
 
 	case "help":
@@ -129,6 +136,85 @@ func dispatch(ctx context.Context, input string, opts *Options, runner platform.
 	return strings.TrimRight(buf.String(), "\n"), false
 }
 
+// runLocal runs the commands that act on the console view rather than the
+// server. It reports false for any other input, which goes to dispatch.
+func (m *model) runLocal(input string) (string, bool) {
+	parts := strings.Fields(input)
+	args := parts[1:]
+
+	switch strings.ToLower(parts[0]) {
+	case "filter":
+		if len(args) == 0 {
+			if f := m.filters.String(); f != "" {
+				return "Filter: " + f, true
+			}
+			return "No filter (filter chat|plugins|errors|off)", true
+		}
+		if !m.filters.toggle(strings.ToLower(args[0])) {
+			return fmt.Sprintf("Unknown filter: %s (chat, plugins, errors or off)", args[0]), true
+		}
+		m.refresh()
+		if f := m.filters.String(); f != "" {
+			return "Filter: " + f, true
+		}
+		return "Filter off", true
+
+	case "save":
+		path := filepath.Join(m.opts.Dir, "console-"+time.Now().Format("20060102-150405")+".log")
+		if len(args) > 0 {
+			path = strings.Join(args, " ")
+		}
+		n, err := m.save(path)
+		if err != nil {
+			return fmt.Sprintf("Saving console: %s", err), true
+		}
+		return fmt.Sprintf("Saved %d lines to %s", n, path), true
+	}
+	return "", false
+}
+
+// save writes the lines currently shown, without colours, to path.
+func (m *model) save(path string) (int, error) {
+	var b strings.Builder
+	for _, i := range m.shown {
+		b.WriteString(m.buf.lines[i].text + "\n")
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return 0, fmt.Errorf("writing %s: %w", path, err)
+	}
+	return len(m.shown), nil
+}
+
+// sendRaw sends a raw server command and shows the server's reply when the
+// manager can return one (RCON); otherwise it confirms the command was sent.
+func sendRaw(ctx context.Context, mgr management.ServerManager, raw string, buf *bytes.Buffer, output *ui.UI) {
+	if q, ok := mgr.(management.Querier); ok {
+		reply, err := q.Query(ctx, raw)
+		switch {
+		case err == nil:
+			reply = strings.TrimRight(management.StripFormatting(reply), "\n")
+			if reply == "" {
+				output.Success("Sent: %s", raw)
+			} else {
+				buf.WriteString(reply + "\n")
+			}
+			return
+		case !errors.Is(err, management.ErrNoQuery):
+			// The server may have run the command already; sending it
+			// again could give, summon or ban twice.
+			output.Warn("%s", err)
+			return
+		}
+		// Screen mode without an RCON connection still works through the
+		// screen session, just without a reply.
+	}
+	if err := mgr.SendCommand(ctx, raw); err != nil {
+		output.Warn("%s", err)
+	} else {
+		output.Success("Sent: %s", raw)
+	}
+}
+
 func helpText() string {
 	return `Available commands:
   start           Start the Minecraft server
@@ -136,12 +222,21 @@ func helpText() string {
   status          Show server status and resource usage
   backup          Backup world data
   rotate-parkour  Rotate the featured parkour map
-  vote-map        Start a map vote
+  vote-map [mode] Start a map vote (plurality, runoff, approval, weighted)
   say <msg>       Broadcast a message to players
-  cmd <raw>       Send a raw command to the server console
+  cmd <raw>       Send a raw command and show the server's reply
+  filter <name>   Toggle a log filter: chat, plugins, errors or off
+  save [file]     Save the lines shown to a file
   clear           Clear the console
   help            Show this help
-  exit / quit     Exit the console`
+  exit / quit     Exit the console
+
+Keys:
+  / text          Find in the log; Ctrl+P/Ctrl+N for older/newer matches
+  F2 / F3 / F4    Toggle chat only / hide plugins / errors only
+  F5              Toggle the sidebar (hidden on narrow terminals)
+  PgUp / PgDn     Scroll; new lines wait while scrolled up, End follows again
+  Tab, Ctrl+R     Complete a command or player, search input history`
 }
 
 // target builds a serverctl.Target from the console options.
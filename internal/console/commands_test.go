@@ -1,10 +1,15 @@
 package console
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
 )
 
 func TestDispatch_ContainerMode_UsesContainerManager(t *testing.T) {
@@ -44,3 +49,22 @@ func TestDispatch_AutoMode_PrefersRunningContainer(t *testing.T) {
 		t.Fatalf("expected docker command for status, got %q", runner.Commands[1].Name)
 	}
 }
+
+func TestSendRawFallsBackOnlyWithoutConnection(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantSent bool
+	}{
+		{"no connection", fmt.Errorf("rcon: %w: dial refused", management.ErrNoQuery), true},
+		{"timed out after sending", errors.New("rcon command read: i/o timeout"), false},
+	}
+	for _, tt := range tests {
+		mgr := management.NewMockManager()
+		mgr.QueryErr = tt.err
+		sendRaw(t.Context(), mgr, "give Steve diamond", &bytes.Buffer{}, ui.NewWriter(&bytes.Buffer{}, false))
+		if got := len(mgr.Commands()) == 1; got != tt.wantSent {
+			t.Errorf("%s: sent through the console = %v, want %v", tt.name, got, tt.wantSent)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package console
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/vote"
+)
+
+// verbs are the console's own commands, completed in the first word.
+var verbs = []string{
+	"start", "stop", "status", "backup", "rotate-parkour", "vote-map",
+	"say", "cmd", "filter", "save", "clear", "help", "exit", "quit",
+}
+
+// minecraftCommands are the server commands completed after "cmd".
+var minecraftCommands = []string{
+	"advancement", "ban", "ban-ip", "banlist", "clear", "deop", "difficulty",
+	"effect", "enchant", "execute", "gamemode", "gamerule", "give", "kick",
+	"kill", "list", "locate", "msg", "mv", "op", "pardon", "playsound",
+	"reload", "save-all", "save-off", "save-on", "say", "seed", "setblock",
+	"setworldspawn", "spawnpoint", "stop", "summon", "tell", "tellraw",
+	"time", "title", "tp", "teleport", "weather", "whitelist", "worldborder",
+	"xp",
+}
+
+// commandArgs are the fixed first arguments of common server commands.
+// Player names are offered for every argument as well.
+var commandArgs = map[string][]string{
+	"difficulty": {"peaceful", "easy", "normal", "hard"},
+	"gamemode":   {"survival", "creative", "adventure", "spectator"},
+	"time":       {"set", "add", "query"},
+	"weather":    {"clear", "rain", "thunder"},
+	"whitelist":  {"add", "remove", "list", "on", "off", "reload"},
+	"mv":         {"tp", "list", "import", "create", "remove"},
+}
+
+// completions returns the candidates for the last word of input and the
+// byte offset where that word starts.
+func completions(input string, players []string) ([]string, int) {
+	start := strings.LastIndexByte(input, ' ') + 1
+	word := input[start:]
+	fields := strings.Fields(input[:start])
+
+	var pool []string
+	switch {
+	case len(fields) == 0:
+		pool = verbs
+	case strings.ToLower(fields[0]) != "cmd":
+		// Console verbs take no arguments worth completing, except
+		// "say", where a player name is handy, "filter" and "vote-map".
+		switch strings.ToLower(fields[0]) {
+		case "say":
+			pool = players
+		case "filter":
+			pool = filterNames
+		case "vote-map":
+			for _, m := range vote.Modes {
+				pool = append(pool, string(m))
+			}
+		}
+	case len(fields) == 1:
+		pool = minecraftCommands
+	default:
+		pool = slices.Concat(commandArgs[strings.ToLower(fields[1])], players)
+	}
+
+	var out []string
+	for _, c := range pool {
+		if strings.HasPrefix(strings.ToLower(c), strings.ToLower(word)) && !slices.Contains(out, c) {
+			out = append(out, c)
+		}
+	}
+	return out, start
+}
+
+// commonPrefix returns the longest prefix shared by all of words.
+func commonPrefix(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// completer cycles through candidates on repeated Tab presses.
+type completer struct {
+	// base is the input before the word being completed.
+	base       string
+	candidates []string
+	idx        int
+}
+
+// complete returns input with its last word completed. The first Tab
+// completes as far as the candidates agree; further presses cycle through
+// them.
+func (c *completer) complete(input string, players []string) string {
+	if c.candidates != nil {
+		c.idx = (c.idx + 1) % len(c.candidates)
+		return c.base + c.candidates[c.idx]
+	}
+
+	cands, start := completions(input, players)
+	switch len(cands) {
+	case 0:
+		return input
+	case 1:
+		return input[:start] + cands[0] + " "
+	}
+	word := input[start:]
+	if prefix := commonPrefix(cands); len(prefix) > len(word) {
+		return input[:start] + prefix
+	}
+	// Nothing more in common: start cycling.
+	c.base, c.candidates, c.idx = input[:start], cands, 0
+	return c.base + cands[0]
+}
+
+// reset ends a completion cycle; any key other than Tab does.
+func (c *completer) reset() {
+	c.base, c.candidates, c.idx = "", nil, 0
+}
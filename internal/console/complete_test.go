@@ -0,0 +1,61 @@
+package console
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompletions(t *testing.T) {
+	players := []string{"Steve", "Stella", ".Bedrock_Kid"}
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "st", want: []string{"start", "stop", "status"}},
+		{input: "vote", want: []string{"vote-map"}},
+		{input: "cmd wea", want: []string{"weather"}},
+		{input: "cmd gamemode c", want: []string{"creative"}},
+		{input: "cmd tp St", want: []string{"Steve", "Stella"}},
+		{input: "cmd op .b", want: []string{".Bedrock_Kid"}},
+		{input: "say st", want: []string{"Steve", "Stella"}},
+		{input: "vote-map r", want: []string{"runoff"}},
+		{input: "backup x", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, _ := completions(tt.input, players)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("completions(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleterCycles(t *testing.T) {
+	var c completer
+	players := []string{"Steve", "Stella"}
+
+	// A single candidate completes the word and adds a space.
+	if got := c.complete("cmd weat", players); got != "cmd weather " {
+		t.Fatalf("got %q", got)
+	}
+
+	// Several candidates complete their common prefix first...
+	if got := c.complete("cmd tp s", players); got != "cmd tp Ste" {
+		t.Fatalf("got %q, want the common prefix", got)
+	}
+	// ...then cycle through them, wrapping around.
+	for _, want := range []string{"cmd tp Steve", "cmd tp Stella", "cmd tp Steve"} {
+		if got := c.complete("cmd tp Ste", players); got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+
+	// Any other key ends the cycle, so the next Tab completes afresh.
+	c.reset()
+	if got := c.complete("cmd weat", players); got != "cmd weather " {
+		t.Fatalf("after reset got %q", got)
+	}
+}
@@ -3,7 +3,6 @@ package console
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -11,6 +10,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/logtail"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
 )
 
@@ -19,6 +21,9 @@ type Options struct {
 	Dir     string
 	Session string
 	Mode    string
+	// Notifier, if set, is told when the console starts or stops the
+	// server or takes a backup.
+	Notifier management.Notifier
 }
 
 // Styles.
@@ -51,9 +56,12 @@ const maxConsoleLines = 5000
 type model struct {
 	viewport viewport.Model
 	input    textinput.Model
-	lines    []string
-	history  []string
-	histIdx  int
+	buf      *logBuffer
+	// shown holds the indices in buf.lines of the lines passing filters,
+	// in view order.
+	shown    []int
+	filters  filters
+	hist     *history
 	opts     *Options
 	runner   platform.CommandRunner
 	width    int
@@ -62,11 +70,27 @@ type model struct {
 	quitting bool
 	cancel   context.CancelFunc
 	ctx      context.Context
-	logPath  string
-	// logOffset tracks file position for the log tailer.
-	logOffset int64
+	// logLines delivers server log lines, surviving log rotation.
+	logLines <-chan logtail.Line
+	// logSource describes where logLines come from, for the status bar.
+	logSource string
 	// running indicates whether a command is currently executing.
 	running bool
+	// players holds the online player names for tab completion.
+	players   []string
+	dashboard *dashboardSource
+	dash      dashboard
+	dashReady bool
+	// sessions tracks when online players joined, for the sidebar.
+	sessions    map[string]session
+	showSidebar bool
+	completer   *completer
+	// search is the Ctrl+R reverse history search, when active.
+	search *reverseSearch
+	// find is the `/` search through the log, when active.
+	find *logFind
+	// unseen counts lines that arrived while scrolled up.
+	unseen int
 }
 
 func newModel(opts *Options, runner platform.CommandRunner) model {
@@ -76,22 +100,41 @@ func newModel(opts *Options, runner platform.CommandRunner) model {
 	ti.CharLimit = 256
 
 	ctx, cancel := context.WithCancel(context.Background())
+	if opts.Notifier != nil {
+		ctx = management.WithNotifier(ctx, opts.Notifier)
+	}
+	logLines, logSource := followServerLog(ctx, opts, runner)
+
+	// Custom chat formats only change how lines are coloured and filtered,
+	// so a broken chat-formats.json falls back to the built-in ones.
+	parser, err := events.LoadParser(opts.Dir)
+	if err != nil {
+		parser = events.NewParser()
+	}
 
 	return model{
-		input:   ti,
-		opts:    opts,
-		runner:  runner,
-		histIdx: -1,
-		ctx:     ctx,
-		cancel:  cancel,
-		logPath: filepath.Join(opts.Dir, "logs", "latest.log"),
+		input:     ti,
+		opts:      opts,
+		runner:    runner,
+		buf:       &logBuffer{parser: parser},
+		hist:      loadHistory(opts.Dir),
+		completer: &completer{},
+		dashboard: &dashboardSource{opts: opts, runner: runner},
+		sessions:  make(map[string]session),
+		// Shown when the terminal is wide enough; F5 toggles it.
+		showSidebar: true,
+		ctx:         ctx,
+		cancel:      cancel,
+		logLines:    logLines,
+		logSource:   logSource,
 	}
 }
 
 func (m model) Init() tea.Cmd { //nolint:gocritic
 	return tea.Batch(
 		textinput.Blink,
-		tailLog(m.ctx, m.logPath),
+		waitForLogLine(m.logLines),
+		fetchDashboard(m.ctx, m.dashboard, 0),
 	)
 }
 
@@ -102,31 +145,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint:gocritic
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-
-		// Reserve lines: 1 title bar + 1 input + 1 status bar.
-		viewHeight := max(m.height-3, 1)
-
-		if !m.ready {
-			m.viewport = viewport.New(m.width, viewHeight)
-			m.viewport.SetContent(strings.Join(m.lines, "\n"))
-			m.ready = true
-		} else {
-			m.viewport.Width = m.width
-			m.viewport.Height = viewHeight
-		}
+		m.resize()
 		m.input.Width = m.width - 4
 
 	case logReadMsg:
-		m.logOffset = msg.offset
-		m.lines = append(m.lines, msg.line)
-		if len(m.lines) > maxConsoleLines {
-			m.lines = m.lines[len(m.lines)-maxConsoleLines:]
+		if ev, ok := m.buf.addLog(msg.line.Text); ok {
+			m.trackSession(ev)
 		}
-		if m.ready {
-			m.viewport.SetContent(strings.Join(m.lines, "\n"))
-			m.viewport.GotoBottom()
+		if m.ready && !m.viewport.AtBottom() && m.filters.show(&m.buf.lines[len(m.buf.lines)-1]) {
+			m.unseen++
 		}
-		cmds = append(cmds, nextLogLine(m.ctx, m.logPath, m.logOffset))
+		m.refresh()
+		cmds = append(cmds, waitForLogLine(m.logLines))
 
 	case cmdDoneMsg:
 		m.running = false
@@ -136,28 +166,51 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint:gocritic
 			return m, tea.Quit
 		}
 		if msg.output == clearSentinel {
-			m.lines = nil
-			if m.ready {
-				m.viewport.SetContent("")
-			}
+			m.buf.reset()
+			m.find = nil
+			m.refresh()
 		} else {
-			// Show the command that was run.
-			m.lines = append(m.lines, promptStyle.Render("> ")+msg.input)
-			if msg.output != "" {
-				m.lines = append(m.lines, strings.Split(msg.output, "\n")...)
-			}
-			if len(m.lines) > maxConsoleLines {
-				m.lines = m.lines[len(m.lines)-maxConsoleLines:]
-			}
-			if m.ready {
-				m.viewport.SetContent(strings.Join(m.lines, "\n"))
-				m.viewport.GotoBottom()
-			}
+			m.showOutput(msg.input, msg.output)
 		}
 
+	case dashboardMsg:
+		m.dash, m.dashReady = msg.dash, true
+		switch {
+		case msg.dash.players != nil:
+			m.players = msg.dash.players.Names
+			m.syncSessions(m.players)
+		case !msg.dash.running:
+			m.players = nil
+			clear(m.sessions)
+		}
+		cmds = append(cmds, fetchDashboard(m.ctx, m.dashboard, dashboardRefresh))
+
 	case tea.KeyMsg:
+		if msg.Type != tea.KeyTab {
+			m.completer.reset()
+		}
+		if m.search != nil && msg.Type != tea.KeyCtrlC {
+			m.updateSearch(msg)
+			return m, tea.Batch(cmds...)
+		}
+		if m.find != nil && m.find.editing && msg.Type != tea.KeyCtrlC {
+			m.updateFind(msg)
+			return m, tea.Batch(cmds...)
+		}
+
 		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case tea.KeyEsc:
+			if m.find != nil {
+				// Esc first clears the search highlights.
+				m.find = nil
+				m.refresh()
+				return m, tea.Batch(cmds...)
+			}
+			m.quitting = true
+			m.cancel()
+			return m, tea.Quit
+
+		case tea.KeyCtrlC:
 			m.quitting = true
 			m.cancel()
 			return m, tea.Quit
@@ -171,37 +224,94 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint:gocritic
 			if strings.TrimSpace(input) == "" {
 				break
 			}
-			// Add to history.
-			m.history = append(m.history, input)
-			m.histIdx = len(m.history)
+			m.hist.add(input)
+			if output, ok := m.runLocal(input); ok {
+				m.showOutput(input, output)
+				break
+			}
 			// Run command asynchronously.
 			m.running = true
 			cmds = append(cmds, m.runCommand(input))
 
 		case tea.KeyUp:
-			if len(m.history) > 0 && m.histIdx > 0 {
-				m.histIdx--
-				m.input.SetValue(m.history[m.histIdx])
+			if entry, ok := m.hist.prev(); ok {
+				m.input.SetValue(entry)
 				m.input.CursorEnd()
 			}
 
 		case tea.KeyDown:
-			if m.histIdx < len(m.history)-1 {
-				m.histIdx++
-				m.input.SetValue(m.history[m.histIdx])
+			if entry, ok := m.hist.next(); ok {
+				m.input.SetValue(entry)
 				m.input.CursorEnd()
-			} else if m.histIdx == len(m.history)-1 {
-				m.histIdx = len(m.history)
-				m.input.SetValue("")
+			}
+
+		case tea.KeyTab:
+			m.input.SetValue(m.completer.complete(m.input.Value(), m.players))
+			m.input.CursorEnd()
+			// Keep the textinput from seeing the Tab.
+			return m, tea.Batch(cmds...)
+
+		case tea.KeyCtrlR:
+			m.search = &reverseSearch{saved: m.input.Value(), idx: len(m.hist.entries)}
+			return m, tea.Batch(cmds...)
+
+		case tea.KeyRunes:
+			if msg.String() == "/" && m.input.Value() == "" {
+				m.find = &logFind{editing: true}
+				return m, tea.Batch(cmds...)
+			}
+
+		case tea.KeyCtrlP, tea.KeyCtrlN:
+			if m.find != nil {
+				delta := -1
+				if msg.Type == tea.KeyCtrlN {
+					delta = 1
+				}
+				m.stepFind(delta)
+				return m, tea.Batch(cmds...)
+			}
+
+		case tea.KeyF2, tea.KeyF3, tea.KeyF4:
+			m.filters.toggle(map[tea.KeyType]string{
+				tea.KeyF2: "chat", tea.KeyF3: "plugins", tea.KeyF4: "errors",
+			}[msg.Type])
+			m.refresh()
+			return m, tea.Batch(cmds...)
+
+		case tea.KeyF5:
+			m.showSidebar = !m.showSidebar
+			m.resize()
+			return m, tea.Batch(cmds...)
+
+		case tea.KeyEnd:
+			// End also moves the cursor to the end of the input line, so
+			// let the textinput see it too.
+			if m.ready {
+				m.viewport.GotoBottom()
+				m.unseen = 0
 			}
 
 		case tea.KeyPgUp, tea.KeyPgDown:
 			var vpCmd tea.Cmd
 			m.viewport, vpCmd = m.viewport.Update(msg)
 			cmds = append(cmds, vpCmd)
+			if m.viewport.AtBottom() {
+				m.unseen = 0
+			}
 
 		default:
 		}
+
+	case tea.MouseMsg:
+		if m.ready {
+			var vpCmd tea.Cmd
+			m.viewport, vpCmd = m.viewport.Update(msg)
+			cmds = append(cmds, vpCmd)
+			if m.viewport.AtBottom() {
+				m.unseen = 0
+			}
+		}
+		return m, tea.Batch(cmds...)
 	}
 
 	// Update text input.
@@ -218,20 +328,130 @@ func (m model) View() string { //nolint:gocritic
 	}
 
 	title := titleStyle.Render(" MC Dad Server Console ")
-	statusText := statusBarStyle.Render(
-		fmt.Sprintf(" %s | Ctrl+C to exit | PgUp/PgDn to scroll", m.opts.Dir))
+	status := []string{m.logSource}
+	if f := m.filters.String(); f != "" {
+		status = append(status, "filter: "+f)
+	}
+	if m.find != nil && !m.find.editing {
+		if s := m.find.status(m.currentMatch()); s != "" {
+			status = append(status, s+" (Ctrl+P/Ctrl+N, Esc to clear)")
+		}
+	}
+	if !m.viewport.AtBottom() {
+		status = append(status, fmt.Sprintf("PAUSED, %d new (End to follow)", m.unseen))
+	}
+	status = append(status, "Ctrl+C to exit | PgUp/PgDn to scroll | / to find | F2 chat F3 plugins F4 errors | F5 sidebar | Tab to complete | Ctrl+R history")
+	statusText := statusBarStyle.Render(" " + strings.Join(status, " | "))
 
 	// Pad title bar to full width.
 	titleBar := title + strings.Repeat(" ", max(0, m.width-lipgloss.Width(title)))
 
+	inputLine := m.input.View()
+	switch {
+	case m.search != nil:
+		inputLine = m.search.view()
+	case m.find != nil && m.find.editing:
+		inputLine = promptStyle.Render("/") + m.find.query
+	}
+
+	body := m.viewport.View()
+	if m.sidebarVisible() {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, body, m.sidebar(m.viewport.Height))
+	}
+
 	return fmt.Sprintf("%s\n%s\n%s\n%s",
 		titleBar,
-		m.viewport.View(),
-		m.input.View(),
+		body,
+		inputLine,
 		statusText,
 	)
 }
 
+// resize fits the log view to the terminal, beside the sidebar when it is
+// shown.
+func (m *model) resize() {
+	// Reserve lines: 1 title bar + 1 input + 1 status bar.
+	viewHeight := max(m.height-3, 1)
+	viewWidth := m.width
+	if m.sidebarVisible() {
+		viewWidth -= sidebarWidth
+	}
+
+	if !m.ready {
+		m.viewport = viewport.New(viewWidth, viewHeight)
+		m.ready = true
+		m.refresh()
+		m.viewport.GotoBottom()
+		return
+	}
+	m.viewport.Width = viewWidth
+	m.viewport.Height = viewHeight
+}
+
+// showOutput adds a command the user ran and its output to the view, and
+// scrolls to them.
+func (m *model) showOutput(input, output string) {
+	m.buf.add(input, kindPrompt)
+	if output != "" {
+		for line := range strings.SplitSeq(output, "\n") {
+			m.buf.add(line, kindOutput)
+		}
+	}
+	m.refresh()
+	if m.ready {
+		m.viewport.GotoBottom()
+		m.unseen = 0
+	}
+}
+
+// refresh rebuilds the view from the buffer, applying the filters and
+// search highlights. A view scrolled to the bottom follows new lines; one
+// scrolled up stays on the lines it was showing.
+func (m *model) refresh() {
+	if !m.ready {
+		return
+	}
+	follow := m.viewport.AtBottom()
+	top := 0
+	if p := m.viewport.YOffset; p < len(m.shown) && m.shown[p] < len(m.buf.lines) {
+		top = m.buf.lines[m.shown[p]].seq
+	}
+
+	m.shown = m.shown[:0]
+	for i := range m.buf.lines {
+		if m.filters.show(&m.buf.lines[i]) {
+			m.shown = append(m.shown, i)
+		}
+	}
+
+	query := ""
+	if m.find != nil {
+		query = m.find.query
+		m.find.matches = m.find.matches[:0]
+	}
+	rendered := make([]string, len(m.shown))
+	offset := 0
+	for p, i := range m.shown {
+		l := &m.buf.lines[i]
+		matched := query != "" && indexFold(l.text, query) >= 0
+		if matched {
+			m.find.matches = append(m.find.matches, p)
+		}
+		rendered[p] = l.view(query, matched, matched && l.seq == m.find.current)
+		if l.seq <= top {
+			offset = p
+		}
+	}
+	m.viewport.SetContent(strings.Join(rendered, "\n"))
+
+	if follow {
+		m.viewport.GotoBottom()
+		m.unseen = 0
+	} else {
+		m.viewport.SetYOffset(offset)
+	}
+}
+
 func (m model) runCommand(input string) tea.Cmd { //nolint:gocritic
 	ctx := m.ctx
 	opts := m.opts
@@ -245,6 +465,7 @@ func (m model) runCommand(input string) tea.Cmd { //nolint:gocritic
 // Run starts the interactive console TUI.
 func Run(opts *Options, runner platform.CommandRunner) error {
 	m := newModel(opts, runner)
+	defer m.dashboard.close()
 	defer m.cancel()
 	p := tea.NewProgram(
 		m,
@@ -0,0 +1,335 @@
+package console
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/serverctl"
+)
+
+// dashboardRefresh is how often the sidebar, and the online player list
+// used for tab completion, are refreshed.
+const dashboardRefresh = 5 * time.Second
+
+// sidebarWidth is the width of the sidebar including its border, and
+// sidebarMinWidth the narrowest terminal that still shows it beside a
+// readable log.
+const (
+	sidebarWidth    = 30
+	sidebarMinWidth = 100
+)
+
+// Sidebar styles.
+var (
+	sidebarStyle = lipgloss.NewStyle().
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderLeft(true).
+			BorderForeground(lipgloss.Color("241")).
+			PaddingLeft(1).
+			Width(sidebarWidth - 1)
+
+	headingStyle = lipgloss.NewStyle().Bold(true)
+	dimStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// dashboard is a snapshot of the server for the sidebar.
+type dashboard struct {
+	running bool
+	// players is nil when the server could not be asked.
+	players *management.PlayerList
+	// resources are "CPU: 5.2%" style lines from the container runtime or
+	// the process table.
+	resources []string
+	tps       []float64
+	// started is when the server started, or zero if unknown.
+	started    time.Time
+	lastBackup *management.BackupStatus
+	// nextJob is the mc-dad-server cron job that runs next, at nextRun.
+	nextJob string
+	nextRun time.Time
+}
+
+// dashboardMsg carries a fresh dashboard.
+type dashboardMsg struct {
+	dash dashboard
+}
+
+// dashboardSource gathers dashboards. It keeps one manager between
+// refreshes so the server isn't sent a new RCON login, and a log line about
+// it, every few seconds.
+type dashboardSource struct {
+	opts   *Options
+	runner platform.CommandRunner
+
+	// mu guards res: a fetch can still be running when the console exits.
+	mu  sync.Mutex
+	res *serverctl.Resolved
+}
+
+// fetchDashboard returns a tea.Cmd that waits delay, then collects a
+// dashboard.
+func fetchDashboard(ctx context.Context, src *dashboardSource, delay time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		return dashboardMsg{dash: src.collect(ctx)}
+	}
+}
+
+// collect takes a snapshot of the server.
+func (s *dashboardSource) collect(ctx context.Context) dashboard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var d dashboard
+	d.lastBackup, _ = management.LoadBackupStatus(s.opts.Dir)
+	if jobs, err := platform.ScheduledJobs(ctx, s.runner); err == nil {
+		for i := range jobs {
+			next := jobs[i].Next(now)
+			if !next.IsZero() && (d.nextRun.IsZero() || next.Before(d.nextRun)) {
+				d.nextJob, d.nextRun = jobs[i].Name, next
+			}
+		}
+	}
+
+	if s.res == nil {
+		res := serverctl.Resolve(ctx, target(s.opts), s.runner)
+		s.res = &res
+	}
+	mgr := s.res.Manager
+	d.running = management.IsServerRunning(ctx, mgr, s.runner, optsToConfig(s.opts).Port)
+	if !d.running {
+		// Resolve again next time: in auto mode, the server may come
+		// back in a container rather than a screen session.
+		s.release()
+		return d
+	}
+
+	if hc, ok := mgr.(management.HealthChecker); ok {
+		if stats, err := hc.Stats(ctx); err == nil {
+			for part := range strings.SplitSeq(stats, "  ") {
+				if part = strings.TrimSpace(part); part != "" {
+					d.resources = append(d.resources, part)
+				}
+			}
+		}
+	} else if stats, err := management.GetProcessStats(ctx, s.runner); err == nil && stats.PID > 0 {
+		d.resources = []string{"CPU: " + stats.CPU, "MEM: " + stats.Memory}
+		if stats.Uptime > 0 {
+			d.started = now.Add(-stats.Uptime)
+		}
+	}
+	if sr, ok := mgr.(management.StartTimeReporter); ok {
+		if t, err := sr.StartedAt(ctx); err == nil {
+			d.started = t
+		}
+	}
+
+	if q, ok := mgr.(management.Querier); ok {
+		if list, err := management.ListPlayers(ctx, q); err == nil {
+			d.players = &list
+			// Only Paper answers "tps"; vanilla's reply doesn't parse.
+			if reply, err := q.Query(ctx, "tps"); err == nil {
+				d.tps, _ = management.ParseTPS(reply)
+			}
+		}
+	}
+	return d
+}
+
+// close releases the manager once any fetch in progress has finished.
+func (s *dashboardSource) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.release()
+}
+
+func (s *dashboardSource) release() {
+	if s.res != nil {
+		_ = s.res.Close()
+		s.res = nil
+	}
+}
+
+// session is when an online player joined. exact is false for players who
+// were already online when the console started, whose join wasn't seen.
+type session struct {
+	since time.Time
+	exact bool
+}
+
+// trackSession follows joins and leaves in the log.
+func (m *model) trackSession(ev events.Event) {
+	switch ev.Kind {
+	case events.Join:
+		since := ev.Time
+		// Log lines carry only the time of day; a join from before
+		// midnight parses as later today.
+		if since.After(time.Now()) {
+			since = since.AddDate(0, 0, -1)
+		}
+		m.sessions[ev.Player] = session{since: since, exact: true}
+	case events.Leave:
+		delete(m.sessions, ev.Player)
+	case events.ServerReady, events.ServerStopping:
+		clear(m.sessions)
+	}
+}
+
+// syncSessions reconciles the tracked sessions with the server's player
+// list.
+func (m *model) syncSessions(online []string) {
+	for name := range m.sessions {
+		if !slices.Contains(online, name) {
+			delete(m.sessions, name)
+		}
+	}
+	for _, name := range online {
+		if _, ok := m.sessions[name]; !ok {
+			m.sessions[name] = session{since: time.Now()}
+		}
+	}
+}
+
+// sidebarVisible reports whether the sidebar is shown: it is switched on
+// and the terminal is wide enough.
+func (m *model) sidebarVisible() bool {
+	return m.showSidebar && m.width >= sidebarMinWidth
+}
+
+// sidebar renders the dashboard at the given height.
+func (m *model) sidebar(height int) string {
+	d := m.dash
+	now := time.Now()
+	inner := sidebarWidth - 2
+	var lines []string
+	row := func(label, value string) {
+		lines = append(lines, truncate(fmt.Sprintf("%-7s %s", label, value), inner))
+	}
+
+	if !m.dashReady {
+		lines = append(lines, dimStyle.Render("Loading..."))
+	} else {
+		lines = append(lines, headingStyle.Render("Server"))
+		if d.running {
+			row("Status", joinStyle.Render("running"))
+		} else {
+			row("Status", errorStyle.Render("stopped"))
+		}
+		if !d.started.IsZero() {
+			row("Uptime", formatDuration(now.Sub(d.started)))
+		}
+		for _, r := range d.resources {
+			label, value, _ := strings.Cut(r, ": ")
+			row(label, value)
+		}
+		if len(d.tps) > 0 {
+			row("TPS", fmt.Sprintf("%.1f %.1f %.1f", d.tps[0], d.tps[1], d.tps[2]))
+		}
+
+		lines = append(lines, "", headingStyle.Render("Last backup"))
+		switch b := d.lastBackup; {
+		case b == nil:
+			lines = append(lines, dimStyle.Render("none yet"))
+		case b.Success:
+			lines = append(lines, formatWhen(b.Time, now))
+		default:
+			lines = append(lines, errorStyle.Render("failed "+formatWhen(b.Time, now)))
+		}
+		if d.nextJob != "" {
+			lines = append(lines, "", headingStyle.Render("Next job"))
+			lines = append(lines, truncate(fmt.Sprintf("%s in %s", d.nextJob, formatDuration(d.nextRun.Sub(now))), inner))
+		}
+
+		if d.players != nil {
+			lines = append(lines, "", headingStyle.Render(fmt.Sprintf("Players %d/%d", d.players.Online, d.players.Max)))
+			lines = append(lines, m.playerLines(height-len(lines), inner, now)...)
+		}
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	return sidebarStyle.Height(height).Render(strings.Join(lines, "\n"))
+}
+
+// playerLines lists online players, longest session first, in at most room
+// lines.
+func (m *model) playerLines(room, width int, now time.Time) []string {
+	names := slices.SortedFunc(maps.Keys(m.sessions), func(a, b string) int {
+		return m.sessions[a].since.Compare(m.sessions[b].since)
+	})
+	var lines []string
+	for i, name := range names {
+		if len(lines) == room-1 && i < len(names)-1 {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("+%d more", len(names)-i)))
+			break
+		}
+		s := m.sessions[name]
+		played := formatDuration(now.Sub(s.since))
+		if !s.exact {
+			// Already online when the console started.
+			played = ">" + played
+		}
+		lines = append(lines, fmt.Sprintf("%-*s %7s", width-8, truncate(name, width-8), played))
+	}
+	return lines
+}
+
+// formatDuration formats d compactly: "45s", "12m", "3h05m", "2d4h".
+func formatDuration(d time.Duration) string {
+	d = max(d, 0)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+}
+
+// formatWhen formats t relative to now's date: "today 04:00",
+// "yesterday 04:00" or "Oct 12 04:00".
+func formatWhen(t, now time.Time) string {
+	t = t.In(now.Location())
+	y, mo, d := now.Date()
+	today := time.Date(y, mo, d, 0, 0, 0, 0, now.Location())
+	switch {
+	case !t.Before(today):
+		return "today " + t.Format("15:04")
+	case !t.Before(today.AddDate(0, 0, -1)):
+		return "yesterday " + t.Format("15:04")
+	}
+	return t.Format("Jan 2 15:04")
+}
+
+// truncate shortens s to n runes of plain text, marking the cut with "…".
+// Strings holding styles are measured by their visible width and left alone
+// when they fit.
+func truncate(s string, n int) string {
+	if lipgloss.Width(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,135 @@
+package console
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+)
+
+func TestDashboardCollectScreenMode(t *testing.T) {
+	runner := platform.NewMockRunner()
+	runner.OutputMap["screen [-list]"] = []byte("There is a screen on:\n\t4242.minecraft\t(Detached)\n")
+	runner.OutputMap["pgrep [-f server.jar]"] = []byte("4243\n")
+	runner.OutputMap["ps [-o rss= -p 4243]"] = []byte("2097152\n")
+	runner.OutputMap["ps [-o %cpu= -p 4243]"] = []byte("12.5\n")
+	runner.OutputMap["ps [-o etime= -p 4243]"] = []byte("01:30:00\n")
+	runner.OutputMap["crontab [-l]"] = []byte("0 4 * * * mc-dad-server backup\n")
+
+	src := &dashboardSource{opts: &Options{Dir: t.TempDir(), Session: "minecraft", Mode: "screen"}, runner: runner}
+	defer src.close()
+	d := src.collect(t.Context())
+
+	if !d.running {
+		t.Fatal("server should be running")
+	}
+	if got := strings.Join(d.resources, ", "); got != "CPU: 12.5%, MEM: 2048 MB" {
+		t.Errorf("resources = %q", got)
+	}
+	if up := time.Since(d.started); up < 90*time.Minute || up > 91*time.Minute {
+		t.Errorf("uptime = %v, want about 1h30m", up)
+	}
+	if d.nextJob != "backup" || d.nextRun.Hour() != 4 {
+		t.Errorf("next job = %q at %v", d.nextJob, d.nextRun)
+	}
+	if d.lastBackup != nil {
+		t.Errorf("lastBackup = %+v, want none", d.lastBackup)
+	}
+}
+
+func TestSessionsFollowJoinsAndPlayerList(t *testing.T) {
+	m := &model{sessions: make(map[string]session)}
+	joined := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	m.trackSession(events.Event{Kind: events.Join, Player: "Steve", Time: joined})
+	m.trackSession(events.Event{Kind: events.Join, Player: "Alex", Time: joined})
+	m.trackSession(events.Event{Kind: events.Leave, Player: "Alex"})
+	// Herobrine was online before the console started, so their join
+	// wasn't seen.
+	m.syncSessions([]string{"Steve", "Herobrine"})
+
+	if s := m.sessions["Steve"]; !s.exact || !s.since.Equal(joined) {
+		t.Errorf("Steve = %+v, want exact join at %v", s, joined)
+	}
+	if s, ok := m.sessions["Herobrine"]; !ok || s.exact {
+		t.Errorf("Herobrine = %+v, %v; want an approximate session", s, ok)
+	}
+	if _, ok := m.sessions["Alex"]; ok {
+		t.Error("Alex left and should have no session")
+	}
+
+	m.syncSessions([]string{"Herobrine"})
+	if _, ok := m.sessions["Steve"]; ok {
+		t.Error("Steve is no longer listed and should have no session")
+	}
+}
+
+func TestSidebarFitsHeight(t *testing.T) {
+	m := &model{
+		width:       120,
+		showSidebar: true,
+		dashReady:   true,
+		sessions:    make(map[string]session),
+		dash: dashboard{
+			running:   true,
+			players:   &management.PlayerList{Online: 12, Max: 20},
+			resources: []string{"CPU: 5.23%", "MEM: 512MiB / 16GiB"},
+			tps:       []float64{20, 19.9, 20},
+		},
+	}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		m.sessions[name] = session{since: time.Now(), exact: true}
+	}
+
+	const height = 15
+	out := m.sidebar(height)
+	if got := strings.Count(out, "\n") + 1; got != height {
+		t.Fatalf("sidebar is %d lines, want %d:\n%s", got, height, out)
+	}
+	if !strings.Contains(out, "more") {
+		t.Errorf("overflowing player list should end with a count:\n%s", out)
+	}
+
+	m.width = 80
+	if m.sidebarVisible() {
+		t.Error("sidebar should collapse on a narrow terminal")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{45 * time.Second, "45s"},
+		{12 * time.Minute, "12m"},
+		{3*time.Hour + 5*time.Minute, "3h05m"},
+		{52 * time.Hour, "2d4h"},
+		{-time.Minute, "0s"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatWhen(t *testing.T) {
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2026, 10, 18, 4, 0, 0, 0, time.UTC), "today 04:00"},
+		{time.Date(2026, 10, 17, 4, 0, 0, 0, time.UTC), "yesterday 04:00"},
+		{time.Date(2026, 10, 12, 4, 0, 0, 0, time.UTC), "Oct 12 04:00"},
+	}
+	for _, tt := range tests {
+		if got := formatWhen(tt.t, now); got != tt.want {
+			t.Errorf("formatWhen(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
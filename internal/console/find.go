@@ -0,0 +1,117 @@
+package console
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// logFind is the state of a `/` search through the log view, which works
+// like the one in less: matches are highlighted, and Ctrl+P and Ctrl+N step
+// to the previous (older) and next (newer) one.
+type logFind struct {
+	query string
+	// editing is set while the query is being typed.
+	editing bool
+	// matches are the positions of matching lines in the view.
+	matches []int
+	// current is the seq of the line with the selected match, or 0.
+	current int
+}
+
+// updateFind handles a key press while the find query is being typed.
+func (m *model) updateFind(msg tea.KeyMsg) {
+	f := m.find
+	switch msg.Type {
+	case tea.KeyEnter:
+		f.editing = false
+		return
+
+	case tea.KeyEsc, tea.KeyCtrlG:
+		m.find = nil
+		m.refresh()
+		return
+
+	case tea.KeyBackspace:
+		if f.query == "" {
+			m.find = nil
+			m.refresh()
+			return
+		}
+		r := []rune(f.query)
+		f.query = string(r[:len(r)-1])
+
+	case tea.KeyRunes, tea.KeySpace:
+		f.query += string(msg.Runes)
+
+	default:
+		return
+	}
+	// The query changed: select the newest match at or above the bottom of
+	// the view, so the search starts from what's on screen.
+	f.current = 0
+	m.refresh()
+	bottom := m.viewport.YOffset + m.viewport.Height - 1
+	sel := -1
+	for i, p := range f.matches {
+		if p <= bottom || sel < 0 {
+			sel = i
+		}
+		if p > bottom {
+			break
+		}
+	}
+	m.selectMatch(sel)
+}
+
+// stepFind moves the selected match by delta: -1 for older, +1 for newer.
+// It wraps around at either end.
+func (m *model) stepFind(delta int) {
+	f := m.find
+	if len(f.matches) == 0 {
+		return
+	}
+	i := m.currentMatch()
+	if i < 0 {
+		i = len(f.matches)
+		if delta > 0 {
+			i = -1
+		}
+	}
+	m.selectMatch((i + delta + len(f.matches)) % len(f.matches))
+}
+
+// currentMatch returns the index in matches of the selected match, or -1.
+func (m *model) currentMatch() int {
+	for i, p := range m.find.matches {
+		if m.buf.lines[m.shown[p]].seq == m.find.current {
+			return i
+		}
+	}
+	return -1
+}
+
+// selectMatch selects matches[i] and scrolls it to the middle of the view.
+func (m *model) selectMatch(i int) {
+	f := m.find
+	if i < 0 || i >= len(f.matches) {
+		return
+	}
+	p := f.matches[i]
+	f.current = m.buf.lines[m.shown[p]].seq
+	m.refresh()
+	m.viewport.SetYOffset(p - m.viewport.Height/2)
+}
+
+// status describes the search for the status bar.
+func (f *logFind) status(current int) string {
+	switch {
+	case f.query == "":
+		return ""
+	case len(f.matches) == 0:
+		return fmt.Sprintf("%q: no matches", f.query)
+	case current < 0:
+		return fmt.Sprintf("%q: %d matches", f.query, len(f.matches))
+	}
+	return fmt.Sprintf("%q: %d/%d", f.query, current+1, len(f.matches))
+}
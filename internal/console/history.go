@@ -0,0 +1,109 @@
+package console
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// historyFile keeps console input across sessions, one entry per line, in
+// the server directory so each server has its own.
+const historyFile = ".console_history"
+
+// maxHistory bounds the entries kept in memory and rewritten on load.
+const maxHistory = 1000
+
+// history is the console's input history with shell-style navigation.
+type history struct {
+	path    string
+	entries []string
+	// idx is the entry shown by up/down navigation; len(entries) means
+	// the fresh input line below the newest entry.
+	idx int
+}
+
+// loadHistory reads the history file in serverDir. A missing or unreadable
+// file starts an empty history.
+func loadHistory(serverDir string) *history {
+	h := &history{path: filepath.Join(serverDir, historyFile)}
+	if f, err := os.Open(h.path); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				h.entries = append(h.entries, line)
+			}
+		}
+		_ = f.Close()
+	}
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[len(h.entries)-maxHistory:]
+		h.rewrite()
+	}
+	h.idx = len(h.entries)
+	return h
+}
+
+// add records an entry and appends it to the history file. Repeating the
+// previous entry is not recorded twice.
+func (h *history) add(entry string) {
+	defer func() { h.idx = len(h.entries) }()
+	if strings.ContainsAny(entry, "\r\n") {
+		return
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1] == entry {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[1:]
+	}
+
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		// History is a convenience; a read-only server dir shouldn't
+		// break the console.
+		return
+	}
+	defer func() { _ = f.Close() }()
+	_, _ = f.WriteString(entry + "\n")
+}
+
+// rewrite replaces the history file with the in-memory entries.
+func (h *history) rewrite() {
+	data := strings.Join(h.entries, "\n") + "\n"
+	_ = os.WriteFile(h.path, []byte(data), 0o600)
+}
+
+// prev moves to the previous (older) entry, reporting false at the oldest.
+func (h *history) prev() (string, bool) {
+	if h.idx == 0 || len(h.entries) == 0 {
+		return "", false
+	}
+	h.idx--
+	return h.entries[h.idx], true
+}
+
+// next moves to the next (newer) entry. Moving past the newest returns the
+// empty fresh line.
+func (h *history) next() (string, bool) {
+	if h.idx >= len(h.entries) {
+		return "", false
+	}
+	h.idx++
+	if h.idx == len(h.entries) {
+		return "", true
+	}
+	return h.entries[h.idx], true
+}
+
+// search returns the newest entry before index before that contains query,
+// and its index. Pass len(entries) to search from the newest entry.
+func (h *history) search(query string, before int) (string, int, bool) {
+	for i := min(before, len(h.entries)) - 1; i >= 0; i-- {
+		if strings.Contains(h.entries[i], query) {
+			return h.entries[i], i, true
+		}
+	}
+	return "", -1, false
+}
@@ -0,0 +1,96 @@
+package console
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHistoryPersistsAcrossSessions(t *testing.T) {
+	dir := t.TempDir()
+
+	h := loadHistory(dir)
+	h.add("status")
+	h.add("cmd list")
+	h.add("cmd list") // repeated entries are stored once
+
+	h = loadHistory(dir)
+	if got := strings.Join(h.entries, ","); got != "status,cmd list" {
+		t.Fatalf("reloaded entries = %q", got)
+	}
+
+	// Up walks back, down walks forward to a fresh line.
+	for _, want := range []string{"cmd list", "status"} {
+		if got, ok := h.prev(); !ok || got != want {
+			t.Fatalf("prev() = %q, %v; want %q", got, ok, want)
+		}
+	}
+	if _, ok := h.prev(); ok {
+		t.Fatal("prev() past the oldest entry should report false")
+	}
+	if got, _ := h.next(); got != "cmd list" {
+		t.Fatalf("next() = %q, want %q", got, "cmd list")
+	}
+	if got, ok := h.next(); !ok || got != "" {
+		t.Fatalf("next() past the newest = %q, %v; want the empty line", got, ok)
+	}
+}
+
+func TestHistoryTrimsOnLoad(t *testing.T) {
+	dir := t.TempDir()
+	var b strings.Builder
+	for range maxHistory + 10 {
+		b.WriteString("status\n")
+	}
+	b.WriteString("newest\n")
+	if err := os.WriteFile(filepath.Join(dir, historyFile), []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	h := loadHistory(dir)
+	if len(h.entries) != maxHistory || h.entries[len(h.entries)-1] != "newest" {
+		t.Fatalf("got %d entries ending %q, want %d ending newest", len(h.entries), h.entries[len(h.entries)-1], maxHistory)
+	}
+}
+
+func TestReverseSearch(t *testing.T) {
+	m := &model{
+		input: textinput.New(),
+		hist:  &history{entries: []string{"cmd time set day", "status", "cmd time set night", "backup"}},
+	}
+	m.input.SetValue("draft")
+	m.search = &reverseSearch{saved: m.input.Value(), idx: len(m.hist.entries)}
+
+	for _, r := range "time" {
+		m.updateSearch(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if m.search.match != "cmd time set night" {
+		t.Fatalf("match = %q, want the newest entry containing time", m.search.match)
+	}
+
+	m.updateSearch(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if m.search.match != "cmd time set day" {
+		t.Fatalf("after Ctrl+R match = %q, want the older entry", m.search.match)
+	}
+
+	m.updateSearch(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.search != nil || m.input.Value() != "cmd time set day" {
+		t.Fatalf("Enter should accept the match into the input, got %q", m.input.Value())
+	}
+
+	// Esc restores what was typed before the search.
+	m.input.SetValue("draft")
+	m.search = &reverseSearch{saved: "draft", idx: len(m.hist.entries)}
+	m.updateSearch(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("zzz")})
+	if !m.search.failed {
+		t.Fatal("search for a missing entry should be marked failed")
+	}
+	m.updateSearch(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.search != nil || m.input.Value() != "draft" {
+		t.Fatalf("Esc should restore the input, got %q", m.input.Value())
+	}
+}
@@ -1,115 +1,54 @@
 package console
 
 import (
-	"bufio"
 	"context"
-	"io"
-	"os"
-	"time"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/container"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/logtail"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/serverctl"
 )
 
-// logReadMsg carries a log line and the file offset for the next read.
+// replayLines is how much recent log is shown when the console attaches to
+// a running server.
+const replayLines = 100
+
+// logReadMsg carries a log line from the follower.
 type logReadMsg struct {
-	line   string
-	offset int64
+	line logtail.Line
 }
 
-// scanBufInitial is the initial size of the bufio.Scanner buffer for log lines.
-const scanBufInitial = 64 * 1024
-
-// scanBufMax is the maximum token size accepted from a log line. Lines longer
-// than this are split across multiple reads.
-const scanBufMax = 1024 * 1024
-
-// tailLog returns a tea.Cmd that waits for the log file to appear, then reads
-// the first new line from the end. Subsequent lines are read via nextLogLine.
-func tailLog(ctx context.Context, path string) tea.Cmd {
-	return func() tea.Msg {
-		// Wait for the file to appear.
-		var startOffset int64
-		for {
-			info, err := os.Stat(path)
-			if err == nil {
-				startOffset = info.Size()
-				break
-			}
-			select {
-			case <-ctx.Done():
-				return nil
-			case <-time.After(500 * time.Millisecond):
-			}
+// followServerLog starts following the server's log and describes where it
+// comes from. A screen-mode server writes <dir>/logs/latest.log. In container
+// mode that file lives inside the container, so the console reads the
+// mounted logs volume when it can, and otherwise streams the runtime's own
+// log output.
+func followServerLog(ctx context.Context, opts *Options, runner platform.CommandRunner) (<-chan logtail.Line, string) {
+	path := filepath.Join(opts.Dir, "logs", "latest.log")
+	tailOpts := logtail.Options{From: logtail.FromEnd, Replay: replayLines}
+
+	if serverctl.ResolveMode(ctx, target(opts), runner) == serverctl.ModeContainer {
+		cm := container.NewManager(runner, serverctl.DetectRuntime(runner), opts.Session, "", "")
+		if dir, ok := cm.LogsDir(ctx); ok {
+			path = filepath.Join(dir, "latest.log")
+		} else if lines, err := cm.FollowLogs(ctx, replayLines); err == nil {
+			return lines, "container " + opts.Session
 		}
-
-		// Now poll from end using the offset-based reader.
-		return readFromOffset(ctx, path, startOffset)
 	}
+	return logtail.Follow(ctx, path, tailOpts), path
 }
 
-// nextLogLine returns a tea.Cmd that waits for the next log line at the given
-// offset. It handles log rotation by resetting the offset when the file
-// shrinks.
-func nextLogLine(ctx context.Context, path string, offset int64) tea.Cmd {
+// waitForLogLine returns a tea.Cmd that waits for the next line from the
+// follower. It returns nil once the follower has stopped.
+func waitForLogLine(lines <-chan logtail.Line) tea.Cmd {
 	return func() tea.Msg {
-		return readFromOffset(ctx, path, offset)
-	}
-}
-
-// readFromOffset polls the file at the given offset until a complete line is
-// available. Returns a logReadMsg with the line and updated offset.
-func readFromOffset(ctx context.Context, path string, offset int64) tea.Msg {
-	for {
-		select {
-		case <-ctx.Done():
+		l, ok := <-lines
+		if !ok {
 			return nil
-		default:
-		}
-
-		f, err := os.Open(path)
-		if err != nil {
-			time.Sleep(500 * time.Millisecond)
-			continue
 		}
-
-		// Check if file was rotated (smaller than our offset).
-		info, err := f.Stat()
-		if err != nil {
-			_ = f.Close()
-			time.Sleep(100 * time.Millisecond)
-			continue
-		}
-		if info.Size() < offset {
-			offset = 0
-		}
-
-		if _, err := f.Seek(offset, io.SeekStart); err != nil {
-			_ = f.Close()
-			time.Sleep(100 * time.Millisecond)
-			continue
-		}
-
-		scanner := bufio.NewScanner(f)
-		scanner.Buffer(make([]byte, 0, scanBufInitial), scanBufMax)
-		if scanner.Scan() {
-			line := scanner.Text()
-			newOffset, err := f.Seek(0, io.SeekCurrent)
-			if err != nil {
-				_ = f.Close()
-				time.Sleep(100 * time.Millisecond)
-				continue
-			}
-			_ = f.Close()
-			return logReadMsg{line: line, offset: newOffset}
-		}
-
-		if err := scanner.Err(); err != nil {
-			_ = f.Close()
-			time.Sleep(100 * time.Millisecond)
-			continue
-		}
-
-		_ = f.Close()
-		time.Sleep(100 * time.Millisecond)
+		return logReadMsg{line: l}
 	}
 }
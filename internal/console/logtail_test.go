@@ -0,0 +1,38 @@
+package console
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+)
+
+func TestFollowServerLogScreenModeTailsFile(t *testing.T) {
+	opts := &Options{Dir: t.TempDir(), Session: "minecraft", Mode: "screen"}
+
+	_, source := followServerLog(t.Context(), opts, platform.NewMockRunner())
+	if want := filepath.Join(opts.Dir, "logs", "latest.log"); source != want {
+		t.Fatalf("source = %q, want %q", source, want)
+	}
+}
+
+func TestFollowServerLogContainerModeStreamsRuntimeLogs(t *testing.T) {
+	runner := platform.NewMockRunner()
+	runner.ExistsMap["podman"] = true
+	runner.OutputMap["podman [logs -f --tail 100 minecraft]"] = []byte("[18:00:05 INFO]: Done\n")
+	opts := &Options{Dir: t.TempDir(), Session: "minecraft", Mode: "container"}
+
+	lines, source := followServerLog(t.Context(), opts, runner)
+	if source != "container minecraft" {
+		t.Fatalf("source = %q, want the container stream", source)
+	}
+	select {
+	case l := <-lines:
+		if l.Text != "[18:00:05 INFO]: Done" {
+			t.Fatalf("line = %q", l.Text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a container log line")
+	}
+}
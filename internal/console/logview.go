@@ -0,0 +1,270 @@
+package console
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+)
+
+// lineKind classifies a console line for colouring and filtering.
+type lineKind int
+
+const (
+	kindPlain lineKind = iota
+	kindWarn
+	kindError
+	kindChat
+	kindJoin
+	kindPlugin
+	// kindPrompt is a command typed into the console; kindOutput is its
+	// result. Both are shown whatever the filters.
+	kindPrompt
+	kindOutput
+)
+
+// Line styles.
+var (
+	warnStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	errorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	chatStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
+	joinStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	pluginStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+	matchStyle   = lipgloss.NewStyle().Background(lipgloss.Color("238"))
+	currentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("11"))
+)
+
+// pluginTag matches the "[PluginName] " that Bukkit-style plugins put at
+// the start of their log messages.
+var pluginTag = regexp.MustCompile(`^\[[\w.-]+\] `)
+
+// logLine is one line held by the console.
+type logLine struct {
+	text string
+	kind lineKind
+	// seq numbers lines in arrival order, so positions survive trimming
+	// and filter changes.
+	seq int
+	// styled caches the line rendered without search highlights.
+	styled string
+}
+
+// logBuffer holds the console's recent lines.
+type logBuffer struct {
+	lines  []logLine
+	parser *events.Parser
+	seq    int
+	// last is the kind of the last server log line, inherited by stack
+	// trace lines that follow a warning or error.
+	last lineKind
+}
+
+// addLog appends a server log line, returning the game event it records,
+// if any.
+func (b *logBuffer) addLog(text string) (events.Event, bool) {
+	kind, ev, ok := b.classify(text)
+	b.last = kind
+	b.add(text, kind)
+	return ev, ok
+}
+
+// add appends a line of the given kind, dropping the oldest beyond
+// maxConsoleLines.
+func (b *logBuffer) add(text string, kind lineKind) {
+	b.seq++
+	b.lines = append(b.lines, logLine{text: text, kind: kind, seq: b.seq})
+	if len(b.lines) > maxConsoleLines {
+		b.lines = b.lines[len(b.lines)-maxConsoleLines:]
+	}
+}
+
+// reset empties the buffer.
+func (b *logBuffer) reset() {
+	b.lines = nil
+	b.last = kindPlain
+}
+
+// classify works out what a server log line is, and parses the event it
+// records.
+func (b *logBuffer) classify(text string) (lineKind, events.Event, bool) {
+	thread, level, msg, ok := events.SplitPrefix(text)
+	if !ok {
+		// Stack traces and other continuation lines belong to the line
+		// above them.
+		if b.last == kindWarn || b.last == kindError {
+			return b.last, events.Event{}, false
+		}
+		return kindPlain, events.Event{}, false
+	}
+	ev, isEvent := b.parser.Parse(text, time.Now())
+	switch {
+	case level == "ERROR" || level == "FATAL" || level == "SEVERE":
+		return kindError, ev, isEvent
+	case level == "WARN" || level == "WARNING":
+		return kindWarn, ev, isEvent
+	case isEvent && ev.Kind == events.Chat:
+		return kindChat, ev, true
+	case isEvent && (ev.Kind == events.Join || ev.Kind == events.Leave):
+		return kindJoin, ev, true
+	case pluginTag.MatchString(msg) || strings.HasPrefix(thread, "Craft Scheduler"):
+		return kindPlugin, ev, isEvent
+	}
+	return kindPlain, ev, isEvent
+}
+
+// filters are the toggleable views of the console buffer.
+type filters struct {
+	chatOnly    bool
+	errorsOnly  bool
+	hidePlugins bool
+}
+
+// show reports whether l passes the filters.
+func (f filters) show(l *logLine) bool {
+	switch {
+	case l.kind == kindPrompt || l.kind == kindOutput:
+		return true
+	case f.chatOnly:
+		return l.kind == kindChat
+	case f.errorsOnly:
+		return l.kind == kindError
+	case f.hidePlugins:
+		return l.kind != kindPlugin
+	}
+	return true
+}
+
+// toggle switches the named filter, reporting false for an unknown name.
+// Chat only and errors only exclude each other.
+func (f *filters) toggle(name string) bool {
+	switch name {
+	case "chat":
+		f.chatOnly = !f.chatOnly
+		f.errorsOnly = false
+	case "errors":
+		f.errorsOnly = !f.errorsOnly
+		f.chatOnly = false
+	case "plugins":
+		f.hidePlugins = !f.hidePlugins
+	case "off":
+		*f = filters{}
+	default:
+		return false
+	}
+	return true
+}
+
+// String describes the active filters for the status bar.
+func (f filters) String() string {
+	var parts []string
+	if f.chatOnly {
+		parts = append(parts, "chat only")
+	}
+	if f.errorsOnly {
+		parts = append(parts, "errors only")
+	}
+	if f.hidePlugins {
+		parts = append(parts, "no plugins")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// filterNames are the arguments of the filter command.
+var filterNames = []string{"chat", "errors", "plugins", "off"}
+
+// view returns l rendered for display, highlighting query when matched is
+// set.
+func (l *logLine) view(query string, matched, current bool) string {
+	if matched {
+		return l.render(query, current)
+	}
+	if l.styled == "" {
+		l.styled = l.render("", false)
+	}
+	return l.styled
+}
+
+// render styles l, highlighting occurrences of query. current marks the
+// line holding the selected search match.
+func (l *logLine) render(query string, current bool) string {
+	if l.kind == kindPrompt {
+		return promptStyle.Render("> ") + highlight(l.text, query, lipgloss.NewStyle(), current)
+	}
+	var style lipgloss.Style
+	switch l.kind {
+	case kindWarn:
+		style = warnStyle
+	case kindError:
+		style = errorStyle
+	case kindChat:
+		style = chatStyle
+	case kindJoin:
+		style = joinStyle
+	case kindPlugin:
+		style = pluginStyle
+	default:
+		if query == "" {
+			return l.text
+		}
+	}
+	return highlight(l.text, query, style, current)
+}
+
+// highlight renders text in style with case-insensitive matches of query
+// picked out.
+func highlight(text, query string, style lipgloss.Style, current bool) string {
+	mark := matchStyle
+	if current {
+		mark = currentStyle
+	}
+	var b strings.Builder
+	rest := text
+	for query != "" {
+		i := indexFold(rest, query)
+		if i < 0 {
+			break
+		}
+		if i > 0 {
+			b.WriteString(style.Render(rest[:i]))
+		}
+		b.WriteString(mark.Render(rest[i : i+len(query)]))
+		rest = rest[i+len(query):]
+	}
+	if rest != "" {
+		b.WriteString(style.Render(rest))
+	}
+	return b.String()
+}
+
+// indexFold is strings.Index ignoring ASCII case. Log search is about
+// player names and English log text, so ASCII folding keeps byte offsets
+// into the original line valid.
+func indexFold(s, substr string) int {
+	n := len(substr)
+	for i := 0; i+n <= len(s); i++ {
+		if asciiEqualFold(s[i:i+n], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func asciiEqualFold(a, b string) bool {
+	for i := range len(a) {
+		ca, cb := a[i], b[i]
+		if 'A' <= ca && ca <= 'Z' {
+			ca += 'a' - 'A'
+		}
+		if 'A' <= cb && cb <= 'Z' {
+			cb += 'a' - 'A'
+		}
+		if ca != cb {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+package console
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+)
+
+func TestClassify(t *testing.T) {
+	b := &logBuffer{parser: events.NewParser()}
+
+	tests := []struct {
+		line string
+		want lineKind
+	}{
+		{"[10:00:00] [Server thread/INFO]: Starting minecraft server version 1.21.4", kindPlain},
+		{"[10:00:01] [Server thread/WARN]: Can't keep up! Is the server overloaded?", kindWarn},
+		{"[10:00:02] [Server thread/ERROR]: Encountered an unexpected exception", kindError},
+		{"\tat net.minecraft.server.MinecraftServer.run(MinecraftServer.java:100)", kindError},
+		{"[10:00:03] [Server thread/INFO]: Steve joined the game", kindJoin},
+		{"[10:00:04] [Server thread/INFO]: <Steve> hi dad", kindChat},
+		{"[10:00:05 INFO]: [LuckPerms] Loading configuration...", kindPlugin},
+		{"[10:00:06] [Craft Scheduler Thread - 3/INFO]: Saved 12 homes", kindPlugin},
+		{"[10:00:07] [Server thread/INFO]: Steve left the game", kindJoin},
+		{"a stray line after an info line", kindPlain},
+	}
+	for _, tt := range tests {
+		b.addLog(tt.line)
+		if got := b.lines[len(b.lines)-1].kind; got != tt.want {
+			t.Errorf("classify(%q) = %d, want %d", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestFilters(t *testing.T) {
+	lines := []logLine{
+		{kind: kindPlain},
+		{kind: kindChat},
+		{kind: kindError},
+		{kind: kindPlugin},
+		{kind: kindPrompt},
+		{kind: kindOutput},
+	}
+	shown := func(f filters) []lineKind {
+		var out []lineKind
+		for i := range lines {
+			if f.show(&lines[i]) {
+				out = append(out, lines[i].kind)
+			}
+		}
+		return out
+	}
+
+	var f filters
+	if got := shown(f); len(got) != len(lines) {
+		t.Fatalf("no filter shows %v", got)
+	}
+
+	f.toggle("chat")
+	if got := shown(f); len(got) != 3 || got[0] != kindChat {
+		t.Fatalf("chat only shows %v", got)
+	}
+	// Errors only replaces chat only rather than combining with it.
+	f.toggle("errors")
+	if f.chatOnly || !f.errorsOnly {
+		t.Fatalf("errors should replace chat: %+v", f)
+	}
+	if got := shown(f); len(got) != 3 || got[0] != kindError {
+		t.Fatalf("errors only shows %v", got)
+	}
+
+	f.toggle("off")
+	f.toggle("plugins")
+	for _, k := range shown(f) {
+		if k == kindPlugin {
+			t.Fatal("plugin line shown with plugins hidden")
+		}
+	}
+	if f.toggle("bogus") {
+		t.Fatal("unknown filter accepted")
+	}
+}
+
+func TestIndexFold(t *testing.T) {
+	tests := []struct {
+		s, sub string
+		want   int
+	}{
+		{"Steve joined the game", "steve", 0},
+		{"<Alex> hi STEVE", "Steve", 10},
+		{"nothing here", "creeper", -1},
+		{"short", "longer than s", -1},
+	}
+	for _, tt := range tests {
+		if got := indexFold(tt.s, tt.sub); got != tt.want {
+			t.Errorf("indexFold(%q, %q) = %d, want %d", tt.s, tt.sub, got, tt.want)
+		}
+	}
+}
+
+// newTestModel returns a ready model with a 5-line view holding lines.
+func newTestModel(t *testing.T, lines ...string) *model {
+	t.Helper()
+	m := &model{
+		opts:     &Options{Dir: t.TempDir()},
+		buf:      &logBuffer{parser: events.NewParser()},
+		viewport: viewport.New(80, 5),
+		ready:    true,
+	}
+	for _, l := range lines {
+		m.buf.addLog(l)
+	}
+	m.refresh()
+	return m
+}
+
+func TestScrolledUpViewStaysPut(t *testing.T) {
+	var lines []string
+	for i := range 20 {
+		lines = append(lines, "line "+strings.Repeat("x", i))
+	}
+	m := newTestModel(t, lines...)
+	if !m.viewport.AtBottom() {
+		t.Fatal("view should start following the log")
+	}
+
+	m.viewport.SetYOffset(3)
+	m.buf.addLog("a new line")
+	m.refresh()
+	if m.viewport.YOffset != 3 {
+		t.Fatalf("scrolled-up view moved to %d", m.viewport.YOffset)
+	}
+
+	m.viewport.GotoBottom()
+	m.buf.addLog("another")
+	m.refresh()
+	if !m.viewport.AtBottom() {
+		t.Fatal("view at the bottom should keep following")
+	}
+}
+
+func TestFindStepsThroughMatches(t *testing.T) {
+	var lines []string
+	for i := range 30 {
+		if i%10 == 0 {
+			lines = append(lines, "[10:00:00] [Server thread/INFO]: creeper exploded")
+		} else {
+			lines = append(lines, "[10:00:00] [Server thread/INFO]: quiet")
+		}
+	}
+	m := newTestModel(t, lines...)
+	m.find = &logFind{editing: true}
+	for _, r := range "CREEPER" {
+		m.updateFind(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if len(m.find.matches) != 3 {
+		t.Fatalf("got %d matches, want 3", len(m.find.matches))
+	}
+	if got := m.currentMatch(); got != 2 {
+		t.Fatalf("search should start at the newest match, got %d", got)
+	}
+
+	m.updateFind(tea.KeyMsg{Type: tea.KeyEnter})
+	m.stepFind(-1)
+	if got := m.currentMatch(); got != 1 {
+		t.Fatalf("previous match = %d, want 1", got)
+	}
+	m.stepFind(1)
+	m.stepFind(1)
+	if got := m.currentMatch(); got != 0 {
+		t.Fatalf("next match should wrap to 0, got %d", got)
+	}
+}
+
+func TestRunLocalSaveWritesFilteredView(t *testing.T) {
+	m := newTestModel(t,
+		"[10:00:00] [Server thread/INFO]: <Steve> hi",
+		"[10:00:01] [Server thread/INFO]: Preparing spawn area",
+		"[10:00:02] [Server thread/INFO]: <Alex> hello",
+	)
+	if _, ok := m.runLocal("filter chat"); !ok {
+		t.Fatal("filter not handled locally")
+	}
+
+	path := filepath.Join(m.opts.Dir, "chat.log")
+	out, ok := m.runLocal("save " + path)
+	if !ok || !strings.Contains(out, "Saved 2 lines") {
+		t.Fatalf("save = %q, %v", out, ok)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "spawn") || strings.Count(string(data), "\n") != 2 {
+		t.Fatalf("saved file should hold only the chat lines:\n%s", data)
+	}
+
+	if _, ok := m.runLocal("status"); ok {
+		t.Fatal("server commands should go to dispatch")
+	}
+}
@@ -0,0 +1,87 @@
+package console
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reverseSearch is the state of a Ctrl+R history search, which works like
+// the one in bash: type to find the newest matching entry, Ctrl+R again for
+// older ones, Enter to take the match, Esc to go back.
+type reverseSearch struct {
+	query string
+	match string
+	// idx is the history index of match; len(entries) before any match.
+	idx int
+	// saved is the input line from before the search, restored on cancel.
+	saved string
+	// failed is set when nothing matches the query.
+	failed bool
+}
+
+// updateSearch handles a key press while a reverse search is active.
+func (m *model) updateSearch(msg tea.KeyMsg) {
+	s := m.search
+	switch msg.Type {
+	case tea.KeyEnter, tea.KeyTab, tea.KeyRight:
+		// Take the match for editing rather than running it straight away.
+		if s.match != "" {
+			m.input.SetValue(s.match)
+		} else {
+			m.input.SetValue(s.saved)
+		}
+		m.input.CursorEnd()
+		m.search = nil
+		return
+
+	case tea.KeyEsc, tea.KeyCtrlG:
+		m.input.SetValue(s.saved)
+		m.input.CursorEnd()
+		m.search = nil
+		return
+
+	case tea.KeyCtrlR:
+		// Next older match for the same query.
+		m.findMatch(s.idx)
+		return
+
+	case tea.KeyBackspace:
+		if s.query == "" {
+			return
+		}
+		r := []rune(s.query)
+		s.query = string(r[:len(r)-1])
+
+	case tea.KeyRunes, tea.KeySpace:
+		s.query += string(msg.Runes)
+
+	default:
+		return
+	}
+	// The query changed: search again from the newest entry.
+	m.findMatch(len(m.hist.entries))
+}
+
+// findMatch looks for the query in entries older than before.
+func (m *model) findMatch(before int) {
+	s := m.search
+	if s.query == "" {
+		s.match, s.idx, s.failed = "", len(m.hist.entries), false
+		return
+	}
+	match, idx, ok := m.hist.search(s.query, before)
+	if !ok {
+		// Keep showing the last match, as bash does.
+		s.failed = true
+		return
+	}
+	s.match, s.idx, s.failed = match, idx, false
+}
+
+// view renders the input line during a reverse search.
+func (s *reverseSearch) view() string {
+	label := "(reverse-i-search)"
+	if s.failed {
+		label = "(failed reverse-i-search)"
+	}
+	return promptStyle.Render(label+"`"+s.query+"': ") + s.match
+}
@@ -7,25 +7,27 @@ import (
 	"io"
 	"net"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
 )
 
-// Verify Manager satisfies management.ServerManager at compile time.
-var _ management.ServerManager = (*Manager)(nil)
+// Verify Manager satisfies the management interfaces at compile time.
+var (
+	_ management.ServerManager     = (*Manager)(nil)
+	_ management.Querier           = (*Manager)(nil)
+	_ management.StartTimeReporter = (*Manager)(nil)
+)
 
 // Manager manages a Minecraft server running in a container (Podman or Docker).
-// It implements management.ServerManager and management.HealthChecker.
+// It implements management.ServerManager, management.HealthChecker, and
+// management.Querier.
 type Manager struct {
 	runner    platform.CommandRunner
 	runtime   string // "podman" or "docker"
 	container string
-	rconAddr  string
-	rconPass  string
-	rcon      *RCONClient
-	rconMu    sync.Mutex
+	rcon      *PersistentRCON
 }
 
 // NewManager creates a Manager for the named container using the specified runtime.
@@ -34,8 +36,7 @@ func NewManager(runner platform.CommandRunner, runtime, container, rconAddr, rco
 		runner:    runner,
 		runtime:   runtime,
 		container: container,
-		rconAddr:  rconAddr,
-		rconPass:  rconPass,
+		rcon:      NewPersistentRCON(rconAddr, rconPass),
 	}
 }
 
@@ -48,63 +49,21 @@ func (c *Manager) IsRunning(ctx context.Context) bool {
 	return strings.TrimSpace(string(out)) == "true"
 }
 
-// ensureRCON lazily initialises and returns the persistent RCON connection,
-// reconnecting if the previous connection was lost.
-// Must be called with c.rconMu held.
-func (c *Manager) ensureRCON(ctx context.Context) (*RCONClient, error) {
-	if c.rcon != nil {
-		return c.rcon, nil
-	}
-	rc := NewRCONClient(c.rconAddr, c.rconPass)
-	if err := rc.Connect(ctx); err != nil {
-		return nil, err
-	}
-	c.rcon = rc
-	return rc, nil
-}
-
 // SendCommand sends a console command to the server via RCON.
 // It reuses a persistent connection and reconnects once on failure.
 func (c *Manager) SendCommand(ctx context.Context, cmd string) error {
-	c.rconMu.Lock()
-	defer c.rconMu.Unlock()
-
-	rc, err := c.ensureRCON(ctx)
-	if err != nil {
-		return fmt.Errorf("rcon: %w", err)
-	}
-
-	_, err = rc.Command(ctx, cmd)
-	if err == nil {
-		return nil
-	}
-
-	// Reconnect once on connection errors.
-	if !isConnectionError(err) {
-		return err
-	}
-	_ = rc.Close()
-	c.rcon = nil
-
-	rc, err = c.ensureRCON(ctx)
-	if err != nil {
-		return fmt.Errorf("rcon reconnect: %w", err)
-	}
-	_, err = rc.Command(ctx, cmd)
+	_, err := c.rcon.Command(ctx, cmd)
 	return err
 }
 
+// Query sends a console command via RCON and returns the server's reply.
+func (c *Manager) Query(ctx context.Context, cmd string) (string, error) {
+	return c.rcon.Command(ctx, cmd)
+}
+
 // Close tears down the persistent RCON connection, if any.
 func (c *Manager) Close() error {
-	c.rconMu.Lock()
-	defer c.rconMu.Unlock()
-
-	if c.rcon == nil {
-		return nil
-	}
-	err := c.rcon.Close()
-	c.rcon = nil
-	return err
+	return c.rcon.Close()
 }
 
 // Launch starts the container via the configured runtime (podman or docker).
@@ -155,6 +114,26 @@ func (c *Manager) Stats(ctx context.Context) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// StartedAt returns when the container was last started.
+func (c *Manager) StartedAt(ctx context.Context) (time.Time, error) {
+	out, err := c.runner.RunWithOutput(ctx, c.runtime, "inspect", "--format", "{{.State.StartedAt}}", c.container)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseStartedAt(strings.TrimSpace(string(out)))
+}
+
+// parseStartedAt parses a container start time. Docker reports RFC 3339;
+// Podman prints Go's default time format.
+func parseStartedAt(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999 -0700 MST"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised start time: %q", s)
+}
+
 // Exists checks if a container with the given name exists (running or stopped).
 func Exists(ctx context.Context, runner platform.CommandRunner, runtime, name string) bool {
 	err := runner.Run(ctx, runtime, "inspect", "--type", "container", name)
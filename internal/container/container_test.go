@@ -353,6 +353,38 @@ func TestManager_Stats(t *testing.T) {
 	}
 }
 
+func TestManager_StartedAt(t *testing.T) {
+	want := time.Date(2026, 10, 18, 9, 30, 0, 123456789, time.UTC)
+	tests := []struct {
+		runtime string
+		output  string
+	}{
+		{runtime: "docker", output: "2026-10-18T09:30:00.123456789Z\n"},
+		{runtime: "podman", output: "2026-10-18 09:30:00.123456789 +0000 UTC\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			m := platform.NewMockRunner()
+			m.OutputMap[tc.runtime+" [inspect --format {{.State.StartedAt}} minecraft]"] = []byte(tc.output)
+
+			got, err := NewManager(m, tc.runtime, "minecraft", "", "").StartedAt(context.Background())
+			if err != nil {
+				t.Fatalf("StartedAt() error = %v", err)
+			}
+			if !got.Equal(want) {
+				t.Errorf("StartedAt() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	m := platform.NewMockRunner()
+	m.OutputMap["podman [inspect --format {{.State.StartedAt}} minecraft]"] = []byte("<no value>\n")
+	if _, err := NewManager(m, "podman", "minecraft", "", "").StartedAt(context.Background()); err == nil {
+		t.Error("StartedAt() expected error for an unparseable time")
+	}
+}
+
 func TestManager_Launch_Error(t *testing.T) {
 	m := platform.NewMockRunner()
 	key := "podman [start minecraft]"
@@ -0,0 +1,133 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/logtail"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+)
+
+// logMounts maps in-container mount points to where the server's logs/
+// directory sits beneath them: the Quadlet unit mounts a logs volume, the
+// itzg image used by the compose file keeps everything under /data.
+var logMounts = map[string]string{
+	"/minecraft/logs": "",
+	"/minecraft":      "logs",
+	"/data/logs":      "",
+	"/data":           "logs",
+}
+
+// restartPoll is how often a stopped container is checked while waiting to
+// reconnect to its log.
+const restartPoll = 2 * time.Second
+
+// LogsDir returns the host directory backing the container's logs/
+// directory, when it is mounted from a volume or bind mount that this user
+// can read.
+func (c *Manager) LogsDir(ctx context.Context) (string, bool) {
+	out, err := c.runner.RunWithOutput(ctx, c.runtime, "inspect", "--format",
+		`{{range .Mounts}}{{.Destination}}={{.Source}}{{"\n"}}{{end}}`, c.container)
+	if err != nil {
+		return "", false
+	}
+	for line := range strings.Lines(string(out)) {
+		dest, src, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || src == "" {
+			continue
+		}
+		sub, ok := logMounts[dest]
+		if !ok {
+			continue
+		}
+		dir := filepath.Join(src, sub)
+		// Docker keeps volumes under /var/lib/docker, usually root-only.
+		if _, err := os.ReadDir(dir); err == nil {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// FollowLogs streams the container's console output through the runtime's
+// `logs -f`, starting with the last tail lines, until ctx is cancelled. When
+// the container stops, the stream waits for it to come back and reconnects,
+// asking only for output since the disconnect.
+func (c *Manager) FollowLogs(ctx context.Context, tail int) (<-chan logtail.Line, error) {
+	streamer, ok := c.runner.(platform.CommandStreamer)
+	if !ok {
+		return nil, errors.New("command runner cannot stream output")
+	}
+
+	source := fmt.Sprintf("%s logs %s", c.runtime, c.container)
+	out := make(chan logtail.Line, 64)
+	go func() {
+		defer close(out)
+		args := []string{"logs", "-f", "--tail", strconv.Itoa(tail), c.container}
+		var count int64
+		for {
+			if err := c.streamLogs(ctx, streamer, args, source, &count, out); err != nil {
+				return
+			}
+			since := time.Now().UTC().Format(time.RFC3339)
+			if err := c.waitUntilRunning(ctx); err != nil {
+				return
+			}
+			args = []string{"logs", "-f", "--since", since, c.container}
+		}
+	}()
+	return out, nil
+}
+
+// streamLogs copies lines from one `logs -f` run to out. It returns nil when
+// the stream ends (the container stopped) and an error when ctx is done.
+func (c *Manager) streamLogs(ctx context.Context, streamer platform.CommandStreamer, args []string, source string, count *int64, out chan<- logtail.Line) error {
+	r, err := streamer.Stream(ctx, c.runtime, args...)
+	if err != nil {
+		// The runtime may be briefly unavailable; retry after a pause.
+		return sleep(ctx, restartPoll)
+	}
+	defer func() { _ = r.Close() }()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		// There is no file offset in a container stream; Offset counts
+		// lines instead.
+		*count++
+		select {
+		case out <- logtail.Line{Text: scanner.Text(), File: source, Offset: *count}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return ctx.Err()
+}
+
+// waitUntilRunning polls until the container is running again.
+func (c *Manager) waitUntilRunning(ctx context.Context) error {
+	for {
+		if err := sleep(ctx, restartPoll); err != nil {
+			return err
+		}
+		if c.IsRunning(ctx) {
+			return nil
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+)
+
+const mountsFormat = `{{range .Mounts}}{{.Destination}}={{.Source}}{{"\n"}}{{end}}`
+
+func TestManager_LogsDir(t *testing.T) {
+	volume := t.TempDir()
+	data := t.TempDir()
+	if err := os.Mkdir(filepath.Join(data, "logs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		mounts string
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "quadlet logs volume",
+			mounts: "/minecraft/world=/nope\n/minecraft/logs=" + volume + "\n",
+			want:   volume,
+			wantOk: true,
+		},
+		{
+			name:   "compose data volume",
+			mounts: "/data=" + data + "\n",
+			want:   filepath.Join(data, "logs"),
+			wantOk: true,
+		},
+		{
+			name:   "unreadable volume",
+			mounts: "/minecraft/logs=/var/lib/docker/volumes/does-not-exist/_data\n",
+		},
+		{
+			name:   "no log mount",
+			mounts: "/minecraft/world=" + volume + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := platform.NewMockRunner()
+			runner.OutputMap["podman [inspect --format "+mountsFormat+" minecraft]"] = []byte(tt.mounts)
+
+			mgr := NewManager(runner, "podman", "minecraft", "", "")
+			got, ok := mgr.LogsDir(t.Context())
+			if ok != tt.wantOk || got != tt.want {
+				t.Fatalf("LogsDir() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestManager_FollowLogs(t *testing.T) {
+	runner := platform.NewMockRunner()
+	runner.OutputMap["docker [logs -f --tail 100 minecraft]"] = []byte("[18:00:00 INFO]: Starting\n[18:00:05 INFO]: Done\n")
+
+	mgr := NewManager(runner, "docker", "minecraft", "", "")
+	lines, err := mgr.FollowLogs(t.Context(), 100)
+	if err != nil {
+		t.Fatalf("FollowLogs() error: %v", err)
+	}
+
+	for i, want := range []string{"[18:00:00 INFO]: Starting", "[18:00:05 INFO]: Done"} {
+		select {
+		case l := <-lines:
+			if l.Text != want || l.File != "docker logs minecraft" || l.Offset != int64(i+1) {
+				t.Fatalf("line %d = %+v, want %q", i, l, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for a log line")
+		}
+	}
+}
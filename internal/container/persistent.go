@@ -0,0 +1,87 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+)
+
+// PersistentRCON is an RCON connection that is dialed lazily on first use
+// and re-established once when a command fails on a broken connection. It is
+// safe for concurrent use.
+type PersistentRCON struct {
+	addr string
+	pass string
+	mu   sync.Mutex
+	rc   *RCONClient
+}
+
+// NewPersistentRCON returns a PersistentRCON for the given address and
+// password. No connection is made until the first Command.
+func NewPersistentRCON(addr, password string) *PersistentRCON {
+	return &PersistentRCON{addr: addr, pass: password}
+}
+
+// ensure lazily initialises and returns the connection.
+// Must be called with p.mu held.
+func (p *PersistentRCON) ensure(ctx context.Context) (*RCONClient, error) {
+	if p.rc != nil {
+		return p.rc, nil
+	}
+	rc := NewRCONClient(p.addr, p.pass)
+	if err := rc.Connect(ctx); err != nil {
+		return nil, err
+	}
+	p.rc = rc
+	return rc, nil
+}
+
+// Command sends cmd and returns the response body, reconnecting once if the
+// existing connection turns out to be broken. Without a password, or if no
+// connection can be made, cmd isn't sent and the error wraps
+// management.ErrNoQuery.
+func (p *PersistentRCON) Command(ctx context.Context, cmd string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pass == "" {
+		return "", fmt.Errorf("rcon: %w: no password", management.ErrNoQuery)
+	}
+	rc, err := p.ensure(ctx)
+	if err != nil {
+		return "", fmt.Errorf("rcon: %w: %w", management.ErrNoQuery, err)
+	}
+
+	body, err := rc.Command(ctx, cmd)
+	if err == nil {
+		return body, nil
+	}
+
+	// Reconnect once on connection errors.
+	if !isConnectionError(err) {
+		return "", err
+	}
+	_ = rc.Close()
+	p.rc = nil
+
+	rc, err = p.ensure(ctx)
+	if err != nil {
+		return "", fmt.Errorf("rcon reconnect: %w", err)
+	}
+	return rc.Command(ctx, cmd)
+}
+
+// Close tears down the connection, if any. It is safe to call repeatedly.
+func (p *PersistentRCON) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.rc == nil {
+		return nil
+	}
+	err := p.rc.Close()
+	p.rc = nil
+	return err
+}
@@ -3,11 +3,14 @@ package container
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"io"
 	"net"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
 )
 
 // rconTestServer is a minimal TCP server that speaks the Source RCON protocol
@@ -363,3 +366,19 @@ func TestRCONClient_DialCancelledContext(t *testing.T) {
 		t.Fatal("Connect() expected error with cancelled context, got nil")
 	}
 }
+
+func TestPersistentRCON_NoConnection(t *testing.T) {
+	lc := &net.ListenConfig{}
+	l, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	for _, p := range []*PersistentRCON{NewPersistentRCON(addr, ""), NewPersistentRCON(addr, "pass")} {
+		if _, err := p.Command(t.Context(), "list"); !errors.Is(err, management.ErrNoQuery) {
+			t.Errorf("Command() error = %v, want ErrNoQuery", err)
+		}
+	}
+}
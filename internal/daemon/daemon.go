@@ -0,0 +1,62 @@
+// Package daemon runs mc-dad-server's long-lived background services side by
+// side in one process, stopping them together on shutdown.
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// Service is one long-running daemon component. Run should block until ctx
+// is cancelled and return nil on a clean shutdown.
+type Service struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Run starts every service and blocks until ctx is cancelled or any service
+// returns. A service that exits early stops the rest, so the daemon never
+// keeps running in a half-working state; its error is returned.
+func Run(ctx context.Context, output *ui.UI, services ...Service) error {
+	if len(services) == 0 {
+		return fmt.Errorf("no daemon services enabled")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for _, svc := range services {
+		wg.Go(func() {
+			err := svc.Run(ctx)
+			if ctx.Err() == nil {
+				// Exited on its own rather than by shutdown.
+				if err == nil {
+					err = errors.New("exited unexpectedly")
+				}
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("%s: %w", svc.Name, err)
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+			if err != nil {
+				output.Warn("%s: %s", svc.Name, err)
+			}
+		})
+		output.Info("Started %s", svc.Name)
+	}
+
+	wg.Wait()
+	return firstErr
+}
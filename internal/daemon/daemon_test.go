@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+func blockUntilDone(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(ctx, ui.NewWriter(&bytes.Buffer{}, false),
+			Service{Name: "a", Run: blockUntilDone},
+			Service{Name: "b", Run: blockUntilDone},
+		)
+	}()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run() did not return after cancel")
+	}
+}
+
+func TestRunFailingServiceStopsOthers(t *testing.T) {
+	err := Run(context.Background(), ui.NewWriter(&bytes.Buffer{}, false),
+		Service{Name: "steady", Run: blockUntilDone},
+		Service{Name: "broken", Run: func(context.Context) error { return errors.New("boom") }},
+	)
+	if err == nil || !strings.Contains(err.Error(), "broken: boom") {
+		t.Fatalf("Run() error = %v, want broken: boom", err)
+	}
+}
+
+func TestRunRequiresServices(t *testing.T) {
+	if err := Run(context.Background(), ui.NewWriter(&bytes.Buffer{}, false)); err == nil {
+		t.Fatal("expected an error with no services")
+	}
+}
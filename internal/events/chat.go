@@ -0,0 +1,154 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// chatFormatsFile lists extra chat formats for servers whose chat plugin
+// writes lines the built-in formats don't recognise.
+const chatFormatsFile = "chat-formats.json"
+
+// playerName matches a Java username or a Floodgate Bedrock name, which
+// carries a prefix (default ".") so it cannot collide with a Java player.
+const playerName = `[.*]?\w{1,32}`
+
+// ChatFormat recognises one way a chat message can appear in the log. The
+// pattern is matched against the log message after the timestamp and
+// thread prefix and must capture the named groups "player" and "message".
+type ChatFormat struct {
+	Name string
+	// Thread, when set, limits the format to lines logged by threads whose
+	// name starts with it. Broad formats such as "Rank Name: text" need
+	// this so plugin status lines on the server thread are not taken for
+	// chat.
+	Thread  string
+	Pattern *regexp.Regexp
+}
+
+// BuiltinChatFormats returns the chat formats recognised out of the box.
+func BuiltinChatFormats() []ChatFormat {
+	return []ChatFormat{
+		{
+			// "<Steve> hi" — vanilla and Fabric on the server thread,
+			// Paper on "Async Chat Thread - #0". Unsigned messages carry
+			// a "[Not Secure]" marker and chat plugins may add ranks.
+			Name:    "vanilla",
+			Pattern: regexp.MustCompile(`^(?:\[[^\]]*\] ?)*<(?P<player>` + playerName + `)> (?P<message>.*)$`),
+		},
+		{
+			// "[Member] Steve: hi", "Steve » hi" — chat plugins
+			// (ChatSentry, LuckPerms chat formats) add ranks and their own
+			// separators. Paper logs these from the async chat thread.
+			Name:    "ranked",
+			Thread:  "Async Chat Thread",
+			Pattern: regexp.MustCompile(`^(?:\[[^\]]*\] ?)*(?P<player>` + playerName + `)(?::| ?[»>]) (?P<message>.*)$`),
+		},
+	}
+}
+
+// Parser turns log lines into events using a set of chat formats.
+type Parser struct {
+	chat []ChatFormat
+}
+
+// NewParser returns a Parser trying the extra chat formats before the
+// built-in ones.
+func NewParser(extra ...ChatFormat) *Parser {
+	return &Parser{chat: slices.Concat(extra, BuiltinChatFormats())}
+}
+
+// LoadParser returns a Parser with the extra chat formats configured in
+// serverDir's chat-formats.json, if any.
+func LoadParser(serverDir string) (*Parser, error) {
+	formats, err := LoadChatFormats(serverDir)
+	if err != nil {
+		return NewParser(), err
+	}
+	return NewParser(formats...), nil
+}
+
+// chatFormatFile is the on-disk form of a ChatFormat.
+type chatFormatFile struct {
+	Name    string `json:"name"`
+	Thread  string `json:"thread,omitempty"`
+	Pattern string `json:"pattern"`
+}
+
+// LoadChatFormats reads the extra chat formats configured in serverDir. A
+// missing file means none.
+func LoadChatFormats(serverDir string) ([]ChatFormat, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, chatFormatsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", chatFormatsFile, err)
+	}
+
+	var raw []chatFormatFile
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", chatFormatsFile, err)
+	}
+	formats := make([]ChatFormat, 0, len(raw))
+	for _, r := range raw {
+		f, err := NewChatFormat(r.Name, r.Thread, r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", chatFormatsFile, err)
+		}
+		formats = append(formats, f)
+	}
+	return formats, nil
+}
+
+// NewChatFormat compiles a chat format, checking that pattern captures the
+// "player" and "message" groups.
+func NewChatFormat(name, thread, pattern string) (ChatFormat, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return ChatFormat{}, fmt.Errorf("chat format %q: %w", name, err)
+	}
+	for _, group := range []string{"player", "message"} {
+		if re.SubexpIndex(group) < 0 {
+			return ChatFormat{}, fmt.Errorf("chat format %q: pattern must capture (?P<%s>...)", name, group)
+		}
+	}
+	return ChatFormat{Name: name, Thread: thread, Pattern: re}, nil
+}
+
+// chatFormatting matches Minecraft colour and style codes, which some chat
+// plugins leave in the log.
+var chatFormatting = regexp.MustCompile(`(?i)§[0-9a-fk-orx]`)
+
+// parseChat reports the player and message if msg, logged by thread, is a
+// chat line in one of p's formats.
+func (p *Parser) parseChat(thread, msg string) (player, message string, ok bool) {
+	msg = chatFormatting.ReplaceAllString(msg, "")
+	for _, f := range p.chat {
+		if f.Thread != "" && !strings.HasPrefix(thread, f.Thread) {
+			continue
+		}
+		m := f.Pattern.FindStringSubmatch(msg)
+		if m == nil {
+			continue
+		}
+		return m[f.Pattern.SubexpIndex("player")], m[f.Pattern.SubexpIndex("message")], true
+	}
+	return "", "", false
+}
+
+// defaultParser recognises the built-in chat formats only.
+var defaultParser = NewParser()
+
+// Parse parses one log line with the built-in chat formats. See
+// Parser.Parse.
+func Parse(line string, day time.Time) (Event, bool) {
+	return defaultParser.Parse(line, day)
+}
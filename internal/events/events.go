@@ -0,0 +1,224 @@
+// Package events turns Minecraft server log lines into typed events —
+// joins, leaves, deaths, advancements, chat, startup, lag warnings and
+// logins the whitelist refused — and streams them from a live latest.log so
+// Go features can react to what happens in game.
+package events
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind identifies the type of an Event.
+type Kind string
+
+// Event kinds recognised in the server log.
+const (
+	Join           Kind = "join"
+	Leave          Kind = "leave"
+	Death          Kind = "death"
+	Advancement    Kind = "advancement"
+	Chat           Kind = "chat"
+	ServerReady    Kind = "server_ready"
+	ServerStopping Kind = "server_stopping"
+	Lag            Kind = "lag"
+	NotWhitelisted Kind = "not_whitelisted"
+)
+
+// Event is one parsed log line.
+type Event struct {
+	Kind Kind
+	// Time is when the line was logged. Log lines carry only the time of
+	// day; the date comes from the caller.
+	Time   time.Time
+	Thread string
+	Level  string
+	// Player is set for join, leave, death, advancement, chat and
+	// not-whitelisted events.
+	Player string
+	// UUID is the refused player's, for NotWhitelisted events from servers
+	// that log it.
+	UUID string
+	// Message is the chat text, the full death message, or the
+	// advancement title. For other kinds it is the log message itself.
+	Message string
+	// Elapsed is the startup time for ServerReady and how far the server
+	// has fallen behind for Lag.
+	Elapsed time.Duration
+	// Raw is the unmodified log line.
+	Raw string
+	// Offset is the byte offset just past this line in the log file it was
+	// read from. Passing it to Stream.Subscribe resumes after this event.
+	Offset int64
+}
+
+// Line prefixes. Vanilla and Paper write "[12:34:56] [Server thread/INFO]: ",
+// Fabric adds the logger name as "[12:34:56] [Server thread/INFO] (Minecraft) ",
+// and Paper's console (what container runtimes capture) writes
+// "[12:34:56 INFO]: ".
+var (
+	filePrefix    = regexp.MustCompile(`^\[(\d{2}:\d{2}:\d{2})\] \[(.+?)/([A-Z]+)\](?:: | \([^)]*\) )`)
+	consolePrefix = regexp.MustCompile(`^\[(\d{2}:\d{2}:\d{2}) ([A-Z]+)\]: `)
+)
+
+// Message patterns, matched against the text after the prefix.
+var (
+	joinPattern        = regexp.MustCompile(`^(\S+)(?: \(formerly known as \S+\))? joined the game$`)
+	leavePattern       = regexp.MustCompile(`^(\S+) left the game$`)
+	advancementPattern = regexp.MustCompile(`^(\S+) has (?:made the advancement|completed the challenge|reached the goal) \[(.+)\]$`)
+	readyPattern       = regexp.MustCompile(`^Done \(([\d.]+)s\)! For help, type "help"`)
+	stoppingPattern    = regexp.MustCompile(`^(?:Stopping the server|Stopping server)$`)
+	lagPattern         = regexp.MustCompile(`^Can't keep up! Is the server overloaded\? Running (\d+)ms or \d+ ticks behind`)
+	deathPattern       = regexp.MustCompile(`^(\S+) (` + strings.Join(deathVerbs, "|") + `)\b`)
+	// refusedPattern matches the disconnect of a player not on the
+	// whitelist. Depending on the version the player is a name, a name
+	// and UUID, or a GameProfile dump, and the line is "Disconnecting
+	// <player>: <reason>", "<player> lost connection: <reason>" or both.
+	refusedPattern = regexp.MustCompile(`^(?:Disconnecting )?(.+?)(?: \(([^)]*)\))?(?: lost connection)?: You are not white-?listed on this server`)
+	profileName    = regexp.MustCompile(`\bname=([^,\]\s]+)`)
+	profileID      = regexp.MustCompile(`\bid=([0-9a-fA-F-]{32,36})\b`)
+	uuidPattern    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// deathVerbs are the openings of vanilla death messages after the victim's
+// name. Kept in step with the Bun sidecar's log-parser.ts.
+var deathVerbs = []string{
+	"was shot", "was pummeled", "was pricked", "walked into a cactus",
+	"drowned", "experienced kinetic energy", "blew up", "was blown up",
+	"was killed by", "hit the ground", "fell", "was squashed", "was squished",
+	"was stung", "was obliterated", "suffocated", "starved", "was frozen",
+	"was burnt", "was roasted", "went up in flames", "burned",
+	"tried to swim in lava", "was struck by lightning", "discovered",
+	"was fireballed", "was impaled", "didn't want to live",
+	"withered away", "died", "was slain", "was killed", "was poked",
+	"was skewered", "froze to death", "went off with a bang",
+}
+
+// Parse parses one log line. day supplies the date (and location) for the
+// line's time of day. It reports false for lines that are not a recognised
+// event.
+func (p *Parser) Parse(line string, day time.Time) (Event, bool) {
+	clock, thread, level, msg, ok := splitPrefix(line)
+	if !ok {
+		return Event{}, false
+	}
+	ev := Event{Raw: line, Thread: thread, Level: level}
+	ev.Time = atClock(day, clock)
+	ev.Message = msg
+
+	// Only informational lines are game events; a WARN that happens to
+	// contain "joined the game" is not.
+	switch ev.Level {
+	case "INFO":
+		return p.parseInfo(ev, msg)
+	case "WARN":
+		if m := lagPattern.FindStringSubmatch(msg); m != nil {
+			ms, _ := strconv.Atoi(m[1])
+			ev.Kind = Lag
+			ev.Elapsed = time.Duration(ms) * time.Millisecond
+			return ev, true
+		}
+	}
+	return Event{}, false
+}
+
+// SplitPrefix splits a log line into the thread and level from its prefix
+// and the message after it. Console-format lines have no thread. It reports
+// false for lines without a prefix, such as stack trace continuations.
+func SplitPrefix(line string) (thread, level, msg string, ok bool) {
+	_, thread, level, msg, ok = splitPrefix(line)
+	return thread, level, msg, ok
+}
+
+// Clock returns the time of day from a log line's prefix, as "15:04:05".
+// It reports false for lines without a prefix.
+func Clock(line string) (string, bool) {
+	clock, _, _, _, ok := splitPrefix(line)
+	return clock, ok
+}
+
+func splitPrefix(line string) (clock, thread, level, msg string, ok bool) {
+	if m := filePrefix.FindStringSubmatch(line); m != nil {
+		return m[1], m[2], m[3], line[len(m[0]):], true
+	}
+	if m := consolePrefix.FindStringSubmatch(line); m != nil {
+		return m[1], "", m[2], line[len(m[0]):], true
+	}
+	return "", "", "", "", false
+}
+
+func (p *Parser) parseInfo(ev Event, msg string) (Event, bool) {
+	// Chat comes first: a player can type anything, including text that
+	// looks like a join or death message.
+	if player, text, ok := p.parseChat(ev.Thread, msg); ok {
+		ev.Kind, ev.Player, ev.Message = Chat, player, text
+		return ev, true
+	}
+	if m := joinPattern.FindStringSubmatch(msg); m != nil {
+		ev.Kind, ev.Player = Join, m[1]
+		return ev, true
+	}
+	if m := leavePattern.FindStringSubmatch(msg); m != nil {
+		ev.Kind, ev.Player = Leave, m[1]
+		return ev, true
+	}
+	if m := advancementPattern.FindStringSubmatch(msg); m != nil {
+		ev.Kind, ev.Player, ev.Message = Advancement, m[1], m[2]
+		return ev, true
+	}
+	if m := readyPattern.FindStringSubmatch(msg); m != nil {
+		secs, _ := strconv.ParseFloat(m[1], 64)
+		ev.Kind = ServerReady
+		ev.Elapsed = time.Duration(secs * float64(time.Second))
+		return ev, true
+	}
+	if stoppingPattern.MatchString(msg) {
+		ev.Kind = ServerStopping
+		return ev, true
+	}
+	if m := deathPattern.FindStringSubmatch(msg); m != nil {
+		ev.Kind, ev.Player = Death, m[1]
+		return ev, true
+	}
+	if m := refusedPattern.FindStringSubmatch(msg); m != nil {
+		if player, uuid, ok := refusedPlayer(m[1], m[2]); ok {
+			ev.Kind, ev.Player, ev.UUID = NotWhitelisted, player, uuid
+			return ev, true
+		}
+	}
+	return Event{}, false
+}
+
+// refusedPlayer picks the player's name and UUID out of a refused login's
+// disconnect line: who is either a name or a GameProfile, and paren the
+// address or UUID after it. Connections refused before the player was
+// known are logged by address and report false.
+func refusedPlayer(who, paren string) (name, uuid string, ok bool) {
+	if m := profileName.FindStringSubmatch(who); m != nil {
+		name = m[1]
+		if id := profileID.FindStringSubmatch(who); id != nil {
+			uuid = id[1]
+		}
+	} else {
+		name = who
+	}
+	if uuidPattern.MatchString(paren) {
+		uuid = paren
+	}
+	if name == "" || strings.ContainsAny(name, " /[") {
+		return "", "", false
+	}
+	return name, strings.ToLower(uuid), true
+}
+
+// atClock returns the instant on day's date at clock ("15:04:05").
+func atClock(day time.Time, clock string) time.Time {
+	t, err := time.Parse(time.TimeOnly, clock)
+	if err != nil {
+		return day
+	}
+	y, mo, d := day.Date()
+	return time.Date(y, mo, d, t.Hour(), t.Minute(), t.Second(), 0, day.Location())
+}
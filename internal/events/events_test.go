@@ -0,0 +1,364 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	day := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		line       string
+		wantKind   Kind
+		wantPlayer string
+		wantMsg    string
+		wantOk     bool
+	}{
+		{
+			name:       "vanilla join",
+			line:       "[18:02:11] [Server thread/INFO]: Steve joined the game",
+			wantKind:   Join,
+			wantPlayer: "Steve",
+			wantMsg:    "Steve joined the game",
+			wantOk:     true,
+		},
+		{
+			name:       "renamed player join",
+			line:       "[18:02:11] [Server thread/INFO]: Steve (formerly known as Bob) joined the game",
+			wantKind:   Join,
+			wantPlayer: "Steve",
+			wantMsg:    "Steve (formerly known as Bob) joined the game",
+			wantOk:     true,
+		},
+		{
+			name:       "floodgate join",
+			line:       "[18:02:11] [Server thread/INFO]: .Steve_Kid joined the game",
+			wantKind:   Join,
+			wantPlayer: ".Steve_Kid",
+			wantMsg:    ".Steve_Kid joined the game",
+			wantOk:     true,
+		},
+		{
+			name:       "fabric leave",
+			line:       "[18:40:00] [Server thread/INFO] (Minecraft) Alex left the game",
+			wantKind:   Leave,
+			wantPlayer: "Alex",
+			wantMsg:    "Alex left the game",
+			wantOk:     true,
+		},
+		{
+			name:       "paper console leave",
+			line:       "[18:40:00 INFO]: Alex left the game",
+			wantKind:   Leave,
+			wantPlayer: "Alex",
+			wantMsg:    "Alex left the game",
+			wantOk:     true,
+		},
+		{
+			name:       "death",
+			line:       "[18:10:42] [Server thread/INFO]: Steve was slain by Zombie",
+			wantKind:   Death,
+			wantPlayer: "Steve",
+			wantMsg:    "Steve was slain by Zombie",
+			wantOk:     true,
+		},
+		{
+			name:       "fall death",
+			line:       "[18:10:42] [Server thread/INFO]: Alex fell from a high place",
+			wantKind:   Death,
+			wantPlayer: "Alex",
+			wantMsg:    "Alex fell from a high place",
+			wantOk:     true,
+		},
+		{
+			name:       "advancement",
+			line:       "[18:12:00] [Server thread/INFO]: Steve has made the advancement [Stone Age]",
+			wantKind:   Advancement,
+			wantPlayer: "Steve",
+			wantMsg:    "Stone Age",
+			wantOk:     true,
+		},
+		{
+			name:       "challenge",
+			line:       "[18:12:00] [Server thread/INFO]: Alex has completed the challenge [The End?]",
+			wantKind:   Advancement,
+			wantPlayer: "Alex",
+			wantMsg:    "The End?",
+			wantOk:     true,
+		},
+		{
+			name:     "server ready",
+			line:     `[17:59:58] [Server thread/INFO]: Done (6.021s)! For help, type "help"`,
+			wantKind: ServerReady,
+			wantMsg:  `Done (6.021s)! For help, type "help"`,
+			wantOk:   true,
+		},
+		{
+			name:     "server stopping",
+			line:     "[22:00:00] [Server thread/INFO]: Stopping the server",
+			wantKind: ServerStopping,
+			wantMsg:  "Stopping the server",
+			wantOk:   true,
+		},
+		{
+			name:     "lag warning",
+			line:     "[19:30:01] [Server thread/WARN]: Can't keep up! Is the server overloaded? Running 2534ms or 50 ticks behind",
+			wantKind: Lag,
+			wantMsg:  "Can't keep up! Is the server overloaded? Running 2534ms or 50 ticks behind",
+			wantOk:   true,
+		},
+		{
+			name:       "chat",
+			line:       "[18:05:00] [Server thread/INFO]: <Steve> hello world",
+			wantKind:   Chat,
+			wantPlayer: "Steve",
+			wantMsg:    "hello world",
+			wantOk:     true,
+		},
+		{
+			name:       "chat that looks like a join",
+			line:       "[18:05:00] [Server thread/INFO]: <Alex> Steve joined the game",
+			wantKind:   Chat,
+			wantPlayer: "Alex",
+			wantMsg:    "Steve joined the game",
+			wantOk:     true,
+		},
+		{
+			name:       "unsigned chat",
+			line:       "[18:05:00] [Server thread/INFO]: [Not Secure] <Alex> hi",
+			wantKind:   Chat,
+			wantPlayer: "Alex",
+			wantMsg:    "hi",
+			wantOk:     true,
+		},
+		{
+			name:   "warning is not a join",
+			line:   "[18:05:00] [Server thread/WARN]: Steve joined the game",
+			wantOk: false,
+		},
+		{
+			name:   "plugin noise",
+			line:   "[18:00:01] [Server thread/INFO]: [Geyser-Spigot] Started Geyser on 0.0.0.0:19132",
+			wantOk: false,
+		},
+		{
+			name:   "stack trace",
+			line:   "\tat net.minecraft.server.MinecraftServer.run(MinecraftServer.java:123)",
+			wantOk: false,
+		},
+		{
+			name:   "empty line",
+			line:   "",
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev, ok := Parse(tt.line, day)
+			if ok != tt.wantOk {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if ev.Kind != tt.wantKind {
+				t.Errorf("Kind = %q, want %q", ev.Kind, tt.wantKind)
+			}
+			if ev.Player != tt.wantPlayer {
+				t.Errorf("Player = %q, want %q", ev.Player, tt.wantPlayer)
+			}
+			if ev.Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", ev.Message, tt.wantMsg)
+			}
+			if ev.Raw != tt.line {
+				t.Errorf("Raw = %q, want the input line", ev.Raw)
+			}
+		})
+	}
+}
+
+func TestParseNotWhitelisted(t *testing.T) {
+	day := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	const uuid = "853c80ef-3c37-49fd-aa49-938b674adae6"
+
+	tests := []struct {
+		name       string
+		line       string
+		wantPlayer string
+		wantUUID   string
+		wantOk     bool
+	}{
+		{"name and address", "[18:02:11] [Server thread/INFO]: Disconnecting Steve (/192.168.1.20:51234): You are not white-listed on this server!", "Steve", "", true},
+		{"game profile", "[18:02:11] [Server thread/INFO]: com.mojang.authlib.GameProfile@1b2c3d4e[id=" + uuid + ",name=Steve,properties={textures=[]},legacy=false] (/192.168.1.20:51234) lost connection: You are not white-listed on this server!", "Steve", uuid, true},
+		{"name and uuid", "[18:02:11] [Server thread/INFO]: Disconnecting Steve (" + uuid + "): You are not white-listed on this server!", "Steve", uuid, true},
+		{"lost connection", "[18:02:11 INFO]: Steve (" + uuid + ") lost connection: You are not whitelisted on this server!", "Steve", uuid, true},
+		{"floodgate", "[18:02:11] [Server thread/INFO]: Disconnecting .Kid_Tablet (/192.168.1.21:50000): You are not white-listed on this server!", ".Kid_Tablet", "", true},
+		{"before login", "[18:02:11] [Server thread/INFO]: /192.168.1.20:51234 lost connection: You are not white-listed on this server!", "", "", false},
+		{"in chat", "[18:02:11] [Server thread/INFO]: <Alex> Disconnecting Steve (/1.2.3.4:5): You are not white-listed on this server!", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev, ok := Parse(tt.line, day)
+			if ok && ev.Kind != NotWhitelisted {
+				ok = false
+			}
+			if ok != tt.wantOk {
+				t.Fatalf("parsed as not whitelisted = %v, want %v (%+v)", ok, tt.wantOk, ev)
+			}
+			if ok && (ev.Player != tt.wantPlayer || ev.UUID != tt.wantUUID) {
+				t.Errorf("Player, UUID = %q, %q; want %q, %q", ev.Player, ev.UUID, tt.wantPlayer, tt.wantUUID)
+			}
+		})
+	}
+}
+
+func TestParseTimingFields(t *testing.T) {
+	day := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	ev, ok := Parse(`[17:59:58] [Server thread/INFO]: Done (6.5s)! For help, type "help"`, day)
+	if !ok {
+		t.Fatal("server ready line not parsed")
+	}
+	if want := time.Date(2026, 10, 17, 17, 59, 58, 0, time.UTC); !ev.Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", ev.Time, want)
+	}
+	if ev.Elapsed != 6500*time.Millisecond {
+		t.Errorf("startup Elapsed = %v, want 6.5s", ev.Elapsed)
+	}
+
+	ev, ok = Parse("[19:30:01] [Server thread/WARN]: Can't keep up! Is the server overloaded? Running 2534ms or 50 ticks behind", day)
+	if !ok {
+		t.Fatal("lag line not parsed")
+	}
+	if ev.Elapsed != 2534*time.Millisecond {
+		t.Errorf("lag Elapsed = %v, want 2.534s", ev.Elapsed)
+	}
+}
+
+func TestSplitPrefix(t *testing.T) {
+	tests := []struct {
+		line               string
+		thread, level, msg string
+		ok                 bool
+	}{
+		{"[10:00:00] [Server thread/ERROR]: Encountered an unexpected exception", "Server thread", "ERROR", "Encountered an unexpected exception", true},
+		{"[10:00:00] [Worker-Main-1/WARN] (Minecraft) Slow chunk", "Worker-Main-1", "WARN", "Slow chunk", true},
+		{"[10:00:00 INFO]: [LuckPerms] Loading configuration...", "", "INFO", "[LuckPerms] Loading configuration...", true},
+		{"\tat net.minecraft.server.MinecraftServer.run(MinecraftServer.java:100)", "", "", "", false},
+	}
+	for _, tt := range tests {
+		thread, level, msg, ok := SplitPrefix(tt.line)
+		if thread != tt.thread || level != tt.level || msg != tt.msg || ok != tt.ok {
+			t.Errorf("SplitPrefix(%q) = %q, %q, %q, %v; want %q, %q, %q, %v",
+				tt.line, thread, level, msg, ok, tt.thread, tt.level, tt.msg, tt.ok)
+		}
+	}
+}
+
+func TestClock(t *testing.T) {
+	tests := []struct {
+		line, clock string
+		ok          bool
+	}{
+		{"[23:59:58] [Server thread/INFO]: Steve left the game", "23:59:58", true},
+		{"[00:00:01 INFO]: Done (3.2s)! For help, type \"help\"", "00:00:01", true},
+		{"\tat net.minecraft.server.MinecraftServer.run(MinecraftServer.java:100)", "", false},
+	}
+	for _, tt := range tests {
+		if clock, ok := Clock(tt.line); clock != tt.clock || ok != tt.ok {
+			t.Errorf("Clock(%q) = %q, %v; want %q, %v", tt.line, clock, ok, tt.clock, tt.ok)
+		}
+	}
+}
+
+func TestParseChatCorpus(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "chat.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for n, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		wantPlayer, rest, _ := strings.Cut(line, "\t")
+		wantMsg, logLine, ok := strings.Cut(rest, "\t")
+		if !ok {
+			t.Fatalf("testdata/chat.txt:%d: want player<TAB>message<TAB>line", n+1)
+		}
+
+		t.Run(logLine, func(t *testing.T) {
+			ev, ok := Parse(logLine, time.Now())
+			isChat := ok && ev.Kind == Chat
+			if wantPlayer == "-" {
+				if isChat {
+					t.Fatalf("parsed as chat from %q: %q", ev.Player, ev.Message)
+				}
+				return
+			}
+			if !isChat {
+				t.Fatal("not parsed as chat")
+			}
+			if ev.Player != wantPlayer || ev.Message != wantMsg {
+				t.Errorf("got %q: %q, want %q: %q", ev.Player, ev.Message, wantPlayer, wantMsg)
+			}
+		})
+	}
+}
+
+func TestParserExtraChatFormats(t *testing.T) {
+	dir := t.TempDir()
+	cfg := `[{"name": "discordsrv", "pattern": "^\\[Discord\\] (?P<player>\\w+) said: (?P<message>.*)$"}]`
+	if err := os.WriteFile(filepath.Join(dir, chatFormatsFile), []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadParser(dir)
+	if err != nil {
+		t.Fatalf("LoadParser() error: %v", err)
+	}
+	ev, ok := p.Parse("[18:00:00] [Server thread/INFO]: [Discord] Dad said: dinner", time.Now())
+	if !ok || ev.Kind != Chat || ev.Player != "Dad" || ev.Message != "dinner" {
+		t.Fatalf("got %+v, %v; want chat from Dad", ev, ok)
+	}
+	// Built-in formats still apply.
+	if ev, ok := p.Parse("[18:00:00] [Server thread/INFO]: <Steve> 1", time.Now()); !ok || ev.Player != "Steve" {
+		t.Fatalf("built-in vanilla format lost: %+v, %v", ev, ok)
+	}
+}
+
+func TestLoadChatFormatsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "bad json", content: `{`, wantErr: "parsing chat-formats.json"},
+		{name: "bad regexp", content: `[{"name": "x", "pattern": "("}]`, wantErr: `chat format "x"`},
+		{name: "missing group", content: `[{"name": "x", "pattern": "^(?P<player>\\w+): .*$"}]`, wantErr: "(?P<message>...)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, chatFormatsFile), []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadChatFormats(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// No file means no extra formats.
+	if formats, err := LoadChatFormats(t.TempDir()); err != nil || formats != nil {
+		t.Fatalf("LoadChatFormats(empty dir) = %v, %v; want nil, nil", formats, err)
+	}
+}
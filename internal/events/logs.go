@@ -0,0 +1,143 @@
+package events
+
+import (
+	"bufio"
+	"cmp"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rotatedLog matches the logs Minecraft compresses at startup and midnight,
+// named for the date of their last line: "2026-10-17-1.log.gz".
+var rotatedLog = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(\d+)\.log\.gz$`)
+
+// RotatedLog is a compressed log in the server's logs directory.
+type RotatedLog struct {
+	Name string
+	// Date is the date of the log's last line.
+	Date time.Time
+	// Index orders the logs of one date.
+	Index int
+}
+
+// RotatedLogs lists serverDir's rotated logs, oldest first.
+func RotatedLogs(serverDir string) ([]RotatedLog, error) {
+	entries, err := os.ReadDir(filepath.Join(serverDir, "logs"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing logs: %w", err)
+	}
+	var logs []RotatedLog
+	for _, e := range entries {
+		m := rotatedLog.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		date, err := time.ParseInLocation(time.DateOnly, m[1], time.Local)
+		if err != nil {
+			continue
+		}
+		index, _ := strconv.Atoi(m[2])
+		logs = append(logs, RotatedLog{Name: e.Name(), Date: date, Index: index})
+	}
+	slices.SortFunc(logs, func(a, b RotatedLog) int {
+		return cmp.Or(a.Date.Compare(b.Date), cmp.Compare(a.Index, b.Index))
+	})
+	return logs, nil
+}
+
+// Line is a log line and when it was logged.
+type Line struct {
+	Text string
+	// Time is zero for lines without a timestamp, such as stack traces.
+	Time time.Time
+}
+
+// ReadLog reads a log, decompressing .gz files, whose last line was logged
+// on end's date. Lines carry only the time of day, so a clock that goes
+// backwards means midnight has passed; counting those back from end dates
+// every line.
+func ReadLog(path string, end time.Time) ([]Line, error) {
+	texts, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]Line, len(texts))
+	clocks := make([]time.Duration, len(texts))
+	midnights := 0
+	prev := time.Duration(-1)
+	for i, text := range texts {
+		lines[i].Text = text
+		clocks[i] = -1
+		clock, ok := Clock(text)
+		if !ok {
+			continue
+		}
+		c, err := time.Parse(time.TimeOnly, clock)
+		if err != nil {
+			continue
+		}
+		d := time.Duration(c.Hour())*time.Hour + time.Duration(c.Minute())*time.Minute + time.Duration(c.Second())*time.Second
+		if prev >= 0 && d < prev {
+			midnights++
+		}
+		clocks[i], prev = d, d
+	}
+
+	y, m, d := end.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.Local).AddDate(0, 0, -midnights)
+	prev = -1
+	for i, c := range clocks {
+		if c < 0 {
+			continue
+		}
+		if prev >= 0 && c < prev {
+			day = day.AddDate(0, 0, 1)
+		}
+		prev = c
+		lines[i].Time = day.Add(c)
+	}
+	return lines, nil
+}
+
+// readLines returns the lines of a log, decompressing .gz files.
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", filepath.Base(path), err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing %s: %w", filepath.Base(path), err)
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	return lines, nil
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotatedLogs(t *testing.T) {
+	dir := t.TempDir()
+	logs := filepath.Join(dir, "logs")
+	if err := os.MkdirAll(logs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"2026-10-17-2.log.gz", "latest.log", "2026-10-17-10.log.gz", "2026-10-16-1.log.gz", "debug.log.gz"} {
+		if err := os.WriteFile(filepath.Join(logs, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := RotatedLogs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"2026-10-16-1.log.gz", "2026-10-17-2.log.gz", "2026-10-17-10.log.gz"}
+	if len(got) != len(want) {
+		t.Fatalf("RotatedLogs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Name != want[i] {
+			t.Errorf("RotatedLogs()[%d] = %s, want %s", i, got[i].Name, want[i])
+		}
+	}
+
+	if got, err := RotatedLogs(t.TempDir()); err != nil || len(got) != 0 {
+		t.Errorf("RotatedLogs() with no logs directory = %v, %v; want nothing", got, err)
+	}
+}
+
+func TestReadLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "2026-10-17-1.log.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	_, _ = gz.Write([]byte(`[23:59:00] [Server thread/INFO]: <Steve> nearly midnight
+	at some.stack.Trace
+[00:01:00] [Server thread/INFO]: <Steve> happy new day
+`))
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file is dated by its last line, so the first is the day before.
+	lines, err := ReadLog(path, time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{
+		time.Date(2026, 10, 16, 23, 59, 0, 0, time.Local),
+		{},
+		time.Date(2026, 10, 17, 0, 1, 0, 0, time.Local),
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("ReadLog() = %d lines, want %d", len(lines), len(want))
+	}
+	for i := range want {
+		if !lines[i].Time.Equal(want[i]) {
+			t.Errorf("line %d %q at %v, want %v", i, lines[i].Text, lines[i].Time, want[i])
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package events
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/logtail"
+)
+
+// FromEnd starts a subscription at the current end of the log, delivering
+// only events logged after it.
+const FromEnd = logtail.FromEnd
+
+// Stream follows a server's logs/latest.log. Each subscription tails the
+// file independently, so subscribers never slow each other down.
+type Stream struct {
+	// Path is the log file to follow.
+	Path string
+	// PollInterval is how often the file is checked for new lines; zero
+	// uses logtail.DefaultPollInterval.
+	PollInterval time.Duration
+	// Parser parses the lines; nil uses the built-in chat formats.
+	Parser *Parser
+}
+
+// NewStream returns a Stream for the server in serverDir.
+func NewStream(serverDir string) *Stream {
+	return &Stream{Path: filepath.Join(serverDir, "logs", "latest.log")}
+}
+
+// Subscribe delivers events logged from byte offset from onward (FromEnd
+// for new events only) until ctx is cancelled, when the channel is closed.
+// An offset beyond the end of the file means the log has rotated since it
+// was recorded, so the new file is read from the start.
+//
+// When the server restarts, Minecraft compresses latest.log and starts a new
+// one; the subscription notices and carries on with the new file.
+func (s *Stream) Subscribe(ctx context.Context, from int64) <-chan Event {
+	lines := logtail.Follow(ctx, s.Path, logtail.Options{From: from, PollInterval: s.PollInterval})
+	parser := s.Parser
+	if parser == nil {
+		parser = defaultParser
+	}
+
+	ch := make(chan Event, 64)
+	go func() {
+		defer close(ch)
+		for l := range lines {
+			ev, ok := parser.Parse(l.Text, time.Now())
+			if !ok {
+				continue
+			}
+			ev.Time = notInFuture(ev.Time)
+			ev.Offset = l.Offset
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// notInFuture moves a time stamped with today's date back a day if that puts
+// it in the future: the line was logged just before midnight.
+func notInFuture(t time.Time) time.Time {
+	if t.After(time.Now().Add(time.Minute)) {
+		return t.AddDate(0, 0, -1)
+	}
+	return t
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	joinLine  = "[18:02:11] [Server thread/INFO]: Steve joined the game\n"
+	leaveLine = "[18:40:00] [Server thread/INFO]: Steve left the game\n"
+	noise     = "[18:00:01] [Server thread/INFO]: Preparing spawn area: 42%\n"
+)
+
+func testStream(t *testing.T) (*Stream, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "logs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	s := NewStream(dir)
+	s.PollInterval = 10 * time.Millisecond
+	return s, s.Path
+}
+
+func appendLog(t *testing.T, path, text string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func next(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case ev, ok := <-ch:
+		if !ok {
+			t.Fatal("stream closed")
+		}
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	return Event{}
+}
+
+func TestSubscribeFromEndSkipsHistory(t *testing.T) {
+	s, path := testStream(t)
+	appendLog(t, path, joinLine)
+
+	ch := s.Subscribe(t.Context(), FromEnd)
+	// Give the follower time to open the file and seek to its end.
+	time.Sleep(50 * time.Millisecond)
+	appendLog(t, path, noise+leaveLine)
+
+	if ev := next(t, ch); ev.Kind != Leave {
+		t.Fatalf("got %q event, want leave (history should be skipped)", ev.Kind)
+	}
+}
+
+func TestSubscribeReplaysFromOffset(t *testing.T) {
+	s, path := testStream(t)
+	appendLog(t, path, joinLine+leaveLine)
+
+	first := next(t, s.Subscribe(t.Context(), 0))
+	if first.Kind != Join || first.Offset != int64(len(joinLine)) {
+		t.Fatalf("first event = %q at %d, want join at %d", first.Kind, first.Offset, len(joinLine))
+	}
+
+	// Resuming from the first event's offset yields the second.
+	if ev := next(t, s.Subscribe(t.Context(), first.Offset)); ev.Kind != Leave {
+		t.Fatalf("resumed event = %q, want leave", ev.Kind)
+	}
+}
+
+func TestSubscribeSurvivesRotation(t *testing.T) {
+	s, path := testStream(t)
+	appendLog(t, path, noise)
+
+	ch := s.Subscribe(t.Context(), FromEnd)
+	time.Sleep(50 * time.Millisecond)
+	appendLog(t, path, joinLine)
+	if ev := next(t, ch); ev.Kind != Join {
+		t.Fatalf("got %q, want join", ev.Kind)
+	}
+
+	// Restart: the old log is moved aside and a fresh one started.
+	if err := os.Rename(path, filepath.Join(filepath.Dir(path), "2026-10-17-1.log")); err != nil {
+		t.Fatal(err)
+	}
+	appendLog(t, path, leaveLine)
+
+	if ev := next(t, ch); ev.Kind != Leave || ev.Offset != int64(len(leaveLine)) {
+		t.Fatalf("got %q at %d, want leave at %d in the new file", ev.Kind, ev.Offset, len(leaveLine))
+	}
+}
@@ -0,0 +1,206 @@
+// Package idle stops a server that has been empty for a while and wakes it
+// again when someone tries to join. While the server sleeps, the game port
+// is held by a stand-in that answers the server list with a "join to wake"
+// MOTD and turns the first login attempt into a server start.
+package idle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// Defaults for the optional Config fields.
+const (
+	DefaultPollInterval = 30 * time.Second
+	DefaultMOTD         = "Sleeping — join to wake"
+	DefaultWakeMessage  = "Server is starting — rejoin in 60 seconds"
+)
+
+// Timeouts for state transitions. A stop that never frees the port or a
+// start that never opens it is reported and retried, not waited on forever.
+const (
+	stopTimeout  = 3 * time.Minute
+	startTimeout = 5 * time.Minute
+	// externalCheck is how often the sleeping listener checks whether the
+	// server was started by something else (a cron job, an admin).
+	externalCheck = 5 * time.Second
+	// bindRetry is the pause before retrying a failed bind of the game port.
+	bindRetry = 5 * time.Second
+	// queryFailures is how many polls in a row may fail to count players
+	// before it is reported; a starting server doesn't answer for a while.
+	queryFailures = 10
+)
+
+// Config configures idle shutdown and wake-on-connect.
+type Config struct {
+	Manager management.ServerManager
+	Runner  platform.CommandRunner
+	Port    int
+	// Timeout is how long the server must be empty before it is stopped.
+	Timeout time.Duration
+	// PollInterval is how often the player count is checked.
+	PollInterval time.Duration
+	// MOTD is shown in the server list while asleep.
+	MOTD string
+	// WakeMessage is the disconnect reason shown to the player who woke it.
+	WakeMessage string
+	// MaxPlayers is advertised in the server list while asleep.
+	MaxPlayers int
+	Output     *ui.UI
+}
+
+// Run alternates between watching a running server for idleness and
+// sleeping on its port until a player tries to join. It returns when ctx is
+// cancelled.
+func Run(ctx context.Context, cfg *Config) error {
+	if cfg.Timeout <= 0 {
+		return errors.New("idle timeout must be positive")
+	}
+	if _, ok := cfg.Manager.(management.Querier); !ok {
+		return errors.New("idle mode needs RCON to count players")
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.MOTD == "" {
+		cfg.MOTD = DefaultMOTD
+	}
+	if cfg.WakeMessage == "" {
+		cfg.WakeMessage = DefaultWakeMessage
+	}
+
+	for ctx.Err() == nil {
+		if serverUp(ctx, cfg) {
+			if err := watch(ctx, cfg); err != nil {
+				cfg.Output.Warn("Idle shutdown failed: %s", err)
+				if err := management.Sleep(ctx, int(bindRetry.Seconds())); err != nil {
+					return nil
+				}
+				continue
+			}
+			stopped := waitFor(ctx, stopTimeout, func() bool {
+				return !serverUp(ctx, cfg) && !management.IsPortListening(cfg.Port)
+			})
+			if !stopped && ctx.Err() == nil {
+				cfg.Output.Warn("Server still running %s after stop", stopTimeout)
+			}
+			continue
+		}
+
+		if err := sleepUntilWoken(ctx, cfg); err != nil {
+			cfg.Output.Warn("Wake listener: %s", err)
+			if err := management.Sleep(ctx, int(bindRetry.Seconds())); err != nil {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// watch polls the player count and stops the server once it has been empty
+// for cfg.Timeout. It returns nil after a stop, or when the server goes down
+// on its own.
+func watch(ctx context.Context, cfg *Config) error {
+	q := cfg.Manager.(management.Querier)
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	var emptySince time.Time
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if !serverUp(ctx, cfg) {
+			return nil
+		}
+		players, err := management.ListPlayers(ctx, q)
+		if err != nil {
+			// Unknown is not empty: a server still starting up or an
+			// RCON hiccup must never trigger a shutdown.
+			emptySince = time.Time{}
+			if failures++; failures == queryFailures {
+				cfg.Output.Warn("Idle shutdown can't count players, so the server won't be stopped: %s (is rcon.password set in server.properties?)", err)
+			}
+			continue
+		}
+		failures = 0
+		if players.Online > 0 {
+			emptySince = time.Time{}
+			continue
+		}
+		if emptySince.IsZero() {
+			emptySince = time.Now()
+			continue
+		}
+		if time.Since(emptySince) < cfg.Timeout {
+			continue
+		}
+
+		cfg.Output.Info("No players for %s — stopping server until someone joins", cfg.Timeout)
+		// Nobody is online to warn, so skip StopServer's countdown.
+		if err := cfg.Manager.SendCommand(ctx, "stop"); err != nil {
+			return cfg.Manager.Stop(ctx)
+		}
+		return nil
+	}
+}
+
+// sleepUntilWoken holds the game port until a login attempt, then starts the
+// server and waits for it to take the port back.
+func sleepUntilWoken(ctx context.Context, cfg *Config) error {
+	cfg.Output.Info("Server asleep — listening on port %d for players", cfg.Port)
+	w := &wakeResponder{motd: cfg.MOTD, kick: cfg.WakeMessage, maxPlayers: cfg.MaxPlayers}
+	woke, err := listenForWake(ctx, cfg.Port, w, func(ctx context.Context) bool {
+		return !serverUp(ctx, cfg)
+	}, externalCheck)
+	if err != nil {
+		return fmt.Errorf("binding port %d: %w", cfg.Port, err)
+	}
+	if !woke {
+		return nil
+	}
+
+	cfg.Output.Info("Player tried to join — waking server")
+	if _, err := management.StartServer(ctx, cfg.Manager, cfg.Runner, cfg.Port, cfg.Manager.Session(), cfg.Output); err != nil {
+		return err
+	}
+	if !waitFor(ctx, startTimeout, func() bool { return management.IsPortListening(cfg.Port) }) && ctx.Err() == nil {
+		cfg.Output.Warn("Server did not open port %d within %s", cfg.Port, startTimeout)
+	}
+	return nil
+}
+
+// serverUp reports whether the server process is alive. Unlike
+// management.IsServerRunning it never probes the port, which the wake
+// listener itself may be holding.
+func serverUp(ctx context.Context, cfg *Config) bool {
+	if cfg.Manager.IsRunning(ctx) {
+		return true
+	}
+	stats, err := management.GetProcessStats(ctx, cfg.Runner)
+	return err == nil && stats.PID > 0
+}
+
+// waitFor polls cond every second until it holds or timeout elapses.
+func waitFor(ctx context.Context, timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		if err := management.Sleep(ctx, 1); err != nil {
+			return false
+		}
+	}
+	return false
+}
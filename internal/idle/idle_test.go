@@ -0,0 +1,213 @@
+package idle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/mcproto"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// listing returns a manager that answers "list" with reply.
+func listing(reply string) *management.MockManager {
+	mgr := management.NewMockManager()
+	mgr.Replies["list"] = reply
+	return mgr
+}
+
+func handshake(t *testing.T, conn net.Conn, state int32) {
+	t.Helper()
+	hs := mcproto.Handshake{Protocol: 767, Address: "localhost", Port: 25565, NextState: state}
+	if err := mcproto.WritePacket(conn, mcproto.PacketHandshake, mcproto.AppendHandshake(nil, hs)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readString(t *testing.T, payload []byte) string {
+	t.Helper()
+	s, err := mcproto.ReadString(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestWakeResponderStatus(t *testing.T) {
+	server, client := net.Pipe()
+	defer func() { _ = client.Close() }()
+
+	w := &wakeResponder{motd: "zzz", kick: "waking", maxPlayers: 20}
+	done := make(chan bool, 1)
+	go func() { done <- w.handle(server) }()
+
+	handshake(t, client, mcproto.StateStatus)
+	if err := mcproto.WritePacket(client, mcproto.PacketStatusRequest, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	br := bufio.NewReader(client)
+	id, payload, err := mcproto.ReadPacket(br)
+	if err != nil || id != mcproto.PacketStatusResponse {
+		t.Fatalf("status response = %d, %v", id, err)
+	}
+	var st mcproto.Status
+	if err := json.Unmarshal([]byte(readString(t, payload)), &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Description.Text != "zzz" || st.Version.Protocol != 767 || st.Players.Max != 20 {
+		t.Errorf("status = %+v", st)
+	}
+
+	ping := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	if err := mcproto.WritePacket(client, mcproto.PacketPing, ping); err != nil {
+		t.Fatal(err)
+	}
+	id, payload, err = mcproto.ReadPacket(br)
+	if err != nil || id != mcproto.PacketPong || !bytes.Equal(payload, ping) {
+		t.Fatalf("pong = %d, %x, %v", id, payload, err)
+	}
+
+	if <-done {
+		t.Error("a status ping must not wake the server")
+	}
+}
+
+func TestWakeResponderLogin(t *testing.T) {
+	server, client := net.Pipe()
+	defer func() { _ = client.Close() }()
+
+	w := &wakeResponder{motd: "zzz", kick: "starting, rejoin in 60 s"}
+	done := make(chan bool, 1)
+	go func() { done <- w.handle(server) }()
+
+	handshake(t, client, mcproto.StateLogin)
+	if err := mcproto.WritePacket(client, mcproto.PacketLoginStart, mcproto.AppendString(nil, "Steve")); err != nil {
+		t.Fatal(err)
+	}
+
+	id, payload, err := mcproto.ReadPacket(bufio.NewReader(client))
+	if err != nil || id != mcproto.PacketLoginDisconnect {
+		t.Fatalf("disconnect = %d, %v", id, err)
+	}
+	if reason := readString(t, payload); !strings.Contains(reason, "rejoin in 60 s") {
+		t.Errorf("reason = %s", reason)
+	}
+	if !<-done {
+		t.Error("a login attempt must wake the server")
+	}
+}
+
+func TestListenForWake(t *testing.T) {
+	lc := net.ListenConfig{}
+	probe, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := probe.Addr().(*net.TCPAddr).Port
+	_ = probe.Close()
+
+	w := &wakeResponder{kick: "bye"}
+	result := make(chan bool, 1)
+	go func() {
+		woke, err := listenForWake(context.Background(), port, w, func(context.Context) bool { return true }, time.Hour)
+		if err != nil {
+			t.Errorf("listenForWake() error = %v", err)
+		}
+		result <- woke
+	}()
+
+	var conn net.Conn
+	d := net.Dialer{}
+	for range 50 {
+		if conn, err = d.DialContext(context.Background(), "tcp", probe.Addr().String()); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if conn == nil {
+		t.Fatalf("dialing wake listener: %v", err)
+	}
+	handshake(t, conn, mcproto.StateLogin)
+	_ = mcproto.WritePacket(conn, mcproto.PacketLoginStart, mcproto.AppendString(nil, "Steve"))
+	_, _, _ = mcproto.ReadPacket(bufio.NewReader(conn))
+	_ = conn.Close()
+
+	select {
+	case woke := <-result:
+		if !woke {
+			t.Fatal("expected the listener to report a wake")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener did not return after a login attempt")
+	}
+}
+
+func TestWatchStopsEmptyServer(t *testing.T) {
+	mgr := listing("There are 0 of a max of 20 players online:")
+	cfg := &Config{
+		Manager:      mgr,
+		Runner:       platform.NewMockRunner(),
+		Timeout:      20 * time.Millisecond,
+		PollInterval: 5 * time.Millisecond,
+		Output:       ui.NewWriter(&bytes.Buffer{}, false),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := watch(ctx, cfg); err != nil {
+		t.Fatalf("watch() error = %v", err)
+	}
+	if !mgr.Sent("stop") {
+		t.Fatal("expected stop to be sent to an empty server")
+	}
+}
+
+func TestWatchKeepsOccupiedServer(t *testing.T) {
+	mgr := listing("There are 1 of a max of 20 players online: Steve")
+	cfg := &Config{
+		Manager:      mgr,
+		Runner:       platform.NewMockRunner(),
+		Timeout:      time.Millisecond,
+		PollInterval: time.Millisecond,
+		Output:       ui.NewWriter(&bytes.Buffer{}, false),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_ = watch(ctx, cfg)
+	if mgr.Sent("stop") {
+		t.Fatal("server with players online must not be stopped")
+	}
+}
+
+func TestWatchWarnsWithoutQuery(t *testing.T) {
+	mgr := management.NewMockManager()
+	mgr.QueryErr = errors.New("rcon: authentication failed")
+	var out bytes.Buffer
+	cfg := &Config{
+		Manager:      mgr,
+		Runner:       platform.NewMockRunner(),
+		Timeout:      time.Millisecond,
+		PollInterval: time.Millisecond,
+		Output:       ui.NewWriter(&out, false),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_ = watch(ctx, cfg)
+	if mgr.Sent("stop") {
+		t.Fatal("server whose players can't be counted must not be stopped")
+	}
+	if n := strings.Count(out.String(), "can't count players"); n != 1 {
+		t.Errorf("warned %d times, want once:\n%s", n, out.String())
+	}
+}
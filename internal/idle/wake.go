@@ -0,0 +1,144 @@
+package idle
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/mcproto"
+)
+
+// connTimeout bounds how long one client may take to finish its exchange
+// with the wake listener.
+const connTimeout = 10 * time.Second
+
+// acceptRetry is the first pause after a failed accept; it doubles with
+// each failure in a row, up to maxAcceptRetry.
+const (
+	acceptRetry    = 5 * time.Millisecond
+	maxAcceptRetry = time.Second
+)
+
+// wakeResponder answers clients while the real server is asleep.
+type wakeResponder struct {
+	motd       string
+	kick       string
+	maxPlayers int
+}
+
+// handle serves one connection and reports whether the client tried to log
+// in, which is the signal to wake the server.
+func (w *wakeResponder) handle(conn net.Conn) (login bool) {
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(connTimeout))
+
+	br := bufio.NewReader(conn)
+	first, err := br.Peek(1)
+	if err != nil || first[0] == mcproto.LegacyPingByte {
+		return false
+	}
+
+	id, payload, err := mcproto.ReadPacket(br)
+	if err != nil || id != mcproto.PacketHandshake {
+		return false
+	}
+	hs, err := mcproto.ParseHandshake(payload)
+	if err != nil {
+		return false
+	}
+
+	switch hs.NextState {
+	case mcproto.StateStatus:
+		w.serveStatus(br, conn, hs.Protocol)
+		return false
+	case mcproto.StateLogin, mcproto.StateTransfer:
+		// Login Start carries the player name; it isn't needed, but
+		// reading it keeps well-behaved clients from seeing a reset.
+		_, _, _ = mcproto.ReadPacket(br)
+		_ = mcproto.WriteLoginDisconnect(conn, mcproto.Text{Text: w.kick, Color: "yellow"})
+		return true
+	default:
+		return false
+	}
+}
+
+// serveStatus answers a Server List Ping: the status request, then the
+// optional ping used to show latency.
+func (w *wakeResponder) serveStatus(br *bufio.Reader, conn net.Conn, protocol int32) {
+	id, _, err := mcproto.ReadPacket(br)
+	if err != nil || id != mcproto.PacketStatusRequest {
+		return
+	}
+	err = mcproto.WriteStatus(conn, &mcproto.Status{
+		// Echo the client's protocol so the entry isn't flagged as
+		// an incompatible version.
+		Version:     mcproto.StatusVersion{Name: "Sleeping", Protocol: protocol},
+		Players:     mcproto.StatusPlayers{Max: w.maxPlayers},
+		Description: mcproto.Text{Text: w.motd, Color: "gray"},
+	})
+	if err != nil {
+		return
+	}
+	id, payload, err := mcproto.ReadPacket(br)
+	if err != nil || id != mcproto.PacketPing {
+		return
+	}
+	_ = mcproto.WritePacket(conn, mcproto.PacketPong, payload)
+}
+
+// listenForWake binds the game port and answers clients until one tries to
+// log in (returns true), ctx is cancelled, or stillAsleep reports that the
+// server was started some other way (both return false).
+func listenForWake(ctx context.Context, port int, w *wakeResponder, stillAsleep func(context.Context) bool, checkEvery time.Duration) (bool, error) {
+	lc := net.ListenConfig{}
+	ln, err := lc.Listen(ctx, "tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = ln.Close() }()
+
+	woke := make(chan struct{}, 1)
+	go func() {
+		var pause time.Duration
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
+				// Out of file descriptors, say: wait for some to close
+				// rather than spin.
+				pause = min(max(2*pause, acceptRetry), maxAcceptRetry)
+				time.Sleep(pause)
+				continue
+			}
+			pause = 0
+			go func() {
+				if w.handle(conn) {
+					select {
+					case woke <- struct{}{}:
+					default:
+					}
+				}
+			}()
+		}
+	}()
+
+	ticker := time.NewTicker(checkEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-woke:
+			return true, nil
+		case <-ticker.C:
+			if !stillAsleep(ctx) {
+				return false, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Package lan announces the server to Minecraft clients on the local network
+// so that it appears under "LAN Worlds" in the multiplayer menu, the same
+// way "Open to LAN" does in single player.
+package lan
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// MulticastAddr is the group and port Java Edition clients listen on.
+const MulticastAddr = "224.0.2.60:4445"
+
+// Interval matches the vanilla client's own LAN broadcast period.
+const Interval = 1500 * time.Millisecond
+
+// runningCheck is how often the announcer re-checks that the server is up.
+// Probing on every 1.5 s broadcast would mean a pgrep each time.
+const runningCheck = 10 * time.Second
+
+// Config configures the LAN announcer.
+type Config struct {
+	Manager management.ServerManager
+	Runner  platform.CommandRunner
+	Port    int
+	MOTD    string
+	// Interface optionally names the network interface to announce on
+	// (e.g. "wlan0"). Empty uses the default multicast route.
+	Interface string
+	Output    *ui.UI
+}
+
+// Payload returns the announcement datagram for motd and port.
+func Payload(motd string, port int) []byte {
+	// A stray closing tag would truncate the MOTD the client displays.
+	motd = strings.ReplaceAll(motd, "[/MOTD]", "")
+	return fmt.Appendf(nil, "[MOTD]%s[/MOTD][AD]%d[/AD]", motd, port)
+}
+
+// Run broadcasts the announcement every Interval while the server is
+// running, until ctx is cancelled.
+func Run(ctx context.Context, cfg *Config) error {
+	conn, err := dial(ctx, cfg.Interface)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	payload := Payload(cfg.MOTD, cfg.Port)
+	ticker := time.NewTicker(Interval)
+	defer ticker.Stop()
+
+	var running bool
+	var checked time.Time
+	for {
+		if time.Since(checked) >= runningCheck {
+			now := management.IsServerRunning(ctx, cfg.Manager, cfg.Runner, cfg.Port)
+			if now != running {
+				if now {
+					cfg.Output.Info("Announcing %q on the LAN (port %d)", cfg.MOTD, cfg.Port)
+				} else {
+					cfg.Output.Info("Server stopped — pausing LAN announcements")
+				}
+			}
+			running, checked = now, time.Now()
+		}
+		if running {
+			// A dropped datagram is harmless; the next one follows shortly.
+			_, _ = conn.Write(payload)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// dial opens a UDP socket to the LAN multicast group. Binding to an
+// interface's own address makes the kernel send the multicast out of that
+// interface.
+func dial(ctx context.Context, iface string) (net.Conn, error) {
+	d := net.Dialer{}
+	if iface != "" {
+		ip, err := interfaceIPv4(iface)
+		if err != nil {
+			return nil, err
+		}
+		d.LocalAddr = &net.UDPAddr{IP: ip}
+	}
+	conn, err := d.DialContext(ctx, "udp4", MulticastAddr)
+	if err != nil {
+		return nil, fmt.Errorf("lan broadcast: %w", err)
+	}
+	return conn, nil
+}
+
+// interfaceIPv4 returns the first IPv4 address of the named interface.
+func interfaceIPv4(name string) (net.IP, error) {
+	ifi, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("lan interface %q: %w", name, err)
+	}
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("lan interface %q: %w", name, err)
+	}
+	for _, a := range addrs {
+		if ipnet, ok := a.(*net.IPNet); ok {
+			if ip4 := ipnet.IP.To4(); ip4 != nil {
+				return ip4, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("lan interface %q has no IPv4 address", name)
+}
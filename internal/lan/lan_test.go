@@ -0,0 +1,35 @@
+package lan
+
+import (
+	"testing"
+)
+
+func TestPayload(t *testing.T) {
+	tests := []struct {
+		motd string
+		port int
+		want string
+	}{
+		{motd: "Dads Minecraft Server", port: 25565, want: "[MOTD]Dads Minecraft Server[/MOTD][AD]25565[/AD]"},
+		{motd: "Sneaky [/MOTD] name", port: 25570, want: "[MOTD]Sneaky  name[/MOTD][AD]25570[/AD]"},
+	}
+	for _, tt := range tests {
+		if got := string(Payload(tt.motd, tt.port)); got != tt.want {
+			t.Errorf("Payload(%q, %d) = %q, want %q", tt.motd, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestInterfaceIPv4UnknownInterface(t *testing.T) {
+	if _, err := interfaceIPv4("definitely-not-an-interface0"); err == nil {
+		t.Fatal("expected an error for an unknown interface")
+	}
+}
+
+func TestDialDefaultRoute(t *testing.T) {
+	conn, err := dial(t.Context(), "")
+	if err != nil {
+		t.Skipf("no multicast route in this environment: %v", err)
+	}
+	_ = conn.Close()
+}
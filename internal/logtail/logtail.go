@@ -0,0 +1,197 @@
+// Package logtail follows a server log file the way `tail -F` does: it
+// waits for the file to appear, delivers complete lines as they are
+// written, and carries on with the new file when the server rotates
+// latest.log on restart.
+package logtail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// FromEnd starts following at the current end of the file.
+const FromEnd int64 = -1
+
+// DefaultPollInterval is how often the file is checked for new lines.
+const DefaultPollInterval = 250 * time.Millisecond
+
+// maxLineLength bounds how much of an unterminated line is buffered before
+// it is delivered as is.
+const maxLineLength = 1024 * 1024
+
+// replayChunk is how much is read at a time when scanning backwards for the
+// lines to replay.
+const replayChunk = 8 * 1024
+
+// Line is one complete line read from a log file.
+type Line struct {
+	Text string
+	// File is the path the line was read from.
+	File string
+	// Offset is the byte offset just past the line in that file. Following
+	// again from it resumes after this line.
+	Offset int64
+}
+
+// Options controls where following starts.
+type Options struct {
+	// From is the byte offset to start at, or FromEnd. An offset beyond
+	// the end of the file means the file has rotated since the offset was
+	// recorded, so the new file is read from the start.
+	From int64
+	// Replay delivers up to this many lines before From first, so a viewer
+	// attaching to a running server sees recent context.
+	Replay int
+	// PollInterval is how often the file is checked for new lines.
+	PollInterval time.Duration
+}
+
+// Follow delivers lines from path until ctx is cancelled, when the channel
+// is closed. It waits for the file to exist, and reopens it when it is
+// replaced (Minecraft compresses latest.log and starts a new one on
+// restart) or truncated.
+func Follow(ctx context.Context, path string, opts Options) <-chan Line {
+	poll := opts.PollInterval
+	if poll <= 0 {
+		poll = DefaultPollInterval
+	}
+	out := make(chan Line, 64)
+	go follow(ctx, path, opts.From, opts.Replay, poll, out)
+	return out
+}
+
+func follow(ctx context.Context, path string, from int64, replay int, poll time.Duration, out chan<- Line) {
+	defer close(out)
+
+	for {
+		f, info, waited, err := openWhenPresent(ctx, path, poll)
+		if err != nil {
+			return
+		}
+		switch {
+		case waited || from > info.Size():
+			// A file that appeared while we waited, or one shorter than
+			// the requested offset, is a new log: read all of it.
+			from = 0
+		case from < 0:
+			from = info.Size()
+		}
+		if replay > 0 {
+			from = replayStart(f, from, replay)
+			replay = 0
+		}
+		next, err := readUntilReplaced(ctx, f, info, path, from, poll, out)
+		_ = f.Close()
+		if err != nil {
+			return
+		}
+		from = next
+	}
+}
+
+// openWhenPresent opens path, polling until it exists. waited reports
+// whether it was missing at first.
+func openWhenPresent(ctx context.Context, path string, poll time.Duration) (f *os.File, info os.FileInfo, waited bool, err error) {
+	for {
+		f, err = os.Open(path)
+		if err == nil {
+			info, err = f.Stat()
+			if err == nil {
+				return f, info, waited, nil
+			}
+			_ = f.Close()
+		}
+		waited = true
+		select {
+		case <-ctx.Done():
+			return nil, nil, false, ctx.Err()
+		case <-time.After(poll):
+		}
+	}
+}
+
+// replayStart returns the offset of the start of the n-th complete line
+// before offset, or 0 if there are fewer.
+func replayStart(f *os.File, offset int64, n int) int64 {
+	buf := make([]byte, replayChunk)
+	// The newline ending the line just before offset doesn't start a line;
+	// we need to find n+1 newlines to have n lines after the last one.
+	need := n + 1
+	end := offset
+	for end > 0 {
+		start := max(0, end-replayChunk)
+		chunk := buf[:end-start]
+		if _, err := f.ReadAt(chunk, start); err != nil && !errors.Is(err, io.EOF) {
+			return offset
+		}
+		for i := len(chunk) - 1; i >= 0; i-- {
+			if chunk[i] != '\n' {
+				continue
+			}
+			need--
+			if need == 0 {
+				return start + int64(i) + 1
+			}
+		}
+		end = start
+	}
+	return 0
+}
+
+// readUntilReplaced reads lines from f starting at offset until path names a
+// different file or f is truncated. It returns the offset to continue from
+// in the file now at path: 0 after a rotation or truncation.
+func readUntilReplaced(ctx context.Context, f *os.File, opened os.FileInfo, path string, offset int64, poll time.Duration, out chan<- Line) (int64, error) {
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	r := bufio.NewReader(f)
+	var pending []byte
+
+	for {
+		chunk, err := r.ReadBytes('\n')
+		pending = append(pending, chunk...)
+		if err == nil || len(pending) >= maxLineLength {
+			offset += int64(len(pending))
+			text := string(bytes.TrimRight(pending, "\r\n"))
+			pending = pending[:0]
+			select {
+			case out <- Line{Text: text, File: path, Offset: offset}:
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+
+		// Caught up. Before waiting, check whether the server has moved on
+		// to a new file; anything left in the old one was already read.
+		if replaced(path, opened, offset+int64(len(pending))) {
+			return 0, nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(poll):
+		}
+	}
+}
+
+// replaced reports whether path no longer names the opened file, or the file
+// has shrunk below what was already read from it.
+func replaced(path string, opened os.FileInfo, read int64) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		// Mid-rotation: the old file is gone and the new one not yet
+		// created. Keep waiting on the old handle.
+		return false
+	}
+	return !os.SameFile(opened, info) || info.Size() < read
+}
@@ -0,0 +1,174 @@
+package logtail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const poll = 10 * time.Millisecond
+
+func appendLog(t *testing.T, path, text string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func next(t *testing.T, ch <-chan Line) Line {
+	t.Helper()
+	select {
+	case l, ok := <-ch:
+		if !ok {
+			t.Fatal("follower closed")
+		}
+		return l
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a line")
+	}
+	return Line{}
+}
+
+func logPath(t *testing.T) string {
+	t.Helper()
+	return filepath.Join(t.TempDir(), "latest.log")
+}
+
+func TestFollowFromEnd(t *testing.T) {
+	path := logPath(t)
+	appendLog(t, path, "old\n")
+
+	ch := Follow(t.Context(), path, Options{From: FromEnd, PollInterval: poll})
+	time.Sleep(50 * time.Millisecond)
+	appendLog(t, path, "new\n")
+
+	l := next(t, ch)
+	if l.Text != "new" || l.File != path || l.Offset != int64(len("old\nnew\n")) {
+		t.Fatalf("got %+v, want \"new\" ending at %d", l, len("old\nnew\n"))
+	}
+}
+
+func TestFollowReplaysLastLines(t *testing.T) {
+	path := logPath(t)
+	appendLog(t, path, "one\ntwo\nthree\nfour\n")
+
+	ch := Follow(t.Context(), path, Options{From: FromEnd, Replay: 2, PollInterval: poll})
+	for _, want := range []string{"three", "four"} {
+		if l := next(t, ch); l.Text != want {
+			t.Fatalf("replayed %q, want %q", l.Text, want)
+		}
+	}
+
+	// Asking for more lines than exist replays the whole file.
+	ch = Follow(t.Context(), path, Options{From: FromEnd, Replay: 10, PollInterval: poll})
+	if l := next(t, ch); l.Text != "one" {
+		t.Fatalf("replayed %q first, want %q", l.Text, "one")
+	}
+}
+
+func TestReplayStartAcrossChunks(t *testing.T) {
+	path := logPath(t)
+	long := make([]byte, replayChunk+100)
+	for i := range long {
+		long[i] = 'x'
+	}
+	appendLog(t, path, "first\n"+string(long)+"\nlast\n")
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	info, _ := f.Stat()
+
+	if got, want := replayStart(f, info.Size(), 2), int64(len("first\n")); got != want {
+		t.Fatalf("replayStart = %d, want %d", got, want)
+	}
+}
+
+func TestFollowWaitsForFile(t *testing.T) {
+	path := logPath(t)
+	ch := Follow(t.Context(), path, Options{From: FromEnd, PollInterval: poll})
+
+	time.Sleep(30 * time.Millisecond)
+	appendLog(t, path, "hello\n")
+
+	// The file did not exist when following started, so all of it is new
+	// even though FromEnd was requested.
+	if l := next(t, ch); l.Text != "hello" {
+		t.Fatalf("got %q, want hello", l.Text)
+	}
+}
+
+func TestFollowSurvivesRotation(t *testing.T) {
+	path := logPath(t)
+	appendLog(t, path, "before\n")
+
+	ch := Follow(t.Context(), path, Options{From: FromEnd, PollInterval: poll})
+	time.Sleep(50 * time.Millisecond)
+	appendLog(t, path, "last of old\n")
+	if l := next(t, ch); l.Text != "last of old" {
+		t.Fatalf("got %q", l.Text)
+	}
+
+	// Restart: the old log is moved aside and a fresh one started.
+	if err := os.Rename(path, filepath.Join(filepath.Dir(path), "2026-10-17-1.log")); err != nil {
+		t.Fatal(err)
+	}
+	appendLog(t, path, "first of new\n")
+
+	if l := next(t, ch); l.Text != "first of new" || l.Offset != int64(len("first of new\n")) {
+		t.Fatalf("got %+v, want the first line of the new file", l)
+	}
+}
+
+func TestFollowSurvivesTruncation(t *testing.T) {
+	path := logPath(t)
+	appendLog(t, path, "a long line that will be truncated away\n")
+
+	ch := Follow(t.Context(), path, Options{From: FromEnd, PollInterval: poll})
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("fresh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if l := next(t, ch); l.Text != "fresh" {
+		t.Fatalf("got %q, want fresh", l.Text)
+	}
+}
+
+func TestFollowHoldsPartialLines(t *testing.T) {
+	path := logPath(t)
+	appendLog(t, path, "")
+
+	ch := Follow(t.Context(), path, Options{PollInterval: poll})
+	appendLog(t, path, "half a ")
+	time.Sleep(50 * time.Millisecond)
+	appendLog(t, path, "line\r\n")
+
+	if l := next(t, ch); l.Text != "half a line" {
+		t.Fatalf("got %q, want the joined line", l.Text)
+	}
+}
+
+func TestFollowClosesOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	ch := Follow(ctx, logPath(t), Options{From: FromEnd, PollInterval: poll})
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("unexpected line")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
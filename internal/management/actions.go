@@ -3,6 +3,7 @@ package management
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
@@ -23,6 +24,7 @@ func StartServer(ctx context.Context, mgr ServerManager, runner platform.Command
 		return false, fmt.Errorf("starting server: %w", err)
 	}
 	output.Success("Server started!")
+	notify(ctx, EventStart, "")
 	return false, nil
 }
 
@@ -52,6 +54,7 @@ func StopServer(ctx context.Context, mgr ServerManager, runner platform.CommandR
 	}
 
 	output.Info("Starting graceful shutdown (30s countdown)...")
+	notify(ctx, EventStop, "")
 
 	// Try graceful in-game countdown + stop command.
 	countdownOK := true
@@ -85,6 +88,28 @@ func StopServer(ctx context.Context, mgr ServerManager, runner platform.CommandR
 	return nil
 }
 
+// restartTimeout is how long RestartServer waits for the server to exit.
+const restartTimeout = 3 * time.Minute
+
+// RestartServer stops the server with the usual countdown, waits for it to
+// exit and starts it again.
+func RestartServer(ctx context.Context, mgr ServerManager, runner platform.CommandRunner, port int, output *ui.UI) error {
+	if err := StopServer(ctx, mgr, runner, port, output); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(restartTimeout)
+	for IsServerRunning(ctx, mgr, runner, port) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("server still running %s after stop", restartTimeout)
+		}
+		if err := Sleep(ctx, 1); err != nil {
+			return err
+		}
+	}
+	_, err := StartServer(ctx, mgr, runner, port, mgr.Session(), output)
+	return err
+}
+
 // PrintStatus prints the server status and resource usage to output.
 func PrintStatus(ctx context.Context, mgr ServerManager, runner platform.CommandRunner, port int, sessionName string, output *ui.UI) {
 	output.Step("Minecraft Server Status")
@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -15,16 +16,83 @@ import (
 	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
 )
 
+// backupStatusFile records the outcome of the most recent backup so that
+// status reporting can tell a stale backup from a failing one.
+const backupStatusFile = "backup-status.json"
+
+// BackupStatus is the outcome of the most recent backup attempt.
+type BackupStatus struct {
+	Time    time.Time `json:"time"`
+	Success bool      `json:"success"`
+	File    string    `json:"file,omitempty"`
+	Size    int64     `json:"size,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// LoadBackupStatus reads the last recorded backup outcome for serverDir.
+// It returns nil and no error when no backup has been attempted yet.
+func LoadBackupStatus(serverDir string) (*BackupStatus, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, backupStatusFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading backup status: %w", err)
+	}
+	var st BackupStatus
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("parsing backup status: %w", err)
+	}
+	return &st, nil
+}
+
+// recordBackupStatus writes the outcome of a backup attempt and returns it.
+// Failures are ignored: the status file is advisory and must never fail a
+// backup.
+func recordBackupStatus(serverDir, backupFile string, archived bool, backupErr error) *BackupStatus {
+	st := BackupStatus{Time: time.Now(), Success: backupErr == nil && archived}
+	switch {
+	case backupErr != nil:
+		st.Error = backupErr.Error()
+	case !archived:
+		st.Error = "no world directories found"
+	default:
+		st.File = backupFile
+		if info, err := os.Stat(backupFile); err == nil {
+			st.Size = info.Size()
+		}
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return &st
+	}
+	_ = os.WriteFile(filepath.Join(serverDir, backupStatusFile), data, 0o644)
+	return &st
+}
+
 // Backup creates a compressed backup of world directories with rotation.
-func Backup(ctx context.Context, serverDir string, maxBackups int, mgr ServerManager, output *ui.UI) error {
+func Backup(ctx context.Context, serverDir string, maxBackups int, mgr ServerManager, output *ui.UI) (err error) {
 	backupDir := filepath.Join(serverDir, "backups")
+	timestamp := time.Now().Format("20060102_150405")
+	backupFile := filepath.Join(backupDir, fmt.Sprintf("world_%s.tar.gz", timestamp))
+
+	archived := false
+	defer func() {
+		st := recordBackupStatus(serverDir, backupFile, archived, err)
+		// An aborted backup is still worth reporting, so the caller's
+		// context being cancelled mustn't stop the notification.
+		notifyCtx := context.WithoutCancel(ctx)
+		if st.Success {
+			notify(notifyCtx, EventBackup, fmt.Sprintf("%s (%s)", filepath.Base(st.File), formatSize(st.Size)))
+		} else {
+			notify(notifyCtx, EventBackupFailed, st.Error)
+		}
+	}()
+
 	if err := os.MkdirAll(backupDir, 0o755); err != nil {
 		return fmt.Errorf("creating backup dir: %w", err)
 	}
 
-	timestamp := time.Now().Format("20060102_150405")
-	backupFile := filepath.Join(backupDir, fmt.Sprintf("world_%s.tar.gz", timestamp))
-
 	// Notify server and save. Auto-save is re-enabled from a defer so that a
 	// failed or aborted backup can never leave the live server with
 	// save-off still in effect.
@@ -46,7 +114,7 @@ func Backup(ctx context.Context, serverDir string, maxBackups int, mgr ServerMan
 
 	// Create backup
 	output.Info("Creating backup: %s", backupFile)
-	worlds := findWorldDirs(serverDir)
+	worlds := FindWorldDirs(serverDir)
 	if len(worlds) == 0 {
 		output.Warn("No world directories found to backup")
 		return nil
@@ -58,6 +126,7 @@ func Backup(ctx context.Context, serverDir string, maxBackups int, mgr ServerMan
 		_ = os.Remove(backupFile)
 		return fmt.Errorf("creating backup archive: %w", err)
 	}
+	archived = true
 
 	if mgr.IsRunning(ctx) {
 		_ = mgr.SendCommand(ctx, "say Backup complete!")
@@ -78,7 +147,9 @@ func Backup(ctx context.Context, serverDir string, maxBackups int, mgr ServerMan
 // worldDirs are the Minecraft world directories to include in backups.
 var worldDirs = []string{"world", "world_nether", "world_the_end"}
 
-func findWorldDirs(serverDir string) []string {
+// FindWorldDirs returns the names of the world directories present in
+// serverDir.
+func FindWorldDirs(serverDir string) []string {
 	candidates := worldDirs
 	var found []string
 	for _, name := range candidates {
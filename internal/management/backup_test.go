@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
 )
@@ -15,7 +16,7 @@ func TestFindWorldDirs(t *testing.T) {
 	dir := t.TempDir()
 
 	// No world dirs yet
-	if dirs := findWorldDirs(dir); len(dirs) != 0 {
+	if dirs := FindWorldDirs(dir); len(dirs) != 0 {
 		t.Errorf("expected no dirs, got %v", dirs)
 	}
 
@@ -27,7 +28,7 @@ func TestFindWorldDirs(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	dirs := findWorldDirs(dir)
+	dirs := FindWorldDirs(dir)
 	if len(dirs) != 2 {
 		t.Fatalf("expected 2 dirs, got %d: %v", len(dirs), dirs)
 	}
@@ -43,7 +44,7 @@ func TestFindWorldDirs_IgnoresFiles(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if dirs := findWorldDirs(dir); len(dirs) != 0 {
+	if dirs := FindWorldDirs(dir); len(dirs) != 0 {
 		t.Errorf("expected no dirs (file should be ignored), got %v", dirs)
 	}
 }
@@ -177,3 +178,86 @@ func TestBackupReenablesAutoSaveWhenArchivingFails(t *testing.T) {
 		t.Fatalf("expected no archive to be left behind, got %v", entries)
 	}
 }
+
+// stoppedManager is a recordingManager whose server is not running, so
+// Backup skips the save-off/save-on dance and its pauses.
+type stoppedManager struct{ recordingManager }
+
+func (m *stoppedManager) IsRunning(context.Context) bool { return false }
+
+func TestBackupRecordsStatus(t *testing.T) {
+	dir := t.TempDir()
+
+	st, err := LoadBackupStatus(dir)
+	if err != nil || st != nil {
+		t.Fatalf("LoadBackupStatus() before any backup = %v, %v; want nil, nil", st, err)
+	}
+
+	// No world directories: the attempt is recorded as unsuccessful.
+	if err := Backup(context.Background(), dir, 3, &stoppedManager{}, ui.New(false)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	st, err = LoadBackupStatus(dir)
+	if err != nil {
+		t.Fatalf("LoadBackupStatus() error = %v", err)
+	}
+	if st == nil || st.Success || st.Error == "" {
+		t.Fatalf("status = %+v, want an unsuccessful attempt with an error", st)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "world"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Backup(context.Background(), dir, 3, &stoppedManager{}, ui.New(false)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	st, err = LoadBackupStatus(dir)
+	if err != nil {
+		t.Fatalf("LoadBackupStatus() error = %v", err)
+	}
+	if !st.Success || st.File == "" || st.Error != "" {
+		t.Fatalf("status = %+v, want a successful backup with a file", st)
+	}
+}
+
+// recordingNotifier records the lifecycle events it is told about.
+type recordingNotifier struct {
+	events []string
+	// unbounded are the events given longer than notifyTimeout.
+	unbounded []string
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, event, _ string) {
+	n.events = append(n.events, event)
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > notifyTimeout {
+		n.unbounded = append(n.unbounded, event)
+	}
+}
+
+func TestBackupNotifies(t *testing.T) {
+	dir := t.TempDir()
+	n := &recordingNotifier{}
+	ctx := WithNotifier(t.Context(), n)
+
+	if err := Backup(ctx, dir, 3, &stoppedManager{}, ui.New(false)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "world"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Backup(ctx, dir, 3, &stoppedManager{}, ui.New(false)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Without a notifier in the context, nothing is reported.
+	if err := Backup(t.Context(), dir, 3, &stoppedManager{}, ui.New(false)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{EventBackupFailed, EventBackup}
+	if !slices.Equal(n.events, want) {
+		t.Errorf("events = %v, want %v", n.events, want)
+	}
+	if len(n.unbounded) != 0 {
+		t.Errorf("%v given longer than %s", n.unbounded, notifyTimeout)
+	}
+}
@@ -1,6 +1,10 @@
 package management
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 // ServerManager is the interface for managing a Minecraft server process,
 // whether via a GNU screen session or a container runtime.
@@ -34,3 +38,25 @@ type HealthChecker interface {
 	// Stats returns a formatted resource-usage string.
 	Stats(ctx context.Context) (string, error)
 }
+
+// StartTimeReporter is an optional interface for managers that know when
+// the server was started, such as a container runtime. Other managers fall
+// back to the age of the server process.
+type StartTimeReporter interface {
+	// StartedAt returns when the server was last started.
+	StartedAt(ctx context.Context) (time.Time, error)
+}
+
+// Querier is an optional interface for managers that can return the server's
+// reply to a console command, such as over RCON. Screen sessions cannot
+// capture output on their own, so callers must handle its absence.
+type Querier interface {
+	// Query sends a console command and returns the server's response.
+	// It returns an error wrapping ErrNoQuery when the command was never
+	// sent; other errors may come after the server received it.
+	Query(ctx context.Context, cmd string) (string, error)
+}
+
+// ErrNoQuery reports that a Querier couldn't connect to the server, so the
+// command it was given wasn't sent and may safely be sent another way.
+var ErrNoQuery = errors.New("no query connection")
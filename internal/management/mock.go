@@ -0,0 +1,89 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// MockManager is a running ServerManager and Querier for testing. It
+// records the commands sent and answers queries from Replies. It is safe
+// for concurrent use once set up.
+type MockManager struct {
+	// Replies answers queries by command.
+	Replies map[string]string
+	// QueryErr answers queries with no reply; without it they fail with
+	// an error naming the command.
+	QueryErr error
+	// SendErr, if set, is returned by SendCommand, which records nothing.
+	SendErr error
+	// OnCommand, if set, is called with each command recorded.
+	OnCommand func(cmd string)
+
+	mu       sync.Mutex
+	commands []string
+}
+
+// NewMockManager creates a MockManager with no replies.
+func NewMockManager() *MockManager {
+	return &MockManager{Replies: make(map[string]string)}
+}
+
+// IsRunning reports the server running.
+func (m *MockManager) IsRunning(context.Context) bool { return true }
+
+// SendCommand records cmd, or returns SendErr.
+func (m *MockManager) SendCommand(_ context.Context, cmd string) error {
+	if m.SendErr != nil {
+		return m.SendErr
+	}
+	m.mu.Lock()
+	m.commands = append(m.commands, cmd)
+	m.mu.Unlock()
+	if m.OnCommand != nil {
+		m.OnCommand(cmd)
+	}
+	return nil
+}
+
+// Launch does nothing.
+func (m *MockManager) Launch(context.Context) error { return nil }
+
+// Stop does nothing.
+func (m *MockManager) Stop(context.Context) error { return nil }
+
+// Session returns "test".
+func (m *MockManager) Session() string { return "test" }
+
+// Query returns the reply for cmd, or QueryErr.
+func (m *MockManager) Query(_ context.Context, cmd string) (string, error) {
+	if reply, ok := m.Replies[cmd]; ok {
+		return reply, nil
+	}
+	if m.QueryErr != nil {
+		return "", m.QueryErr
+	}
+	return "", fmt.Errorf("no reply to %q", cmd)
+}
+
+// Commands returns the commands sent so far, oldest first.
+func (m *MockManager) Commands() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.commands)
+}
+
+// Take returns the commands sent since the last call and forgets them.
+func (m *MockManager) Take() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cmds := m.commands
+	m.commands = nil
+	return cmds
+}
+
+// Sent reports whether cmd was sent.
+func (m *MockManager) Sent(cmd string) bool {
+	return slices.Contains(m.Commands(), cmd)
+}
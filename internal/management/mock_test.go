@@ -0,0 +1,52 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMockManager_SendCommand(t *testing.T) {
+	m := NewMockManager()
+	ctx := context.Background()
+	var seen []string
+	m.OnCommand = func(cmd string) { seen = append(seen, cmd) }
+
+	for _, cmd := range []string{"say hi", "stop"} {
+		if err := m.SendCommand(ctx, cmd); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !m.Sent("stop") || m.Sent("list") {
+		t.Fatalf("Sent() doesn't match %v", m.Commands())
+	}
+	if got := m.Take(); !slices.Equal(got, []string{"say hi", "stop"}) || !slices.Equal(seen, got) {
+		t.Fatalf("Take() = %v, OnCommand saw %v", got, seen)
+	}
+	if len(m.Commands()) != 0 {
+		t.Fatalf("expected no commands after Take, got %v", m.Commands())
+	}
+
+	m.SendErr = errors.New("no screen session")
+	if err := m.SendCommand(ctx, "say hi"); err == nil || len(m.Commands()) != 0 {
+		t.Fatalf("SendCommand() with SendErr = %v, recorded %v", err, m.Commands())
+	}
+}
+
+func TestMockManager_Query(t *testing.T) {
+	m := NewMockManager()
+	m.Replies["list"] = "There are 0 of a max of 20 players online:"
+	ctx := context.Background()
+
+	if reply, err := m.Query(ctx, "list"); err != nil || reply != m.Replies["list"] {
+		t.Fatalf("Query(list) = %q, %v", reply, err)
+	}
+	if _, err := m.Query(ctx, "tps"); err == nil {
+		t.Fatal("expected an error for a query without a reply")
+	}
+	m.QueryErr = ErrNoQuery
+	if _, err := m.Query(ctx, "tps"); !errors.Is(err, ErrNoQuery) {
+		t.Fatalf("Query(tps) error = %v, want QueryErr", err)
+	}
+}
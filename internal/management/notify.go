@@ -0,0 +1,45 @@
+package management
+
+import (
+	"context"
+	"time"
+)
+
+// Lifecycle events reported to a Notifier.
+const (
+	EventStart        = "start"
+	EventStop         = "stop"
+	EventBackup       = "backup"
+	EventBackupFailed = "backup_failed"
+)
+
+// Notifier is told about lifecycle events: the server being started or
+// stopped, and backups finishing or failing. Detail is a short human
+// description, such as the backup file or the error.
+type Notifier interface {
+	Notify(ctx context.Context, event, detail string)
+}
+
+// notifyTimeout bounds each lifecycle notification, so a webhook that is
+// down can't hold up starting, stopping or backing up the server.
+const notifyTimeout = 5 * time.Second
+
+type notifierKey struct{}
+
+// WithNotifier returns a context under which StartServer, StopServer,
+// RestartServer and Backup report to n. It is carried in the context so
+// that every caller — the CLI, the console, chat commands and the idle
+// service — reports without threading n through each of them.
+func WithNotifier(ctx context.Context, n Notifier) context.Context {
+	return context.WithValue(ctx, notifierKey{}, n)
+}
+
+// notify reports event to the context's Notifier, if it has one, giving
+// up after notifyTimeout.
+func notify(ctx context.Context, event, detail string) {
+	if n, ok := ctx.Value(notifierKey{}).(Notifier); ok && n != nil {
+		ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+		defer cancel()
+		n.Notify(ctx, event, detail)
+	}
+}
@@ -16,6 +16,14 @@ type ProcessStats struct {
 	PID    int
 	Memory string
 	CPU    string
+
+	// RSSBytes and CPUPercent are the raw values behind Memory and CPU,
+	// for callers that need numbers rather than display strings.
+	RSSBytes   int64
+	CPUPercent float64
+
+	// Uptime is how long the process has been running, or 0 if unknown.
+	Uptime time.Duration
 }
 
 // serverJarPatterns are the jar names used by supported Minecraft server types.
@@ -49,18 +57,52 @@ func GetProcessStats(ctx context.Context, runner platform.CommandRunner) (Proces
 		rssKB, err := strconv.Atoi(strings.TrimSpace(string(memOut)))
 		if err == nil {
 			stats.Memory = fmt.Sprintf("%d MB", rssKB/1024)
+			stats.RSSBytes = int64(rssKB) * 1024
 		}
 	}
 
 	// Get CPU %
 	cpuOut, err := runner.RunWithOutput(ctx, "ps", "-o", "%cpu=", "-p", pidStr)
 	if err == nil {
-		stats.CPU = strings.TrimSpace(string(cpuOut)) + "%"
+		cpu := strings.TrimSpace(string(cpuOut))
+		stats.CPU = cpu + "%"
+		stats.CPUPercent, _ = strconv.ParseFloat(cpu, 64)
+	}
+
+	// Elapsed time as [[dd-]hh:]mm:ss; macOS ps has no etimes.
+	etimeOut, err := runner.RunWithOutput(ctx, "ps", "-o", "etime=", "-p", pidStr)
+	if err == nil {
+		stats.Uptime, _ = parseEtime(strings.TrimSpace(string(etimeOut)))
 	}
 
 	return stats, nil
 }
 
+// parseEtime parses ps's elapsed time format, [[dd-]hh:]mm:ss.
+func parseEtime(s string) (time.Duration, error) {
+	var days int
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return 0, fmt.Errorf("invalid elapsed time: %q", s)
+		}
+		days, s = n, rest
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid elapsed time: %q", s)
+	}
+	var secs int
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, fmt.Errorf("invalid elapsed time: %q", s)
+		}
+		secs = secs*60 + n
+	}
+	return time.Duration(days)*24*time.Hour + time.Duration(secs)*time.Second, nil
+}
+
 // IsServerRunning checks whether a Minecraft server is running using the
 // manager's own detection, process detection, and port probing.
 func IsServerRunning(ctx context.Context, mgr ServerManager, runner platform.CommandRunner, port int) bool {
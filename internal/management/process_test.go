@@ -3,6 +3,7 @@ package management
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
 )
@@ -12,6 +13,7 @@ func TestGetProcessStats_Success(t *testing.T) {
 	mock.OutputMap["pgrep [-f server.jar]"] = []byte("12345\n")
 	mock.OutputMap["ps [-o rss= -p 12345]"] = []byte("524288\n")
 	mock.OutputMap["ps [-o %cpu= -p 12345]"] = []byte("15.3\n")
+	mock.OutputMap["ps [-o etime= -p 12345]"] = []byte("   02:03:04\n")
 
 	stats, err := GetProcessStats(context.Background(), mock)
 	if err != nil {
@@ -26,6 +28,36 @@ func TestGetProcessStats_Success(t *testing.T) {
 	if stats.CPU != "15.3%" {
 		t.Errorf("CPU = %q, want %q", stats.CPU, "15.3%")
 	}
+	if stats.RSSBytes != 524288*1024 {
+		t.Errorf("RSSBytes = %d, want %d", stats.RSSBytes, 524288*1024)
+	}
+	if stats.CPUPercent != 15.3 {
+		t.Errorf("CPUPercent = %v, want 15.3", stats.CPUPercent)
+	}
+	if want := 2*time.Hour + 3*time.Minute + 4*time.Second; stats.Uptime != want {
+		t.Errorf("Uptime = %v, want %v", stats.Uptime, want)
+	}
+}
+
+func TestParseEtime(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "00:42", want: 42 * time.Second},
+		{in: "12:34", want: 12*time.Minute + 34*time.Second},
+		{in: "01:02:03", want: time.Hour + 2*time.Minute + 3*time.Second},
+		{in: "3-04:05:06", want: 3*24*time.Hour + 4*time.Hour + 5*time.Minute + 6*time.Second},
+		{in: "42", wantErr: true},
+		{in: "x-01:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseEtime(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseEtime(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
 }
 
 func TestGetProcessStats_NotRunning(t *testing.T) {
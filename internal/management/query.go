@@ -0,0 +1,129 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// formattingCode matches Minecraft's section-sign formatting codes (e.g. "§a"),
+// which Paper embeds in command replies sent over RCON.
+var formattingCode = regexp.MustCompile(`§[0-9a-fk-orA-FK-OR]`)
+
+// StripFormatting removes Minecraft formatting codes from s.
+func StripFormatting(s string) string {
+	return formattingCode.ReplaceAllString(s, "")
+}
+
+// PlayerList is the parsed reply to the "list" console command.
+type PlayerList struct {
+	Online int
+	Max    int
+	Names  []string
+}
+
+// listCounts matches the online/max counts across server flavours:
+// "There are 2 of a max of 20 players online", "There are 2 out of maximum
+// 20 players online", and the older "There are 2/20 players online".
+var listCounts = regexp.MustCompile(`There are (\d+)\D+?(\d+) players? online`)
+
+// ParsePlayerList parses the reply to the "list" command.
+func ParsePlayerList(reply string) (PlayerList, error) {
+	reply = StripFormatting(reply)
+	m := listCounts.FindStringSubmatchIndex(reply)
+	if m == nil {
+		return PlayerList{}, fmt.Errorf("unrecognised list reply: %q", reply)
+	}
+	online, _ := strconv.Atoi(reply[m[2]:m[3]])
+	maxPlayers, _ := strconv.Atoi(reply[m[4]:m[5]])
+	pl := PlayerList{Online: online, Max: maxPlayers}
+
+	// Names follow the first colon after the counts, comma separated.
+	// Paper can prefix groups ("default: Steve, Alex"), so only the text
+	// after the last colon on each line is treated as names.
+	rest := reply[m[1]:]
+	for line := range strings.SplitSeq(rest, "\n") {
+		idx := strings.LastIndex(line, ":")
+		if idx < 0 {
+			continue
+		}
+		for name := range strings.SplitSeq(line[idx+1:], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				pl.Names = append(pl.Names, name)
+			}
+		}
+	}
+	return pl, nil
+}
+
+// ListPlayers asks the server for its online players.
+func ListPlayers(ctx context.Context, q Querier) (PlayerList, error) {
+	reply, err := q.Query(ctx, "list")
+	if err != nil {
+		return PlayerList{}, err
+	}
+	return ParsePlayerList(reply)
+}
+
+// decimal matches an unsigned decimal number, optionally prefixed with "*"
+// as Paper does when a value is capped ("*20.0").
+var decimal = regexp.MustCompile(`\*?(\d+(?:\.\d+)?)`)
+
+// ParseTPS parses Paper's "tps" reply ("TPS from last 1m, 5m, 15m: 20.0,
+// 19.98, 20.0") into the 1, 5, and 15 minute averages.
+func ParseTPS(reply string) ([]float64, error) {
+	reply = StripFormatting(reply)
+	_, values, ok := strings.Cut(reply, ":")
+	if !ok || !strings.Contains(reply, "TPS") {
+		return nil, fmt.Errorf("unrecognised tps reply: %q", reply)
+	}
+	tps := parseDecimals(values)
+	if len(tps) < 3 {
+		return nil, fmt.Errorf("unrecognised tps reply: %q", reply)
+	}
+	return tps[:3], nil
+}
+
+// MSPT holds tick-duration statistics in milliseconds for one window.
+type MSPT struct {
+	Window string
+	Avg    float64
+	Min    float64
+	Max    float64
+}
+
+// msptWindows are the windows Paper's "mspt" command reports, in order.
+var msptWindows = []string{"5s", "10s", "1m"}
+
+// ParseMSPT parses Paper's "mspt" reply, which lists avg/min/max tick times
+// for the last 5s, 10s, and 1m.
+func ParseMSPT(reply string) ([]MSPT, error) {
+	reply = StripFormatting(reply)
+	idx := strings.LastIndex(reply, ":")
+	if idx < 0 || !strings.Contains(reply, "tick times") {
+		return nil, fmt.Errorf("unrecognised mspt reply: %q", reply)
+	}
+	values := parseDecimals(reply[idx+1:])
+	if len(values) < 3*len(msptWindows) {
+		return nil, fmt.Errorf("unrecognised mspt reply: %q", reply)
+	}
+	out := make([]MSPT, 0, len(msptWindows))
+	for i, w := range msptWindows {
+		out = append(out, MSPT{Window: w, Avg: values[i*3], Min: values[i*3+1], Max: values[i*3+2]})
+	}
+	return out, nil
+}
+
+func parseDecimals(s string) []float64 {
+	var out []float64
+	for _, m := range decimal.FindAllStringSubmatch(s, -1) {
+		v, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
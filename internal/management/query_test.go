@@ -0,0 +1,92 @@
+package management
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePlayerList(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		wantOn    int
+		wantMax   int
+		wantNames []string
+	}{
+		{
+			name:      "vanilla with players",
+			reply:     "There are 2 of a max of 20 players online: Steve, .Bedrock_Kid",
+			wantOn:    2,
+			wantMax:   20,
+			wantNames: []string{"Steve", ".Bedrock_Kid"},
+		},
+		{
+			name:    "vanilla empty",
+			reply:   "There are 0 of a max of 20 players online: ",
+			wantOn:  0,
+			wantMax: 20,
+		},
+		{
+			name:      "paper grouped with formatting",
+			reply:     "§6There are §c1§6 out of maximum §c10§6 players online.\n§6default§r: Alex",
+			wantOn:    1,
+			wantMax:   10,
+			wantNames: []string{"Alex"},
+		},
+		{
+			name:    "legacy slash format",
+			reply:   "There are 0/20 players online:",
+			wantOn:  0,
+			wantMax: 20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePlayerList(tt.reply)
+			if err != nil {
+				t.Fatalf("ParsePlayerList() error = %v", err)
+			}
+			if got.Online != tt.wantOn || got.Max != tt.wantMax {
+				t.Errorf("counts = %d/%d, want %d/%d", got.Online, got.Max, tt.wantOn, tt.wantMax)
+			}
+			if !slices.Equal(got.Names, tt.wantNames) {
+				t.Errorf("names = %q, want %q", got.Names, tt.wantNames)
+			}
+		})
+	}
+
+	if _, err := ParsePlayerList("Unknown command"); err == nil {
+		t.Error("expected error for unrecognised reply")
+	}
+}
+
+func TestParseTPS(t *testing.T) {
+	got, err := ParseTPS("§6TPS from last 1m, 5m, 15m: §a*20.0, §a19.87, §a19.5")
+	if err != nil {
+		t.Fatalf("ParseTPS() error = %v", err)
+	}
+	if want := []float64{20.0, 19.87, 19.5}; !slices.Equal(got, want) {
+		t.Errorf("ParseTPS() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseTPS("Unknown or incomplete command, see below for error"); err == nil {
+		t.Error("expected error on a server without the tps command")
+	}
+}
+
+func TestParseMSPT(t *testing.T) {
+	reply := "§6Server tick times §e(§7avg§e/§7min§e/§7max§e)§6 from last 5s§7,§6 10s§7,§6 1m§e:\n" +
+		"§6◴ §a1.2§7/§a0.8§7/§a3.4§7, §a1.3§7/§a0.8§7/§a4.0§7, §a1.5§7/§a0.7§7/§a9.1"
+	got, err := ParseMSPT(reply)
+	if err != nil {
+		t.Fatalf("ParseMSPT() error = %v", err)
+	}
+	want := []MSPT{
+		{Window: "5s", Avg: 1.2, Min: 0.8, Max: 3.4},
+		{Window: "10s", Avg: 1.3, Min: 0.8, Max: 4.0},
+		{Window: "1m", Avg: 1.5, Min: 0.7, Max: 9.1},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseMSPT() = %+v, want %+v", got, want)
+	}
+}
@@ -5,80 +5,88 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/KevinTCoughlin/mc-dad-server/internal/mappool"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
 )
 
-// ParkourMaps is the default list of parkour map world folder names.
-var ParkourMaps = []string{
-	"parkour-spiral",
-	"parkour-spiral-3",
-	"parkour-volcano",
-	"parkour-pyramid",
-	"parkour-paradise",
-}
+// rotationStateFile holds the featured map's name. Older versions stored
+// its index in the map list, which is still understood.
+const rotationStateFile = "rotation-state.txt"
 
-// RotateParkour advances the featured parkour map, broadcasts, and teleports.
+// RotateParkour advances the featured map through the map pool, broadcasts,
+// and teleports. Maps that are missing or not imported are skipped.
 func RotateParkour(ctx context.Context, serverDir string, mgr ServerManager, output *ui.UI) error {
-	maps := ParkourMaps
+	maps, problems, err := mappool.Playable(serverDir)
+	if err != nil {
+		return err
+	}
+	for i := range problems {
+		output.Warn("Skipping %s: %s", problems[i].Name, problems[i].Problem())
+	}
 	if len(maps) == 0 {
-		output.Info("No parkour maps configured")
+		output.Info("No playable maps in the map pool")
 		return nil
 	}
 
-	stateFile := filepath.Join(serverDir, "rotation-state.txt")
+	stateFile := filepath.Join(serverDir, rotationStateFile)
 
-	// Read current index
-	currentIndex := 0
+	// Find the current map; start from the top if it has left the pool.
+	currentIndex := -1
 	if data, err := os.ReadFile(stateFile); err == nil {
-		if idx, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil {
+		state := strings.TrimSpace(string(data))
+		currentIndex = slices.IndexFunc(maps, func(m mappool.Map) bool { return m.Name == state })
+		if idx, err := strconv.Atoi(state); err == nil && currentIndex < 0 && idx >= 0 && idx < len(maps) {
 			currentIndex = idx
 		}
 	}
 
 	// Advance
 	nextIndex := (currentIndex + 1) % len(maps)
-	if err := os.WriteFile(stateFile, []byte(strconv.Itoa(nextIndex)+"\n"), 0o644); err != nil {
+	nextMap := maps[nextIndex]
+	if err := os.WriteFile(stateFile, []byte(nextMap.Name+"\n"), 0o644); err != nil {
 		return fmt.Errorf("writing rotation state: %w", err)
 	}
 
-	currentMap := maps[currentIndex]
-	nextMap := maps[nextIndex]
-
+	currentName := "(none)"
+	if currentIndex >= 0 {
+		currentName = maps[currentIndex].Name
+	}
 	output.Info("[%s] Rotating: %s -> %s",
-		time.Now().Format("2006-01-02 15:04:05"), currentMap, nextMap)
+		time.Now().Format("2006-01-02 15:04:05"), currentName, nextMap.Name)
 
 	// Broadcast
 	if err := mgr.SendCommand(ctx, fmt.Sprintf(
-		"say [PARKOUR] Featured map: %s! Type /mv tp %s to play!", nextMap, nextMap)); err != nil {
+		"say [PARKOUR] Featured map: %s! Type /mv tp %s to play!", nextMap.Label(), nextMap.World)); err != nil {
 		return err
 	}
 	_ = Sleep(ctx, 1)
 
 	// Teleport players
-	if err := mgr.SendCommand(ctx, fmt.Sprintf("mv tp * %s", nextMap)); err != nil {
+	if err := mgr.SendCommand(ctx, fmt.Sprintf("mv tp * %s", nextMap.World)); err != nil {
 		return err
 	}
 
-	output.Success("Rotation complete: %s", nextMap)
+	output.Success("Rotation complete: %s", nextMap.Name)
 	return nil
 }
 
-// RotateToMap broadcasts and teleports all players to the named map.
-func RotateToMap(ctx context.Context, mapName string, mgr ServerManager, output *ui.UI) error {
+// RotateToMap broadcasts and teleports all players to the map.
+func RotateToMap(ctx context.Context, m *mappool.Map, mgr ServerManager, output *ui.UI) error {
 	if err := mgr.SendCommand(ctx, fmt.Sprintf(
-		"say [PARKOUR] Loading map: %s!", mapName)); err != nil {
+		"say [PARKOUR] Loading map: %s!", m.Label())); err != nil {
 		return err
 	}
 	_ = Sleep(ctx, 1)
 
-	if err := mgr.SendCommand(ctx, fmt.Sprintf("mv tp * %s", mapName)); err != nil {
+	if err := mgr.SendCommand(ctx, fmt.Sprintf("mv tp * %s", m.World)); err != nil {
 		return err
 	}
 
-	output.Success("Teleported all players to %s", mapName)
+	output.Success("Teleported all players to %s", m.Label())
 	return nil
 }
@@ -0,0 +1,45 @@
+package management
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// Text is a Minecraft JSON text component.
+type Text struct {
+	Text  string `json:"text"`
+	Color string `json:"color,omitempty"`
+	Bold  bool   `json:"bold,omitempty"`
+}
+
+// consoleSafe replaces characters that would need escaping in JSON or that
+// screen's stuff command interprets: a "^M" would otherwise end the
+// tellraw and start a command of whoever wrote the text.
+var consoleSafe = strings.NewReplacer(`"`, "'", `\`, "/", "^", "ˆ")
+
+// Tellraw returns the tellraw command that shows parts to target, such as
+// "@a" or a player name. The components are marshalled as JSON with no
+// escapes at all, which screen would mangle: quotes, backslashes and
+// carets in the text are swapped for lookalikes and unprintable
+// characters for spaces. Any text, player-written or not, is safe.
+func Tellraw(target string, parts ...Text) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	msg := make([]any, 0, len(parts)+1)
+	msg = append(msg, "")
+	for _, p := range parts {
+		p.Text = consoleSafe.Replace(strings.Map(func(r rune) rune {
+			if !unicode.IsPrint(r) {
+				return ' '
+			}
+			return r
+		}, p.Text))
+		msg = append(msg, p)
+	}
+	// Strings, bools and slices of them always encode.
+	_ = enc.Encode(msg)
+	return "tellraw " + target + " " + strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,57 @@
+package management
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTellraw(t *testing.T) {
+	tests := []struct {
+		name  string
+		parts []Text
+		want  string
+	}{
+		{
+			name:  "plain",
+			parts: []Text{{Text: "Hi <Steve> & co", Color: "gold"}},
+			want:  `tellraw Steve ["",{"text":"Hi <Steve> & co","color":"gold"}]`,
+		},
+		{
+			name:  "bold and uncoloured",
+			parts: []Text{{Text: "[1] ", Color: "green", Bold: true}, {Text: "map"}},
+			want:  `tellraw Steve ["",{"text":"[1] ","color":"green","bold":true},{"text":"map"}]`,
+		},
+		{
+			name:  "emoji and quotes",
+			parts: []Text{{Text: `gg 🎉 "nice"`}},
+			want:  `tellraw Steve ["",{"text":"gg 🎉 'nice'"}]`,
+		},
+		{
+			name:  "screen escapes",
+			parts: []Text{{Text: `x^Mop Me^M \015`}},
+			want:  `tellraw Steve ["",{"text":"xˆMop MeˆM /015"}]`,
+		},
+		{
+			name:  "control characters",
+			parts: []Text{{Text: "bell\x07 tab\t line "}},
+			want:  `tellraw Steve ["",{"text":"bell  tab  line "}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Tellraw("Steve", tt.parts...)
+			if got != tt.want {
+				t.Errorf("Tellraw() = %s, want %s", got, tt.want)
+			}
+			// screen's stuff would act on any of these.
+			if strings.ContainsAny(got, `\^`) {
+				t.Errorf("Tellraw() = %s; screen would interpret it", got)
+			}
+			var msg []any
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(got, "tellraw Steve ")), &msg); err != nil {
+				t.Errorf("Tellraw() = %s: %v", got, err)
+			}
+		})
+	}
+}
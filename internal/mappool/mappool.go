@@ -0,0 +1,354 @@
+// Package mappool defines the maps that votes and rotation choose from. The
+// pool is the maps listed in maps.json in the server directory plus world
+// folders discovered by name pattern, so a map dropped into the server
+// directory can be voted on without editing any code.
+package mappool
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ConfigFile is the map pool configuration in the server directory.
+const ConfigFile = "maps.json"
+
+// DefaultPattern discovers the parkour maps installed by setup-parkour. It
+// is used when there is no config file.
+const DefaultPattern = "parkour-*"
+
+// multiverseWorlds is where Multiverse-Core records the worlds it has
+// imported.
+const multiverseWorlds = "plugins/Multiverse-Core/worlds.yml"
+
+// worldName restricts map and world names to what is safe to put in a
+// console command such as "mv tp * <world>".
+var worldName = regexp.MustCompile(`^[A-Za-z0-9_.+-]{1,64}$`)
+
+// Map is one map in the pool.
+type Map struct {
+	// Name identifies the map in commands and vote history.
+	Name string `json:"name"`
+	// DisplayName is shown to players; it defaults to Name.
+	DisplayName string `json:"display_name,omitempty"`
+	// World is the world folder and Multiverse world; it defaults to Name.
+	World string `json:"world,omitempty"`
+	// Weight makes a map proportionally more likely to be offered in a
+	// vote; it defaults to 1.
+	Weight int `json:"weight,omitempty"`
+	// Tags describe the map, such as its difficulty.
+	Tags []string `json:"tags,omitempty"`
+
+	// Discovered is set for maps found by folder pattern rather than
+	// listed in the config.
+	Discovered bool `json:"-"`
+}
+
+// Label returns the name to show players.
+func (m *Map) Label() string {
+	if m.DisplayName != "" {
+		return m.DisplayName
+	}
+	return m.Name
+}
+
+// withDefaults returns m with World and Weight filled in.
+func (m Map) withDefaults() Map { //nolint:gocritic // returns a modified copy
+	if m.World == "" {
+		m.World = m.Name
+	}
+	if m.Weight == 0 {
+		m.Weight = 1
+	}
+	return m
+}
+
+// validate checks the fields a user can get wrong.
+func (m *Map) validate() error {
+	if !worldName.MatchString(m.Name) {
+		return fmt.Errorf("map name %q: use letters, digits, '.', '_', '+' or '-'", m.Name)
+	}
+	if m.World != "" && !worldName.MatchString(m.World) {
+		return fmt.Errorf("map %q: world %q: use letters, digits, '.', '_', '+' or '-'", m.Name, m.World)
+	}
+	if m.Weight < 0 {
+		return fmt.Errorf("map %q: weight must not be negative", m.Name)
+	}
+	return nil
+}
+
+// Config is the contents of maps.json.
+type Config struct {
+	// Discover is a glob matched against folder names in the server
+	// directory; matching folders holding a level.dat join the pool.
+	// Empty disables discovery.
+	Discover string `json:"discover,omitempty"`
+	// Exclude lists discovered folders to leave out.
+	Exclude []string `json:"exclude,omitempty"`
+	Maps    []Map    `json:"maps,omitempty"`
+}
+
+// LoadConfig reads maps.json from serverDir. Without one, the pool is the
+// parkour maps found by DefaultPattern.
+func LoadConfig(serverDir string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, ConfigFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{Discover: DefaultPattern}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ConfigFile, err)
+	}
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ConfigFile, err)
+	}
+	if _, err := filepath.Match(c.Discover, ""); err != nil {
+		return nil, fmt.Errorf("%s: discover pattern %q: %w", ConfigFile, c.Discover, err)
+	}
+	for i := range c.Maps {
+		if err := c.Maps[i].validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", ConfigFile, err)
+		}
+	}
+	return &c, nil
+}
+
+// Save writes the config to maps.json in serverDir.
+func (c *Config) Save(serverDir string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", ConfigFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(serverDir, ConfigFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", ConfigFile, err)
+	}
+	return nil
+}
+
+// Add lists m in the config. A folder excluded from discovery under the
+// same name is included again.
+func (c *Config) Add(m Map) error { //nolint:gocritic // stored by value
+	if err := m.validate(); err != nil {
+		return err
+	}
+	if slices.ContainsFunc(c.Maps, func(o Map) bool { return o.Name == m.Name }) {
+		return fmt.Errorf("map %q is already in the pool", m.Name)
+	}
+	c.Maps = append(c.Maps, m)
+	c.Exclude = slices.DeleteFunc(c.Exclude, func(w string) bool { return w == m.withDefaults().World })
+	return nil
+}
+
+// Remove takes the named map out of the pool: it is unlisted, and a folder
+// of that name is excluded from discovery. It reports false if the map was
+// neither listed nor discoverable.
+func (c *Config) Remove(serverDir, name string) bool {
+	n := len(c.Maps)
+	c.Maps = slices.DeleteFunc(c.Maps, func(m Map) bool { return m.Name == name })
+	removed := len(c.Maps) < n
+
+	if slices.Contains(c.discover(serverDir), name) && !slices.Contains(c.Exclude, name) {
+		c.Exclude = append(c.Exclude, name)
+		removed = true
+	}
+	return removed
+}
+
+// Pool returns the listed maps, then discovered folders that no listed map
+// already uses, in name order.
+func (c *Config) Pool(serverDir string) []Map {
+	pool := make([]Map, 0, len(c.Maps))
+	worlds := make(map[string]bool)
+	for _, m := range c.Maps {
+		m = m.withDefaults()
+		worlds[m.World] = true
+		pool = append(pool, m)
+	}
+	for _, dir := range c.discover(serverDir) {
+		if worlds[dir] || slices.Contains(c.Exclude, dir) {
+			continue
+		}
+		pool = append(pool, Map{Name: dir, World: dir, Weight: 1, Discovered: true})
+	}
+	return pool
+}
+
+// discover returns the folders in serverDir matching the discover pattern
+// that hold a world.
+func (c *Config) discover(serverDir string) []string {
+	if c.Discover == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(serverDir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, e := range entries {
+		if !e.IsDir() || !worldName.MatchString(e.Name()) {
+			continue
+		}
+		if ok, _ := filepath.Match(c.Discover, e.Name()); !ok {
+			continue
+		}
+		if isWorld(filepath.Join(serverDir, e.Name())) {
+			dirs = append(dirs, e.Name())
+		}
+	}
+	return dirs
+}
+
+// Load returns the map pool for serverDir.
+func Load(serverDir string) ([]Map, error) {
+	c, err := LoadConfig(serverDir)
+	if err != nil {
+		return nil, err
+	}
+	return c.Pool(serverDir), nil
+}
+
+// ErrNoMap is returned by Find when no map matches.
+var ErrNoMap = errors.New("no map by that name")
+
+// Find looks a map up in pool by name or label, exactly or by unique
+// prefix, ignoring case.
+func Find(pool []Map, query string) (Map, error) {
+	var prefixed []Map
+	for _, m := range pool {
+		if strings.EqualFold(m.Name, query) || strings.EqualFold(m.Label(), query) {
+			return m, nil
+		}
+		if hasPrefixFold(m.Name, query) || hasPrefixFold(m.Label(), query) {
+			prefixed = append(prefixed, m)
+		}
+	}
+	switch len(prefixed) {
+	case 0:
+		return Map{}, fmt.Errorf("%w: %q", ErrNoMap, query)
+	case 1:
+		return prefixed[0], nil
+	}
+	names := make([]string, len(prefixed))
+	for i := range prefixed {
+		names[i] = prefixed[i].Name
+	}
+	return Map{}, fmt.Errorf("%q could be %s", query, strings.Join(names, ", "))
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// Status is a map and whether players can be sent to it.
+type Status struct {
+	Map
+	// Missing is set when the world folder has no level.dat.
+	Missing bool
+	// NotImported is set when Multiverse-Core has not imported the world.
+	NotImported bool
+}
+
+// OK reports whether the map is ready to play.
+func (s *Status) OK() bool {
+	return !s.Missing && !s.NotImported
+}
+
+// Problem describes what is wrong with the map, or "" if nothing is.
+func (s *Status) Problem() string {
+	switch {
+	case s.Missing:
+		return fmt.Sprintf("no world folder %s with a level.dat", s.World)
+	case s.NotImported:
+		return fmt.Sprintf("not imported in Multiverse (mv import %s normal)", s.World)
+	}
+	return ""
+}
+
+// Check validates each map in pool: its world folder must hold a level.dat
+// and, when Multiverse-Core has recorded its worlds, be imported.
+func Check(serverDir string, pool []Map) []Status {
+	imported, haveMV := MultiverseWorlds(serverDir)
+	out := make([]Status, 0, len(pool))
+	for _, m := range pool {
+		s := Status{Map: m}
+		s.Missing = !isWorld(filepath.Join(serverDir, m.World))
+		s.NotImported = !s.Missing && haveMV && !imported[m.World]
+		out = append(out, s)
+	}
+	return out
+}
+
+// Playable returns the maps in serverDir's pool that pass Check, and the
+// statuses of those that don't.
+func Playable(serverDir string) (playable []Map, problems []Status, err error) {
+	pool, err := Load(serverDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, s := range Check(serverDir, pool) {
+		if s.OK() {
+			playable = append(playable, s.Map)
+		} else {
+			problems = append(problems, s)
+		}
+	}
+	return playable, problems, nil
+}
+
+// MultiverseWorlds returns the worlds listed in Multiverse-Core's
+// worlds.yml. ok is false when there is no such file, as on a server
+// without Multiverse or one that hasn't started with it yet.
+//
+// Multiverse 4 nests worlds under a "worlds:" key; Multiverse 5 lists them
+// at the top level. Only the keys are needed, so the file is scanned by
+// indentation rather than parsed as YAML.
+func MultiverseWorlds(serverDir string) (worlds map[string]bool, ok bool) {
+	f, err := os.Open(filepath.Join(serverDir, multiverseWorlds))
+	if err != nil {
+		return nil, false
+	}
+	defer func() { _ = f.Close() }()
+
+	worlds = make(map[string]bool)
+	depth := 0 // indentation of world keys, once known
+	nested := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(trimmed)
+		key, _, isKey := strings.Cut(trimmed, ":")
+		if !isKey || strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+		key = strings.Trim(key, `'"`)
+
+		switch {
+		case indent == 0 && key == "worlds":
+			nested = true
+		case indent == 0 && !nested:
+			if key != "version" {
+				worlds[key] = true
+			}
+		case nested && indent > 0 && (depth == 0 || indent == depth):
+			depth = indent
+			worlds[key] = true
+		}
+	}
+	return worlds, true
+}
+
+// isWorld reports whether dir holds a Minecraft world.
+func isWorld(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "level.dat"))
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,301 @@
+package mappool
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// addWorld creates a world folder with a level.dat under dir.
+func addWorld(t *testing.T, dir, name string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name, "level.dat"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func names(pool []Map) []string {
+	out := make([]string, len(pool))
+	for i := range pool {
+		out[i] = pool[i].Name
+	}
+	return out
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string // "" for no file
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "missing file discovers parkour maps",
+			want: Config{Discover: DefaultPattern},
+		},
+		{
+			name:    "listed maps",
+			content: `{"maps": [{"name": "sky", "display_name": "Sky Block", "weight": 3}]}`,
+			want:    Config{Maps: []Map{{Name: "sky", DisplayName: "Sky Block", Weight: 3}}},
+		},
+		{name: "bad json", content: `{"maps": [`, wantErr: true},
+		{name: "unsafe name", content: `{"maps": [{"name": "sky; op me"}]}`, wantErr: true},
+		{name: "unsafe world", content: `{"maps": [{"name": "sky", "world": "../x y"}]}`, wantErr: true},
+		{name: "negative weight", content: `{"maps": [{"name": "sky", "weight": -1}]}`, wantErr: true},
+		{name: "bad pattern", content: `{"discover": "["}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.content != "" {
+				writeFile(t, filepath.Join(dir, ConfigFile), tt.content)
+			}
+			got, err := LoadConfig(dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("LoadConfig() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Discover != tt.want.Discover || len(got.Maps) != len(tt.want.Maps) {
+				t.Fatalf("LoadConfig() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got.Maps {
+				if got.Maps[i].Name != tt.want.Maps[i].Name || got.Maps[i].Weight != tt.want.Maps[i].Weight {
+					t.Errorf("Maps[%d] = %+v, want %+v", i, got.Maps[i], tt.want.Maps[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_Pool(t *testing.T) {
+	dir := t.TempDir()
+	addWorld(t, dir, "parkour-volcano")
+	addWorld(t, dir, "parkour-spiral")
+	addWorld(t, dir, "parkour-pyramid")
+	addWorld(t, dir, "world")
+	if err := os.Mkdir(filepath.Join(dir, "parkour-empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Config{
+		Discover: DefaultPattern,
+		Exclude:  []string{"parkour-pyramid"},
+		Maps: []Map{
+			{Name: "sky", DisplayName: "Sky Block"},
+			{Name: "volcano", World: "parkour-volcano", Weight: 2},
+		},
+	}
+	pool := c.Pool(dir)
+
+	want := []string{"sky", "volcano", "parkour-spiral"}
+	if got := names(pool); !slices.Equal(got, want) {
+		t.Fatalf("Pool() = %v, want %v", got, want)
+	}
+	if pool[0].World != "sky" || pool[0].Weight != 1 {
+		t.Errorf("defaults not applied: %+v", pool[0])
+	}
+	if pool[0].Label() != "Sky Block" || pool[2].Label() != "parkour-spiral" {
+		t.Errorf("labels = %q, %q", pool[0].Label(), pool[2].Label())
+	}
+	if pool[1].Discovered || !pool[2].Discovered {
+		t.Errorf("Discovered = %v, %v, want false, true", pool[1].Discovered, pool[2].Discovered)
+	}
+
+	c.Discover = ""
+	if got := names(c.Pool(dir)); !slices.Equal(got, []string{"sky", "volcano"}) {
+		t.Errorf("Pool() without discovery = %v", got)
+	}
+}
+
+func TestConfig_AddRemove(t *testing.T) {
+	dir := t.TempDir()
+	addWorld(t, dir, "parkour-spiral")
+
+	c, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Add(Map{Name: "sky"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Add(Map{Name: "sky"}); err == nil {
+		t.Error("adding a map twice succeeded")
+	}
+	if err := c.Add(Map{Name: "bad name"}); err == nil {
+		t.Error("adding an unsafe name succeeded")
+	}
+
+	// Removing a discovered map excludes its folder.
+	if !c.Remove(dir, "parkour-spiral") {
+		t.Fatal("Remove(parkour-spiral) = false")
+	}
+	if got := names(c.Pool(dir)); !slices.Equal(got, []string{"sky"}) {
+		t.Errorf("Pool() after remove = %v", got)
+	}
+	if c.Remove(dir, "parkour-spiral") {
+		t.Error("removing an excluded map again = true")
+	}
+	if c.Remove(dir, "nope") {
+		t.Error("Remove(nope) = true")
+	}
+
+	// Adding it back lifts the exclusion; saving and loading keeps it.
+	if err := c.Add(Map{Name: "parkour-spiral"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Exclude) != 0 {
+		t.Errorf("Exclude = %v after re-adding", c.Exclude)
+	}
+	if !c.Remove(dir, "sky") {
+		t.Error("Remove(sky) = false")
+	}
+	if err := c.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	pool, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(pool); !slices.Equal(got, []string{"parkour-spiral"}) {
+		t.Errorf("Load() = %v", got)
+	}
+}
+
+func TestMultiverseWorlds(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "multiverse 4",
+			content: `worlds:
+  world:
+    ==: MVWorld
+    alias: ''
+    spawnLocation:
+      world: world
+  parkour-spiral:
+    ==: MVWorld
+    alias: Spiral
+`,
+			want: []string{"parkour-spiral", "world"},
+		},
+		{
+			name: "multiverse 5",
+			content: `version: 1
+world:
+  alias: ''
+  entry-fee:
+    amount: 0.0
+'parkour-volcano':
+  alias: Volcano
+`,
+			want: []string{"parkour-volcano", "world"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, multiverseWorlds), tt.content)
+			worlds, ok := MultiverseWorlds(dir)
+			if !ok {
+				t.Fatal("MultiverseWorlds() ok = false")
+			}
+			got := make([]string, 0, len(worlds))
+			for w := range worlds {
+				got = append(got, w)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("MultiverseWorlds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := MultiverseWorlds(t.TempDir()); ok {
+		t.Error("MultiverseWorlds() without worlds.yml: ok = true")
+	}
+}
+
+func TestPlayable(t *testing.T) {
+	dir := t.TempDir()
+	addWorld(t, dir, "parkour-spiral")
+	addWorld(t, dir, "parkour-volcano")
+	writeFile(t, filepath.Join(dir, ConfigFile), `{"discover": "parkour-*", "maps": [{"name": "gone"}]}`)
+
+	// Without Multiverse's worlds.yml, only folders are checked.
+	playable, problems, err := Playable(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(playable); !slices.Equal(got, []string{"parkour-spiral", "parkour-volcano"}) {
+		t.Errorf("playable = %v", got)
+	}
+	if len(problems) != 1 || !problems[0].Missing || problems[0].Problem() == "" {
+		t.Errorf("problems = %+v, want gone missing", problems)
+	}
+
+	writeFile(t, filepath.Join(dir, multiverseWorlds), "worlds:\n  parkour-spiral:\n    alias: ''\n")
+	playable, problems, err = Playable(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(playable); !slices.Equal(got, []string{"parkour-spiral"}) {
+		t.Errorf("playable = %v", got)
+	}
+	if len(problems) != 2 || !problems[1].NotImported {
+		t.Errorf("problems = %+v, want gone missing and parkour-volcano not imported", problems)
+	}
+}
+
+func TestFind(t *testing.T) {
+	pool := []Map{
+		{Name: "parkour-spiral"},
+		{Name: "parkour-spiral-3"},
+		{Name: "parkour-volcano"},
+		{Name: "skyblock", DisplayName: "Sky Block"},
+	}
+	tests := []struct {
+		query string
+		want  string // "" for an error
+	}{
+		{"parkour-spiral", "parkour-spiral"}, // exact beats prefix
+		{"PARKOUR-VOLCANO", "parkour-volcano"},
+		{"parkour-v", "parkour-volcano"},
+		{"sky b", "skyblock"},
+		{"parkour-s", ""}, // ambiguous
+		{"lava", ""},
+	}
+	for _, tt := range tests {
+		m, err := Find(pool, tt.query)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Find(%q) = %s, want error", tt.query, m.Name)
+			}
+			continue
+		}
+		if err != nil || m.Name != tt.want {
+			t.Errorf("Find(%q) = %s, %v; want %s", tt.query, m.Name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Package mcproto implements the small slice of the Minecraft Java Edition
+// network protocol needed to talk to clients before they join: the
+// handshake, Server List Ping, and a login-stage disconnect.
+package mcproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Handshake next-state values.
+const (
+	StateStatus   = 1
+	StateLogin    = 2
+	StateTransfer = 3
+)
+
+// Packet IDs used before the play state.
+const (
+	PacketHandshake       = 0x00
+	PacketStatusRequest   = 0x00
+	PacketStatusResponse  = 0x00
+	PacketPing            = 0x01
+	PacketPong            = 0x01
+	PacketLoginStart      = 0x00
+	PacketLoginDisconnect = 0x00
+)
+
+// LegacyPingByte is the first byte sent by pre-1.7 clients' Server List Ping,
+// which uses a different framing that this package does not speak.
+const LegacyPingByte = 0xFE
+
+// maxPacketSize bounds packets read from unauthenticated clients. Nothing
+// exchanged before login comes close to this.
+const maxPacketSize = 32 * 1024
+
+// maxVarIntBytes is the longest encoding of a 32-bit VarInt.
+const maxVarIntBytes = 5
+
+// ErrVarIntTooLong reports a VarInt that runs past five bytes.
+var ErrVarIntTooLong = errors.New("varint too long")
+
+// ReadVarInt reads a protocol VarInt.
+func ReadVarInt(r io.ByteReader) (int32, error) {
+	var value uint32
+	for i := range maxVarIntBytes {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint32(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(value), nil
+		}
+	}
+	return 0, ErrVarIntTooLong
+}
+
+// AppendVarInt appends the VarInt encoding of v to b.
+func AppendVarInt(b []byte, v int32) []byte {
+	u := uint32(v)
+	for u >= 0x80 {
+		b = append(b, byte(u)|0x80)
+		u >>= 7
+	}
+	return append(b, byte(u))
+}
+
+// ReadString reads a VarInt-length-prefixed UTF-8 string.
+func ReadString(r *bytes.Reader) (string, error) {
+	n, err := ReadVarInt(r)
+	if err != nil {
+		return "", err
+	}
+	if n < 0 || int(n) > r.Len() {
+		return "", fmt.Errorf("string length %d out of range", n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// AppendString appends s with its VarInt length prefix.
+func AppendString(b []byte, s string) []byte {
+	b = AppendVarInt(b, int32(len(s)))
+	return append(b, s...)
+}
+
+// ReadPacket reads one uncompressed packet and returns its ID and payload.
+func ReadPacket(r *bufio.Reader) (id int32, payload []byte, err error) {
+	length, err := ReadVarInt(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if length <= 0 || length > maxPacketSize {
+		return 0, nil, fmt.Errorf("packet length %d out of range", length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+	br := bytes.NewReader(data)
+	id, err = ReadVarInt(br)
+	if err != nil {
+		return 0, nil, err
+	}
+	return id, data[len(data)-br.Len():], nil
+}
+
+// WritePacket writes one uncompressed packet.
+func WritePacket(w io.Writer, id int32, payload []byte) error {
+	body := AppendVarInt(nil, id)
+	body = append(body, payload...)
+	frame := AppendVarInt(make([]byte, 0, len(body)+maxVarIntBytes), int32(len(body)))
+	frame = append(frame, body...)
+	_, err := w.Write(frame)
+	return err
+}
+
+// Handshake is the first packet a client sends on a new connection.
+type Handshake struct {
+	Protocol  int32
+	Address   string
+	Port      uint16
+	NextState int32
+}
+
+// ParseHandshake decodes a handshake packet payload.
+func ParseHandshake(payload []byte) (Handshake, error) {
+	r := bytes.NewReader(payload)
+	var hs Handshake
+	var err error
+	if hs.Protocol, err = ReadVarInt(r); err != nil {
+		return hs, fmt.Errorf("handshake protocol: %w", err)
+	}
+	if hs.Address, err = ReadString(r); err != nil {
+		return hs, fmt.Errorf("handshake address: %w", err)
+	}
+	if err := binary.Read(r, binary.BigEndian, &hs.Port); err != nil {
+		return hs, fmt.Errorf("handshake port: %w", err)
+	}
+	if hs.NextState, err = ReadVarInt(r); err != nil {
+		return hs, fmt.Errorf("handshake next state: %w", err)
+	}
+	return hs, nil
+}
+
+// AppendHandshake encodes hs as a handshake packet payload.
+func AppendHandshake(b []byte, hs Handshake) []byte {
+	b = AppendVarInt(b, hs.Protocol)
+	b = AppendString(b, hs.Address)
+	b = binary.BigEndian.AppendUint16(b, hs.Port)
+	return AppendVarInt(b, hs.NextState)
+}
+
+// Status is the JSON body of a Server List Ping response.
+type Status struct {
+	Version     StatusVersion `json:"version"`
+	Players     StatusPlayers `json:"players"`
+	Description Text          `json:"description"`
+}
+
+// StatusVersion names the server version shown in the server list.
+type StatusVersion struct {
+	Name     string `json:"name"`
+	Protocol int32  `json:"protocol"`
+}
+
+// StatusPlayers is the player count shown in the server list.
+type StatusPlayers struct {
+	Max    int `json:"max"`
+	Online int `json:"online"`
+}
+
+// Text is a minimal JSON text component.
+type Text struct {
+	Text  string `json:"text"`
+	Color string `json:"color,omitempty"`
+}
+
+// WriteStatus sends a status response carrying st.
+func WriteStatus(w io.Writer, st *Status) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return WritePacket(w, PacketStatusResponse, AppendString(nil, string(data)))
+}
+
+// WriteLoginDisconnect sends a login-state disconnect showing reason.
+func WriteLoginDisconnect(w io.Writer, reason Text) error {
+	data, err := json.Marshal(reason)
+	if err != nil {
+		return err
+	}
+	return WritePacket(w, PacketLoginDisconnect, AppendString(nil, string(data)))
+}
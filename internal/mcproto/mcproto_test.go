@@ -0,0 +1,92 @@
+package mcproto
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestVarIntRoundTrip(t *testing.T) {
+	tests := []struct {
+		value int32
+		bytes []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x80, 0x01}},
+		{25565, []byte{0xdd, 0xc7, 0x01}},
+		{2147483647, []byte{0xff, 0xff, 0xff, 0xff, 0x07}},
+		{-1, []byte{0xff, 0xff, 0xff, 0xff, 0x0f}},
+	}
+	for _, tt := range tests {
+		got := AppendVarInt(nil, tt.value)
+		if !bytes.Equal(got, tt.bytes) {
+			t.Errorf("AppendVarInt(%d) = %x, want %x", tt.value, got, tt.bytes)
+		}
+		v, err := ReadVarInt(bytes.NewReader(tt.bytes))
+		if err != nil || v != tt.value {
+			t.Errorf("ReadVarInt(%x) = %d, %v; want %d", tt.bytes, v, err, tt.value)
+		}
+	}
+}
+
+func TestReadVarIntTooLong(t *testing.T) {
+	_, err := ReadVarInt(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x01}))
+	if !errors.Is(err, ErrVarIntTooLong) {
+		t.Fatalf("err = %v, want ErrVarIntTooLong", err)
+	}
+}
+
+func TestHandshakeRoundTrip(t *testing.T) {
+	want := Handshake{Protocol: 767, Address: "mc.example.com", Port: 25565, NextState: StateLogin}
+
+	var buf bytes.Buffer
+	if err := WritePacket(&buf, PacketHandshake, AppendHandshake(nil, want)); err != nil {
+		t.Fatal(err)
+	}
+
+	id, payload, err := ReadPacket(bufio.NewReader(&buf))
+	if err != nil {
+		t.Fatalf("ReadPacket() error = %v", err)
+	}
+	if id != PacketHandshake {
+		t.Fatalf("id = %d, want %d", id, PacketHandshake)
+	}
+	got, err := ParseHandshake(payload)
+	if err != nil {
+		t.Fatalf("ParseHandshake() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("ParseHandshake() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadPacketRejectsOversizedLength(t *testing.T) {
+	frame := AppendVarInt(nil, maxPacketSize+1)
+	if _, _, err := ReadPacket(bufio.NewReader(bytes.NewReader(frame))); err == nil {
+		t.Fatal("expected an error for an oversized packet")
+	}
+}
+
+func TestWriteLoginDisconnect(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteLoginDisconnect(&buf, Text{Text: "bye"}); err != nil {
+		t.Fatal(err)
+	}
+	id, payload, err := ReadPacket(bufio.NewReader(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != PacketLoginDisconnect {
+		t.Fatalf("id = %d, want %d", id, PacketLoginDisconnect)
+	}
+	reason, err := ReadString(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason != `{"text":"bye"}` {
+		t.Errorf("reason = %s", reason)
+	}
+}
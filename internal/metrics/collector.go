@@ -0,0 +1,214 @@
+// Package metrics exposes Minecraft server health as Prometheus/OpenMetrics
+// text: process or container resource usage, players and tick rate over
+// RCON, disk usage, backup freshness, and vote activity.
+package metrics
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/vote"
+)
+
+// Collector gathers a Snapshot on demand. It is safe for concurrent use as
+// long as the manager is.
+type Collector struct {
+	mgr       management.ServerManager
+	runner    platform.CommandRunner
+	serverDir string
+	port      int
+
+	rconErrors atomic.Uint64
+}
+
+// NewCollector returns a Collector for the server in serverDir managed by mgr.
+func NewCollector(mgr management.ServerManager, runner platform.CommandRunner, serverDir string, port int) *Collector {
+	return &Collector{mgr: mgr, runner: runner, serverDir: serverDir, port: port}
+}
+
+// Snapshot is one scrape's worth of measurements. Pointer and slice fields
+// are nil when the value could not be obtained, so the exporter can omit the
+// series rather than report a misleading zero.
+type Snapshot struct {
+	Up bool
+
+	CPUPercent  *float64
+	MemoryBytes *int64
+
+	Players *management.PlayerList
+	TPS     []float64
+	MSPT    []management.MSPT
+
+	WorldBytes  map[string]int64
+	BackupBytes int64
+	LastBackup  *management.BackupStatus
+
+	Votes   int
+	Ballots int
+
+	RCONErrors uint64
+	Time       time.Time
+}
+
+// Collect takes a snapshot of the server.
+func (c *Collector) Collect(ctx context.Context) *Snapshot {
+	s := &Snapshot{Time: time.Now()}
+	s.Up = management.IsServerRunning(ctx, c.mgr, c.runner, c.port)
+
+	c.collectResources(ctx, s)
+	if s.Up {
+		c.collectRCON(ctx, s)
+	}
+
+	s.WorldBytes = make(map[string]int64)
+	for _, w := range management.FindWorldDirs(c.serverDir) {
+		s.WorldBytes[w] = dirSize(filepath.Join(c.serverDir, w))
+	}
+	s.BackupBytes = dirSize(filepath.Join(c.serverDir, "backups"))
+	s.LastBackup, _ = management.LoadBackupStatus(c.serverDir)
+
+	if records, err := vote.LoadHistory(c.serverDir); err == nil {
+		s.Votes = len(records)
+		for i := range records {
+			s.Ballots += records[i].Voters
+		}
+	}
+
+	s.RCONErrors = c.rconErrors.Load()
+	return s
+}
+
+// collectResources reads CPU and memory from the container runtime when the
+// manager supports it, falling back to the host process table.
+func (c *Collector) collectResources(ctx context.Context, s *Snapshot) {
+	if hc, ok := c.mgr.(management.HealthChecker); ok {
+		if !c.mgr.IsRunning(ctx) {
+			return
+		}
+		out, err := hc.Stats(ctx)
+		if err != nil {
+			return
+		}
+		cpu, mem, ok := parseContainerStats(out)
+		if ok {
+			s.CPUPercent, s.MemoryBytes = &cpu, &mem
+		}
+		return
+	}
+
+	stats, err := management.GetProcessStats(ctx, c.runner)
+	if err != nil || stats.PID == 0 {
+		return
+	}
+	s.CPUPercent = &stats.CPUPercent
+	s.MemoryBytes = &stats.RSSBytes
+}
+
+// collectRCON queries players and tick statistics. Transport failures count
+// as RCON errors; replies that don't parse (e.g. "tps" on vanilla) do not.
+func (c *Collector) collectRCON(ctx context.Context, s *Snapshot) {
+	q, ok := c.mgr.(management.Querier)
+	if !ok {
+		return
+	}
+
+	if reply, err := q.Query(ctx, "list"); err != nil {
+		c.rconErrors.Add(1)
+		// The connection is unusable; don't pile up more failures.
+		return
+	} else if pl, err := management.ParsePlayerList(reply); err == nil {
+		s.Players = &pl
+	}
+
+	if reply, err := q.Query(ctx, "tps"); err != nil {
+		c.rconErrors.Add(1)
+	} else if tps, err := management.ParseTPS(reply); err == nil {
+		s.TPS = tps
+	}
+
+	if reply, err := q.Query(ctx, "mspt"); err != nil {
+		c.rconErrors.Add(1)
+	} else if mspt, err := management.ParseMSPT(reply); err == nil {
+		s.MSPT = mspt
+	}
+}
+
+// parseContainerStats parses the "CPU: 1.23%  MEM: 512MiB / 4GiB" string
+// produced by container.Manager.Stats.
+func parseContainerStats(s string) (cpuPercent float64, memBytes int64, ok bool) {
+	_, cpuPart, found := strings.Cut(s, "CPU:")
+	if !found {
+		return 0, 0, false
+	}
+	cpuPart, memPart, found := strings.Cut(cpuPart, "MEM:")
+	if !found {
+		return 0, 0, false
+	}
+	cpu, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(cpuPart), "%"), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	used, _, _ := strings.Cut(memPart, "/")
+	mem, ok := parseByteSize(strings.TrimSpace(used))
+	if !ok {
+		return 0, 0, false
+	}
+	return cpu, mem, true
+}
+
+// byteUnits maps the unit suffixes used by podman and docker to multipliers.
+var byteUnits = map[string]float64{
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// parseByteSize parses a human-readable size such as "512.3MiB" or "1.2GB".
+func parseByteSize(s string) (int64, bool) {
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i <= 0 {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, false
+	}
+	mult, ok := byteUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok {
+		return 0, false
+	}
+	return int64(value * mult), true
+}
+
+// dirSize returns the total size of the regular files under dir, or 0 if it
+// does not exist.
+func dirSize(dir string) int64 {
+	var total int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+)
+
+func TestParseContainerStats(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantCPU float64
+		wantMem int64
+		wantOk  bool
+	}{
+		{in: "CPU: 12.50%  MEM: 512MiB / 4GiB", wantCPU: 12.5, wantMem: 512 << 20, wantOk: true},
+		{in: "CPU: 0.31%  MEM: 1.5GB / 4.1GB", wantCPU: 0.31, wantMem: 1_500_000_000, wantOk: true},
+		{in: "CPU: --  MEM: -- / --", wantOk: false},
+		{in: "", wantOk: false},
+	}
+	for _, tt := range tests {
+		cpu, mem, ok := parseContainerStats(tt.in)
+		if ok != tt.wantOk {
+			t.Errorf("parseContainerStats(%q) ok = %v, want %v", tt.in, ok, tt.wantOk)
+			continue
+		}
+		if ok && (cpu != tt.wantCPU || mem != tt.wantMem) {
+			t.Errorf("parseContainerStats(%q) = %v, %d; want %v, %d", tt.in, cpu, mem, tt.wantCPU, tt.wantMem)
+		}
+	}
+}
+
+func TestCollectAndWrite(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "world"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "world", "level.dat"), make([]byte, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := platform.NewMockRunner()
+	runner.OutputMap["pgrep [-f server.jar]"] = []byte("42\n")
+	runner.OutputMap["ps [-o rss= -p 42]"] = []byte("1024\n")
+	runner.OutputMap["ps [-o %cpu= -p 42]"] = []byte("7.5\n")
+
+	mgr := management.NewMockManager()
+	mgr.Replies = map[string]string{
+		"list": "There are 1 of a max of 20 players online: Steve",
+		"tps":  "§6TPS from last 1m, 5m, 15m: §a20.0, §a19.9, §a19.8",
+		// "mspt" is missing, which counts as a failed RCON query.
+	}
+
+	c := NewCollector(mgr, runner, dir, 25565)
+	s := c.Collect(context.Background())
+
+	var b strings.Builder
+	if err := Write(&b, s); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE minecraft_up gauge\n",
+		"minecraft_up 1\n",
+		"minecraft_cpu_percent 7.5\n",
+		"minecraft_memory_bytes 1048576\n",
+		"minecraft_players_online 1\n",
+		"minecraft_players_max 20\n",
+		`minecraft_tps{window="5m"} 19.9` + "\n",
+		`minecraft_world_size_bytes{world="world"} 100` + "\n",
+		"# TYPE minecraft_rcon_errors counter\n",
+		"minecraft_rcon_errors_total 1\n",
+		"minecraft_votes_total 0\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "minecraft_mspt_milliseconds") {
+		t.Error("mspt series should be omitted when the query failed")
+	}
+	if strings.Contains(out, "minecraft_last_backup_success") {
+		t.Error("backup series should be omitted before any backup")
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Error("output must end with # EOF")
+	}
+}
+
+func TestWriteBackupAge(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	s := &Snapshot{
+		Time:       now,
+		LastBackup: &management.BackupStatus{Time: now.Add(-90 * time.Second), Success: true},
+	}
+	var b strings.Builder
+	if err := Write(&b, s); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"minecraft_last_backup_age_seconds 90\n",
+		"minecraft_last_backup_success 1\n",
+		"minecraft_last_backup_timestamp_seconds 999910\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("output missing %q\n%s", want, b.String())
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	c := NewCollector(management.NewMockManager(), platform.NewMockRunner(), t.TempDir(), 25565)
+	srv := httptest.NewServer(Handler(c))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/metrics", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:9225": true,
+		"localhost:9225": true,
+		"[::1]:9225":     true,
+		"0.0.0.0:9225":   false,
+		":9225":          false,
+		"bogus":          false,
+	}
+	for addr, want := range tests {
+		if got := IsLoopback(addr); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
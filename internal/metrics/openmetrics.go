@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of the exposition written by Write.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// namespace prefixes every exported metric name.
+const namespace = "minecraft_"
+
+// labelEscaper escapes label values as the OpenMetrics text format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// sample is one labelled value of a metric family.
+type sample struct {
+	labels [][2]string
+	value  float64
+}
+
+// family accumulates samples for one metric and renders them.
+type family struct {
+	name    string
+	typ     string
+	help    string
+	samples []sample
+}
+
+func (f *family) add(value float64, labels ...[2]string) {
+	f.samples = append(f.samples, sample{labels: labels, value: value})
+}
+
+func label(name, value string) [2]string { return [2]string{name, value} }
+
+// Write renders s in the OpenMetrics text format, terminated by "# EOF".
+func Write(w io.Writer, s *Snapshot) error {
+	var b strings.Builder
+	for _, f := range families(s) {
+		if len(f.samples) == 0 {
+			continue
+		}
+		writeFamily(&b, f)
+	}
+	b.WriteString("# EOF\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// families turns a snapshot into metric families in a stable order.
+func families(s *Snapshot) []*family {
+	up := &family{name: "up", typ: "gauge", help: "Whether the Minecraft server is running (1) or not (0)."}
+	up.add(boolValue(s.Up))
+
+	cpu := &family{name: "cpu_percent", typ: "gauge", help: "CPU usage of the server process or container, in percent of one core."}
+	if s.CPUPercent != nil {
+		cpu.add(*s.CPUPercent)
+	}
+	mem := &family{name: "memory_bytes", typ: "gauge", help: "Resident memory of the server process or container."}
+	if s.MemoryBytes != nil {
+		mem.add(float64(*s.MemoryBytes))
+	}
+
+	online := &family{name: "players_online", typ: "gauge", help: "Players currently online."}
+	maxPlayers := &family{name: "players_max", typ: "gauge", help: "Maximum player slots."}
+	if s.Players != nil {
+		online.add(float64(s.Players.Online))
+		maxPlayers.add(float64(s.Players.Max))
+	}
+
+	tps := &family{name: "tps", typ: "gauge", help: "Ticks per second averaged over the window (Paper only)."}
+	for i, window := range []string{"1m", "5m", "15m"} {
+		if i < len(s.TPS) {
+			tps.add(s.TPS[i], label("window", window))
+		}
+	}
+
+	mspt := &family{name: "mspt_milliseconds", typ: "gauge", help: "Milliseconds per tick over the window (Paper only)."}
+	for _, m := range s.MSPT {
+		mspt.add(m.Avg, label("window", m.Window), label("stat", "avg"))
+		mspt.add(m.Min, label("window", m.Window), label("stat", "min"))
+		mspt.add(m.Max, label("window", m.Window), label("stat", "max"))
+	}
+
+	world := &family{name: "world_size_bytes", typ: "gauge", help: "Disk space used by each world directory."}
+	for _, name := range slices.Sorted(maps.Keys(s.WorldBytes)) {
+		world.add(float64(s.WorldBytes[name]), label("world", name))
+	}
+	backups := &family{name: "backups_size_bytes", typ: "gauge", help: "Disk space used by backup archives."}
+	backups.add(float64(s.BackupBytes))
+
+	backupTime := &family{name: "last_backup_timestamp_seconds", typ: "gauge", help: "Unix time of the last backup attempt."}
+	backupAge := &family{name: "last_backup_age_seconds", typ: "gauge", help: "Seconds since the last backup attempt."}
+	backupOK := &family{name: "last_backup_success", typ: "gauge", help: "Whether the last backup attempt succeeded (1) or failed (0)."}
+	if s.LastBackup != nil {
+		backupTime.add(float64(s.LastBackup.Time.Unix()))
+		backupAge.add(s.Time.Sub(s.LastBackup.Time).Seconds())
+		backupOK.add(boolValue(s.LastBackup.Success))
+	}
+
+	votes := &family{name: "votes", typ: "counter", help: "Completed map votes."}
+	votes.add(float64(s.Votes))
+	ballots := &family{name: "vote_ballots", typ: "counter", help: "Ballots cast across all map votes."}
+	ballots.add(float64(s.Ballots))
+	rconErrors := &family{name: "rcon_errors", typ: "counter", help: "RCON queries that failed since the exporter started."}
+	rconErrors.add(float64(s.RCONErrors))
+
+	return []*family{
+		up, cpu, mem, online, maxPlayers, tps, mspt,
+		world, backups, backupTime, backupAge, backupOK,
+		votes, ballots, rconErrors,
+	}
+}
+
+func writeFamily(b *strings.Builder, f *family) {
+	name := namespace + f.name
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, f.typ)
+	fmt.Fprintf(b, "# HELP %s %s\n", name, f.help)
+
+	sampleName := name
+	if f.typ == "counter" {
+		sampleName += "_total"
+	}
+	for _, s := range f.samples {
+		b.WriteString(sampleName)
+		if len(s.labels) > 0 {
+			b.WriteByte('{')
+			for i, l := range s.labels {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				fmt.Fprintf(b, "%s=\"%s\"", l[0], labelEscaper.Replace(l[1]))
+			}
+			b.WriteByte('}')
+		}
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatFloat(s.value, 'f', -1, 64))
+		b.WriteByte('\n')
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/players"
+)
+
+// pardonInterval is how often expired bans are lifted.
+const pardonInterval = 30 * time.Second
+
+// BansHeartbeat is touched by RunBans on every pass and removed when it
+// stops, so the ban command can tell whether anything will lift a ban.
+const BansHeartbeat = "timed-bans.heartbeat"
+
+// LiftingBans reports whether RunBans is running for serverDir: its
+// heartbeat was touched within the last few passes at now.
+func LiftingBans(serverDir string, now time.Time) bool {
+	info, err := os.Stat(filepath.Join(serverDir, BansHeartbeat))
+	return err == nil && now.Sub(info.ModTime()) < 3*pardonInterval
+}
+
+// DefaultBanReason is what a banned player is told without a reason, as
+// the server itself says.
+const DefaultBanReason = "Banned by an operator."
+
+// Banner bans players. While the server runs, bans go through its ban
+// command; while it is stopped, they are written to banned-players.json,
+// with their expiry so the server lets the player back in by itself. Timed
+// bans are also kept in the ledger for RunBans to lift.
+type Banner struct {
+	ServerDir string
+	Manager   management.ServerManager
+	// Running is whether the server is up to take commands.
+	Running  bool
+	Resolver *players.Resolver
+}
+
+// Ban bans player at now for d, or for good if d is zero, and returns
+// when the ban ends. An empty reason gives DefaultBanReason.
+func (b *Banner) Ban(ctx context.Context, player, reason string, d time.Duration, now time.Time) (time.Time, error) {
+	if err := b.Resolver.Check(player); err != nil {
+		return time.Time{}, err
+	}
+	// The reason goes to the console, where screen would act on these.
+	if strings.ContainsAny(reason, "\\^\r\n") {
+		return time.Time{}, errors.New(`a ban reason can't contain \, ^ or line breaks`)
+	}
+	if reason == "" {
+		reason = DefaultBanReason
+	}
+	var until time.Time
+	if d > 0 {
+		until = now.Add(d)
+	}
+
+	if b.Running {
+		if err := b.Manager.SendCommand(ctx, "ban "+player+" "+reason); err != nil {
+			return time.Time{}, fmt.Errorf("banning %s: %w", player, err)
+		}
+	} else {
+		entry, err := b.Resolver.Resolve(ctx, player)
+		if err != nil {
+			return time.Time{}, err
+		}
+		banned, err := players.LoadBannedPlayers(b.ServerDir)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if err := banned.Put(players.NewBan(entry, reason, now, until)).Save(b.ServerDir); err != nil {
+			return time.Time{}, err
+		}
+		player = entry.Name
+	}
+
+	key := strings.ToLower(player)
+	detail := "forever"
+	if d > 0 {
+		detail = FormatDuration(d)
+	}
+	err := UpdateLedger(b.ServerDir, func(ledger *Ledger) error {
+		if d > 0 {
+			ledger.Bans[key] = Ban{Player: player, Reason: reason, Until: until}
+		} else {
+			// A ban for good outlasts any timed one.
+			delete(ledger.Bans, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return until, AppendLog(b.ServerDir, &Entry{Time: now, Player: player, Action: ActionBan, Message: reason, Detail: detail})
+}
+
+// RunBans lifts the timed bans in the ledger as they run out, until ctx is
+// cancelled. Bans that ran out while the daemon or server was down are
+// lifted as soon as the server takes commands.
+func RunBans(ctx context.Context, cfg *Config) error {
+	if cfg.Manager == nil || cfg.Output == nil || cfg.ServerDir == "" {
+		return errors.New("timed bans need a manager, output and server directory")
+	}
+	heartbeat := filepath.Join(cfg.ServerDir, BansHeartbeat)
+	defer func() { _ = os.Remove(heartbeat) }()
+	pass := func() {
+		err := os.WriteFile(heartbeat, nil, 0o644)
+		if err == nil {
+			err = pardon(ctx, cfg, time.Now())
+		}
+		if err != nil {
+			cfg.Output.Warn("Timed bans: %s", err)
+		}
+	}
+	ticker := time.NewTicker(pardonInterval)
+	defer ticker.Stop()
+	pass()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			pass()
+		}
+	}
+}
+
+// pardon lifts the bans that have run out. A ban the server can't be told
+// to lift yet stays in the ledger for the next try. The server is told
+// without holding the ledger's lock, so a ban is only dropped afterwards
+// if no one has changed it meanwhile.
+func pardon(ctx context.Context, cfg *Config, now time.Time) error {
+	dir := cfg.ServerDir
+	ledger, err := LoadLedger(dir)
+	if err != nil {
+		return err
+	}
+	lifted := make(map[string]Ban)
+	for key, b := range ledger.Bans {
+		if now.Before(b.Until) {
+			continue
+		}
+		if err := cfg.Manager.SendCommand(ctx, "pardon "+b.Player); err != nil {
+			continue
+		}
+		lifted[key] = b
+	}
+	if len(lifted) == 0 {
+		return nil
+	}
+	err = UpdateLedger(dir, func(l *Ledger) error {
+		for key, b := range lifted {
+			if cur, ok := l.Bans[key]; ok && cur.Until.Equal(b.Until) {
+				delete(l.Bans, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, b := range lifted {
+		cfg.Output.Info("Lifted %s's ban", b.Player)
+		if err := AppendLog(dir, &Entry{Time: now, Player: b.Player, Action: ActionPardon}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
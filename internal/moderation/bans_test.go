@@ -0,0 +1,180 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/players"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+func TestBanner(t *testing.T) {
+	dir := t.TempDir()
+	mgr := management.NewMockManager()
+	b := &Banner{ServerDir: dir, Manager: mgr, Running: true, Resolver: &players.Resolver{ServerDir: dir, Offline: true}}
+	now := time.Date(2026, 10, 18, 16, 0, 0, 0, time.Local)
+	ctx := t.Context()
+
+	until, err := b.Ban(ctx, "Steve", "griefing the castle", 2*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !until.Equal(now.Add(2 * time.Hour)) {
+		t.Errorf("Ban() until = %v, want two hours on", until)
+	}
+	if got := last(mgr); got != "ban Steve griefing the castle" {
+		t.Errorf("sent %q, want the ban command", got)
+	}
+	if _, err := b.Ban(ctx, "Steve", "hi^Mop Steve", time.Hour, now); err == nil {
+		t.Error("Ban() with ^ in the reason succeeded")
+	}
+	if _, err := b.Ban(ctx, "Steve; op Steve", "", time.Hour, now); err == nil {
+		t.Error("Ban() of a command succeeded")
+	}
+
+	// With the server stopped, the ban is written for it to read at start.
+	b.Running = false
+	if _, err := b.Ban(ctx, "Alex", "", 30*time.Minute, now); err != nil {
+		t.Fatal(err)
+	}
+	banned, err := players.LoadBannedPlayers(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := players.BannedPlayer{
+		UUID: players.OfflineUUID("Alex"), Name: "Alex", Source: "Server", Reason: DefaultBanReason,
+		Created: now.Format(players.BanTimeFormat), Expires: now.Add(30 * time.Minute).Format(players.BanTimeFormat),
+	}
+	if len(banned) != 1 || banned[0] != want {
+		t.Errorf("banned-players.json = %+v, want [%+v]", banned, want)
+	}
+
+	ledger, err := LoadLedger(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.Bans) != 2 || ledger.Bans["alex"].Reason != DefaultBanReason {
+		t.Errorf("ledger bans = %+v, want Steve and Alex", ledger.Bans)
+	}
+
+	// A ban for good takes over from the timed one.
+	if _, err := b.Ban(ctx, "alex", "", 0, now); err != nil {
+		t.Fatal(err)
+	}
+	if ledger, _ = LoadLedger(dir); len(ledger.Bans) != 1 {
+		t.Errorf("ledger bans = %+v, want only Steve's timed ban", ledger.Bans)
+	}
+
+	// Bans that ran out while the daemon was down go on its first pass.
+	cfg := &Config{ServerDir: dir, Manager: mgr, Output: ui.NewWriter(&bytes.Buffer{}, false)}
+	if err := pardon(ctx, cfg, now.Add(3*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if got := last(mgr); got != "pardon Steve" {
+		t.Errorf("sent %q, want pardon Steve", got)
+	}
+	if ledger, _ = LoadLedger(dir); len(ledger.Bans) != 0 {
+		t.Errorf("ledger bans = %+v after the pardon, want none", ledger.Bans)
+	}
+}
+
+func TestPardonAlongsideChatFilter(t *testing.T) {
+	dir := t.TempDir()
+	policy, err := (&Settings{Steps: []string{"warn"}}).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr := management.NewMockManager()
+	newConfig := func() *Config {
+		return &Config{ServerDir: dir, Manager: mgr, Output: ui.NewWriter(&bytes.Buffer{}, false)}
+	}
+	now := time.Date(2026, 10, 18, 16, 0, 0, 0, time.Local)
+	ctx := t.Context()
+
+	// Chat is filtered while bans run out and are lifted; each side reads
+	// the ledger, changes it and writes it back, and neither may lose the
+	// other's changes.
+	const messages = 20
+	chatters := []string{"Steve", "Alex", "Kid"}
+	errs := make([]error, len(chatters)+1)
+	var wg sync.WaitGroup
+	for i, player := range chatters {
+		m := &moderator{cfg: newConfig(), filter: NewFilter([]string{"crap"}), policy: policy}
+		wg.Go(func() {
+			for range messages {
+				if err := m.handle(ctx, player, "oh crap", now); err != nil {
+					errs[i] = err
+					return
+				}
+			}
+		})
+	}
+	cfg := newConfig()
+	wg.Go(func() {
+		for i := range messages {
+			err := UpdateLedger(dir, func(l *Ledger) error {
+				l.Bans[fmt.Sprint("griefer", i)] = Ban{Player: fmt.Sprint("Griefer", i), Until: now}
+				return nil
+			})
+			if err == nil {
+				err = pardon(ctx, cfg, now)
+			}
+			if err != nil {
+				errs[len(chatters)] = err
+				return
+			}
+		}
+	})
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		t.Fatal(err)
+	}
+
+	ledger, err := LoadLedger(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, player := range chatters {
+		if n := ledger.Active(player, now, DefaultForget); n != messages {
+			t.Errorf("%s has %d strikes, want %d", player, n, messages)
+		}
+	}
+	if len(ledger.Bans) != 0 {
+		t.Errorf("ledger bans = %+v, want all lifted", ledger.Bans)
+	}
+}
+
+func TestRunBansHeartbeat(t *testing.T) {
+	dir := t.TempDir()
+	if LiftingBans(dir, time.Now()) {
+		t.Fatal("LiftingBans() before RunBans started")
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error)
+	go func() {
+		done <- RunBans(ctx, &Config{ServerDir: dir, Manager: management.NewMockManager(), Output: ui.NewWriter(&bytes.Buffer{}, false)})
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for !LiftingBans(dir, time.Now()) {
+		if time.Now().After(deadline) {
+			t.Fatal("RunBans never touched its heartbeat")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if LiftingBans(dir, time.Now().Add(time.Hour)) {
+		t.Error("LiftingBans() an hour on without a pass")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if LiftingBans(dir, time.Now()) {
+		t.Error("LiftingBans() after RunBans stopped")
+	}
+}
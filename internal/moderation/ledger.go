@@ -0,0 +1,185 @@
+package moderation
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+)
+
+// Files in the server directory.
+const (
+	// LedgerFile holds each player's strikes and temporary bans.
+	LedgerFile = "moderation-ledger.json"
+	// LogFile is the JSON-lines record of every moderation action.
+	LogFile = "moderation-log.jsonl"
+)
+
+// ledgerLock is held while the ledger is changed, so the daemon's chat
+// filter and timed bans and the command line don't undo each other's
+// changes.
+const ledgerLock = LedgerFile + ".lock"
+
+// Ledger is the strikes and temporary bans of each player, keyed by
+// lower-cased name.
+type Ledger struct {
+	Strikes map[string]Strikes `json:"strikes,omitempty"`
+	Bans    map[string]Ban     `json:"bans,omitempty"`
+}
+
+// Strikes are the times a player's chat was blocked.
+type Strikes struct {
+	Player string      `json:"player"`
+	Times  []time.Time `json:"times"`
+}
+
+// Ban is a temporary ban to lift at Until.
+type Ban struct {
+	Player string    `json:"player"`
+	Reason string    `json:"reason"`
+	Until  time.Time `json:"until"`
+}
+
+// LoadLedger reads the ledger from serverDir. A missing file gives an empty
+// ledger.
+func LoadLedger(serverDir string) (*Ledger, error) {
+	l := &Ledger{}
+	data, err := os.ReadFile(filepath.Join(serverDir, LedgerFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading %s: %w", LedgerFile, err)
+	default:
+		if err := json.Unmarshal(data, l); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", LedgerFile, err)
+		}
+	}
+	if l.Strikes == nil {
+		l.Strikes = make(map[string]Strikes)
+	}
+	if l.Bans == nil {
+		l.Bans = make(map[string]Ban)
+	}
+	return l, nil
+}
+
+// UpdateLedger loads serverDir's ledger, lets update change it and saves
+// it, holding the ledger's lock throughout. Nothing is saved if update
+// returns an error.
+func UpdateLedger(serverDir string, update func(*Ledger) error) error {
+	unlock, err := platform.LockFile(filepath.Join(serverDir, ledgerLock))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	l, err := LoadLedger(serverDir)
+	if err != nil {
+		return err
+	}
+	if err := update(l); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", LedgerFile, err)
+	}
+	if err := platform.WriteFileAtomic(filepath.Join(serverDir, LedgerFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", LedgerFile, err)
+	}
+	return nil
+}
+
+// Strike records a strike against player at now and returns how many
+// strikes they have within forget.
+func (l *Ledger) Strike(player string, now time.Time, forget time.Duration) int {
+	key := strings.ToLower(player)
+	s := l.Strikes[key]
+	s.Player = player
+	s.Times = append(active(s.Times, now, forget), now)
+	l.Strikes[key] = s
+	return len(s.Times)
+}
+
+// Active returns how many strikes player has within forget of now.
+func (l *Ledger) Active(player string, now time.Time, forget time.Duration) int {
+	return len(active(l.Strikes[strings.ToLower(player)].Times, now, forget))
+}
+
+// Forgive clears player's strikes and reports whether they had any.
+func (l *Ledger) Forgive(player string) bool {
+	key := strings.ToLower(player)
+	_, ok := l.Strikes[key]
+	delete(l.Strikes, key)
+	return ok
+}
+
+// active returns the times within forget of now.
+func active(times []time.Time, now time.Time, forget time.Duration) []time.Time {
+	return slices.DeleteFunc(slices.Clone(times), func(t time.Time) bool { return now.Sub(t) >= forget })
+}
+
+// Entry is one line of the moderation log.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Player string    `json:"player"`
+	Action string    `json:"action"`
+	// Strike is the player's strike count after a blocked message.
+	Strike int `json:"strike,omitempty"`
+	// Word is the blocked word matched and Message what the player said,
+	// or the reason for a ban from the command line.
+	Word    string `json:"word,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Detail is anything else worth knowing, such as a ban's length.
+	Detail string `json:"detail,omitempty"`
+}
+
+// AppendLog adds e to the moderation log in serverDir.
+func AppendLog(serverDir string, e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding moderation entry: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(serverDir, LogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", LogFile, err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %s: %w", LogFile, err)
+	}
+	return f.Close()
+}
+
+// LoadLog reads the moderation log in serverDir, oldest first. A missing
+// log is empty; malformed lines are skipped.
+func LoadLog(serverDir string) ([]Entry, error) {
+	f, err := os.Open(filepath.Join(serverDir, LogFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", LogFile, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("reading %s: %w", LogFile, err)
+	}
+	return entries, nil
+}
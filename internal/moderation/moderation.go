@@ -0,0 +1,113 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// Config configures the chat filter.
+type Config struct {
+	ServerDir string
+	Manager   management.ServerManager
+	Output    *ui.UI
+}
+
+// Run filters chat from the server log until ctx is cancelled; RunBans
+// lifts the bans it hands out. The words list and moderation.json are read
+// once at start; the ledger is read on each use, so a parent forgiving a
+// player from the command line takes effect at once.
+func Run(ctx context.Context, cfg *Config) error {
+	if cfg.Manager == nil || cfg.Output == nil || cfg.ServerDir == "" {
+		return errors.New("the chat filter needs a manager, output and server directory")
+	}
+	filter, err := LoadFilter(cfg.ServerDir)
+	if err != nil {
+		return err
+	}
+	policy, err := LoadPolicy(cfg.ServerDir)
+	if err != nil {
+		return err
+	}
+	if filter.Len() == 0 {
+		cfg.Output.Warn("No blocked words in %s — chat will not be filtered", WordsFile)
+	}
+	m := &moderator{cfg: cfg, filter: filter, policy: policy}
+
+	stream := events.NewStream(cfg.ServerDir)
+	parser, err := events.LoadParser(cfg.ServerDir)
+	if err != nil {
+		cfg.Output.Warn("Ignoring custom chat formats: %s", err)
+	}
+	stream.Parser = parser
+	evs := stream.Subscribe(ctx, events.FromEnd)
+
+	for ev := range evs {
+		if ev.Kind == events.Chat {
+			m.report(m.handle(ctx, ev.Player, ev.Message, time.Now()))
+		}
+	}
+	return nil
+}
+
+type moderator struct {
+	cfg    *Config
+	filter *Filter
+	policy *Policy
+}
+
+// handle checks one chat message and, if it has a blocked word, gives the
+// player a strike and the action their strike count calls for.
+func (m *moderator) handle(ctx context.Context, player, msg string, now time.Time) error {
+	if m.policy.Exempt(player) {
+		return nil
+	}
+	word, ok := m.filter.Match(msg)
+	if !ok {
+		return nil
+	}
+	dir := m.cfg.ServerDir
+	var n int
+	var step Step
+	var cmd string
+	entry := &Entry{Time: now, Player: player, Word: word, Message: msg}
+	err := UpdateLedger(dir, func(ledger *Ledger) error {
+		n = ledger.Strike(player, now, m.policy.Forget)
+		step = m.policy.Step(n)
+		next := m.policy.Step(n + 1)
+		switch step.Action {
+		case ActionWarn:
+			text := fmt.Sprintf("Please keep chat friendly — that's strike %d. Next time is %s.", n, next)
+			cmd = management.Tellraw(player, management.Text{Text: text, Color: "red"})
+		case ActionKick:
+			cmd = fmt.Sprintf("kick %s Please keep chat friendly (strike %d). Next time is %s.", player, n, next)
+		default:
+			reason := fmt.Sprintf("Banned for %s for bad language (strike %d)", FormatDuration(step.Ban), n)
+			cmd = fmt.Sprintf("ban %s %s", player, reason)
+			ledger.Bans[strings.ToLower(player)] = Ban{Player: player, Reason: reason, Until: now.Add(step.Ban)}
+			entry.Detail = FormatDuration(step.Ban)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	entry.Action, entry.Strike = step.Action, n
+	if err := m.cfg.Manager.SendCommand(ctx, cmd); err != nil {
+		entry.Detail = strings.TrimSpace(entry.Detail + " (failed: " + err.Error() + ")")
+	}
+	m.cfg.Output.Info("Blocked %q from %s: strike %d, %s", word, player, n, step)
+	return AppendLog(dir, entry)
+}
+
+func (m *moderator) report(err error) {
+	if err != nil {
+		m.cfg.Output.Warn("Chat filter: %s", err)
+	}
+}
@@ -0,0 +1,147 @@
+package moderation
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// last returns the last command sent to mgr.
+func last(mgr *management.MockManager) string {
+	cmds := mgr.Commands()
+	if len(cmds) == 0 {
+		return ""
+	}
+	return cmds[len(cmds)-1]
+}
+
+func TestCompile(t *testing.T) {
+	p, err := (&Settings{}).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Steps) != 5 || p.Forget != DefaultForget {
+		t.Errorf("defaults = %+v, want five steps forgotten after %s", p, DefaultForget)
+	}
+	if got := p.Step(9); got.Action != ActionBan || got.Ban != 24*time.Hour {
+		t.Errorf("Step(9) = %+v, want the last step, a 1d ban", got)
+	}
+
+	for _, bad := range []Settings{
+		{Steps: []string{"shout"}},
+		{Steps: []string{"ban"}},
+		{Steps: []string{"ban forever"}},
+		{Steps: []string{"kick 5m"}},
+		{ForgetAfter: "-1h"},
+		{ForgetAfter: "0d"},
+	} {
+		if _, err := bad.Compile(); err == nil {
+			t.Errorf("Compile(%+v) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for _, s := range []string{"30m", "2h", "7d", "90s"} {
+		d, err := ParseDuration(s)
+		if err != nil {
+			t.Fatalf("ParseDuration(%q): %v", s, err)
+		}
+		want := s
+		if s == "90s" {
+			want = "1m30s"
+		}
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(ParseDuration(%q)) = %q, want %q", s, got, want)
+		}
+	}
+}
+
+func TestHandleEscalates(t *testing.T) {
+	dir := t.TempDir()
+	policy, err := (&Settings{Steps: []string{"warn", "kick", "ban 1h"}, ForgetAfter: "1d", Exempt: []string{"Dad"}}).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr := management.NewMockManager()
+	m := &moderator{
+		cfg:    &Config{ServerDir: dir, Manager: mgr, Output: ui.NewWriter(&bytes.Buffer{}, false)},
+		filter: NewFilter([]string{"crap"}),
+		policy: policy,
+	}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+	ctx := t.Context()
+
+	steps := []struct {
+		player, msg string
+		at          time.Time
+		want        string // prefix of the command sent, "" for none
+	}{
+		{"Steve", "good game", now, ""},
+		{"Dad", "oh crap", now, ""},
+		{"Steve", "oh crap", now, `tellraw Steve ["",{"text":"Please keep chat friendly — that's strike 1. Next time is a kick."`},
+		{"Steve", "c r a p", now.Add(time.Minute), "kick Steve Please keep chat friendly (strike 2). Next time is a 1h ban."},
+		{"Steve", "CRAAAP", now.Add(2 * time.Minute), "ban Steve Banned for 1h for bad language (strike 3)"},
+		// A day on, the first two strikes have been forgotten.
+		{"Steve", "crap", now.Add(24*time.Hour + time.Minute + time.Second), "kick Steve"},
+	}
+	for i, s := range steps {
+		before := last(mgr)
+		if err := m.handle(ctx, s.player, s.msg, s.at); err != nil {
+			t.Fatal(err)
+		}
+		got := last(mgr)
+		if s.want == "" {
+			if got != before {
+				t.Errorf("step %d: %s saying %q sent %q, want nothing", i, s.player, s.msg, got)
+			}
+			continue
+		}
+		if !strings.HasPrefix(got, s.want) {
+			t.Errorf("step %d: %s saying %q sent %q, want %q", i, s.player, s.msg, got, s.want)
+		}
+	}
+
+	ledger, err := LoadLedger(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ban, ok := ledger.Bans["steve"]
+	if !ok || !ban.Until.Equal(now.Add(2*time.Minute+time.Hour)) {
+		t.Fatalf("Steve's ban = %+v, want one lifted an hour after the third strike", ban)
+	}
+
+	// The ban isn't lifted early, then is.
+	if err := pardon(ctx, m.cfg, ban.Until.Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if got := last(mgr); strings.HasPrefix(got, "pardon") {
+		t.Errorf("pardon before the ban ends sent %q", got)
+	}
+	if err := pardon(ctx, m.cfg, ban.Until); err != nil {
+		t.Fatal(err)
+	}
+	if got := last(mgr); got != "pardon Steve" {
+		t.Errorf("pardon after the ban ends sent %q, want pardon Steve", got)
+	}
+
+	entries, err := LoadLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actions []string
+	for _, e := range entries {
+		actions = append(actions, e.Action)
+	}
+	if want := []string{"warn", "kick", "ban", "kick", "pardon"}; !slices.Equal(actions, want) {
+		t.Errorf("log actions = %q, want %q", actions, want)
+	}
+	if entries[0].Word != "crap" || entries[0].Message != "oh crap" || entries[0].Strike != 1 {
+		t.Errorf("first log entry = %+v, want the word, message and strike", entries[0])
+	}
+}
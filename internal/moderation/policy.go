@@ -0,0 +1,189 @@
+// Package moderation filters game chat against blocked-words.txt without a
+// server plugin, so it works on vanilla and Fabric as well as Paper. Each
+// blocked message earns the player a strike, and strikes escalate from a
+// warning to a kick to a temporary ban. Parents can ban players for a
+// while by hand too, and RunBans lifts timed bans as they run out. Strikes
+// and bans are kept in a ledger and every action in a log parents can
+// review.
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigFile tunes moderation in the server directory.
+const ConfigFile = "moderation.json"
+
+// Actions taken against a player.
+const (
+	ActionWarn   = "warn"
+	ActionKick   = "kick"
+	ActionBan    = "ban"
+	ActionPardon = "pardon"
+	// ActionForgive is a parent clearing a player's strikes.
+	ActionForgive = "forgive"
+)
+
+// defaultSteps is the escalation used without a config: two warnings, a
+// kick, then bans of an hour and a day.
+var defaultSteps = []string{"warn", "warn", "kick", "ban 1h", "ban 1d"}
+
+// DefaultForget is how long a strike counts without a config.
+const DefaultForget = 7 * 24 * time.Hour
+
+// Settings is the on-disk form of ConfigFile:
+//
+//	{
+//	  "steps": ["warn", "kick", "ban 30m", "ban 1d"],
+//	  "forget_after": "3d",
+//	  "exempt": ["Dad"]
+//	}
+type Settings struct {
+	// Steps are the actions for a player's first, second, ... strike;
+	// the last repeats for any further strikes.
+	Steps []string `json:"steps,omitempty"`
+	// ForgetAfter is how long a strike counts, such as "7d" or "12h".
+	ForgetAfter string `json:"forget_after,omitempty"`
+	// Exempt names players whose chat is never filtered.
+	Exempt []string `json:"exempt,omitempty"`
+}
+
+// Step is one rung of the escalation.
+type Step struct {
+	Action string
+	// Ban is how long a ban lasts.
+	Ban time.Duration
+}
+
+// String describes the step to a player: "a warning", "a kick", "a 1h ban".
+func (s Step) String() string {
+	switch s.Action {
+	case ActionWarn:
+		return "a warning"
+	case ActionKick:
+		return "a kick"
+	default:
+		return "a " + FormatDuration(s.Ban) + " ban"
+	}
+}
+
+// Policy is the compiled config.
+type Policy struct {
+	Steps  []Step
+	Forget time.Duration
+	exempt map[string]bool
+}
+
+// LoadPolicy reads the moderation config from serverDir. A missing file
+// gives the defaults.
+func LoadPolicy(serverDir string) (*Policy, error) {
+	var cfg Settings
+	data, err := os.ReadFile(filepath.Join(serverDir, ConfigFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading %s: %w", ConfigFile, err)
+	default:
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", ConfigFile, err)
+		}
+	}
+	p, err := cfg.Compile()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFile, err)
+	}
+	return p, nil
+}
+
+// Compile checks the config and fills in defaults.
+func (c *Settings) Compile() (*Policy, error) {
+	p := &Policy{Forget: DefaultForget, exempt: make(map[string]bool)}
+	specs := c.Steps
+	if len(specs) == 0 {
+		specs = defaultSteps
+	}
+	for _, spec := range specs {
+		s, err := parseStep(spec)
+		if err != nil {
+			return nil, err
+		}
+		p.Steps = append(p.Steps, s)
+	}
+	if c.ForgetAfter != "" {
+		d, err := ParseDuration(c.ForgetAfter)
+		if err != nil {
+			return nil, fmt.Errorf("forget_after: %w", err)
+		}
+		p.Forget = d
+	}
+	for _, name := range c.Exempt {
+		p.exempt[strings.ToLower(name)] = true
+	}
+	return p, nil
+}
+
+// Exempt reports whether player's chat goes unfiltered.
+func (p *Policy) Exempt(player string) bool {
+	return p.exempt[strings.ToLower(player)]
+}
+
+// Step returns the action for a player's nth strike, counting from 1.
+func (p *Policy) Step(n int) Step {
+	return p.Steps[min(max(n, 1), len(p.Steps))-1]
+}
+
+// parseStep reads "warn", "kick" or "ban <duration>".
+func parseStep(spec string) (Step, error) {
+	fields := strings.Fields(strings.ToLower(spec))
+	switch {
+	case len(fields) == 1 && (fields[0] == ActionWarn || fields[0] == ActionKick):
+		return Step{Action: fields[0]}, nil
+	case len(fields) == 2 && fields[0] == ActionBan:
+		d, err := ParseDuration(fields[1])
+		if err != nil {
+			return Step{}, fmt.Errorf("step %q: %w", spec, err)
+		}
+		return Step{Action: ActionBan, Ban: d}, nil
+	default:
+		return Step{}, fmt.Errorf("step %q: use warn, kick or ban <duration>", spec)
+	}
+}
+
+// ParseDuration reads a positive duration, allowing days: "30m", "2h",
+// "7d".
+func ParseDuration(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%q is not a positive duration such as 30m, 2h or 7d", s)
+	}
+	return d, nil
+}
+
+// FormatDuration writes d as ParseDuration reads it: "1d", "2h", "45m".
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d >= time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return d.String()
+	}
+}
@@ -0,0 +1,183 @@
+package moderation
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WordsFile is the blocked words list in the server directory, shared
+// with ChatSentry on Paper.
+const WordsFile = "blocked-words.txt"
+
+// leet maps characters used in place of letters to the letter.
+var leet = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'i', '+': 't',
+}
+
+// lookalikes are the characters that can stand for each letter in chat.
+var lookalikes = map[rune]string{
+	'a': "a4@", 'b': "b8", 'e': "e3", 'g': "g9", 'i': "i1!|l", 'l': "l1|i",
+	'o': "o0", 's': "s5$z", 't': "t7+",
+}
+
+// separator matches what players put between letters to dodge a filter:
+// spaces, dots, dashes and the like. Apostrophes don't count, so "he'll"
+// isn't read as "hell".
+const separator = `[^\pL\pN']+`
+
+// wordLetters are the single letters that are words by themselves, so a
+// blocked word next to one isn't taken for part of a spaced-out word.
+const wordLetters = "ai"
+
+// Filter matches chat against a blocked words list.
+type Filter struct {
+	words    []string
+	patterns []*regexp.Regexp
+}
+
+// LoadFilter reads serverDir's blocked words list. A missing list blocks
+// nothing.
+func LoadFilter(serverDir string) (*Filter, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, WordsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return NewFilter(nil), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", WordsFile, err)
+	}
+	var words []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return NewFilter(words), nil
+}
+
+// NewFilter returns a filter for words, which may be phrases. Matching
+// ignores case, repeated letters ("fuuuck"), leetspeak ("sh1t", "a$$") and
+// letters spaced out one by one ("f u c k"), but only whole words match,
+// so "hello", "class", "it's hit" and "hell o" are fine. A "*" in a word
+// stands for any one symbol or digit, as in "f#ck".
+func NewFilter(words []string) *Filter {
+	f := &Filter{}
+	seen := make(map[string]bool)
+	for _, w := range words {
+		norm := normalize(w)
+		if norm == "" || seen[norm] {
+			continue
+		}
+		seen[norm] = true
+		f.words = append(f.words, norm)
+		f.patterns = append(f.patterns, regexp.MustCompile(wordPattern(norm)))
+	}
+	return f
+}
+
+// Len returns the number of distinct blocked words.
+func (f *Filter) Len() int { return len(f.words) }
+
+// Match returns the first blocked word in msg.
+func (f *Filter) Match(msg string) (string, bool) {
+	msg = strings.ToLower(msg)
+	for i, re := range f.patterns {
+		for _, loc := range re.FindAllStringIndex(msg, -1) {
+			if wholeWord(msg, loc[0], loc[1]) {
+				return f.words[i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// wholeWord reports whether msg[start:end] stands as a word by itself:
+// it doesn't start after an apostrophe ("it's hit") or inside a longer
+// word, doesn't end inside one, and isn't spelled out together with a
+// lone letter next to it ("hell o").
+func wholeWord(msg string, start, end int) bool {
+	before, _ := utf8.DecodeLastRuneInString(msg[:start])
+	after, _ := utf8.DecodeRuneInString(msg[end:])
+	if (start > 0 && !isSeparator(before)) || (end < len(msg) && (unicode.IsLetter(after) || unicode.IsDigit(after))) {
+		return false
+	}
+	return !loneLetter(strings.TrimRightFunc(msg[:start], isSeparator), false) &&
+		!loneLetter(strings.TrimLeftFunc(msg[end:], isSeparator), true)
+}
+
+// loneLetter reports whether s ends, or starts if first is set, with a
+// letter standing alone that isn't a word by itself.
+func loneLetter(s string, first bool) bool {
+	if s == "" {
+		return false
+	}
+	r, n := utf8.DecodeLastRuneInString(s)
+	rest := s[:len(s)-n]
+	next, _ := utf8.DecodeLastRuneInString(rest)
+	if first {
+		r, n = utf8.DecodeRuneInString(s)
+		rest = s[n:]
+		next, _ = utf8.DecodeRuneInString(rest)
+	}
+	return unicode.IsLetter(r) && !strings.ContainsRune(wordLetters, r) && (rest == "" || isSeparator(next))
+}
+
+// isSeparator reports whether r can come between words or spaced-out
+// letters.
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+}
+
+// normalize lower-cases a blocked word, reads leetspeak as letters and
+// collapses whitespace.
+func normalize(w string) string {
+	w = strings.Join(strings.Fields(strings.ToLower(w)), " ")
+	return strings.Map(func(r rune) rune {
+		if l, ok := leet[r]; ok {
+			return l
+		}
+		return r
+	}, w)
+}
+
+// wordPattern builds the regular expression for a normalized word: each
+// letter may repeat and be written as a lookalike, and either all the
+// letters run together or every one is set apart by separators. Match
+// checks that the word stands by itself.
+func wordPattern(word string) string {
+	var compact, spaced strings.Builder
+	first := true
+	for _, r := range word {
+		if r == ' ' {
+			compact.WriteString(`\s+`)
+			continue
+		}
+		if !first {
+			spaced.WriteString(separator)
+		}
+		first = false
+		var letter string
+		switch {
+		case r == '*':
+			letter = `[^\pL\s]`
+		case lookalikes[r] != "":
+			letter = "[" + regexp.QuoteMeta(lookalikes[r]) + "]+"
+		default:
+			letter = "(?:" + regexp.QuoteMeta(string(r)) + ")+"
+		}
+		compact.WriteString(letter)
+		spaced.WriteString(letter)
+	}
+	return compact.String() + "|" + spaced.String()
+}
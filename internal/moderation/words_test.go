@@ -0,0 +1,78 @@
+package moderation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilterMatch(t *testing.T) {
+	f := NewFilter([]string{"hell", "ass", "shit", "f*ck", "sh*t", "a$$", "bad word", "SHIT"})
+	if f.Len() != 6 {
+		t.Errorf("Len() = %d, want 6: \"a$$\" and \"SHIT\" repeat earlier words", f.Len())
+	}
+	tests := []struct {
+		msg  string
+		want string // "" for no match
+	}{
+		{"what the hell", "hell"},
+		{"HELL yeah", "hell"},
+		{"heeeell", "hell"},
+		{"h e l l", "hell"},
+		{"h.3.l.l", "hell"},
+		{"what the hell's that", "hell"},
+		{"what a hell i had", "hell"},
+		{"hello there", ""},
+		{"he'll be back", ""},
+		{"h e l l o", ""},
+		{"lol it's hell o", ""},
+		{"shell", ""},
+		{"you @$$", "ass"},
+		{"a55!", "ass"},
+		{"first class", ""},
+		{"pass the ball", ""},
+		{"sh1t", "shit"},
+		{"SHIIIT", "shit"},
+		{"s h i t", "shit"},
+		{"sh#t", "sh*t"},
+		{"it's hit the ground", ""},
+		{"Dad's hit list", ""},
+		{"sh it", ""},
+		{"what a sshot", ""},
+		{"nice shot", ""},
+		{"f*ck", "f*ck"},
+		{"f#ck that", "f*ck"},
+		{"said a bad   word", "bad word"},
+		{"badword", ""},
+		{"good game", ""},
+	}
+	for _, tt := range tests {
+		got, ok := f.Match(tt.msg)
+		if (tt.want == "") == ok || got != tt.want {
+			t.Errorf("Match(%q) = %q, %v; want %q", tt.msg, got, ok, tt.want)
+		}
+	}
+}
+
+func TestLoadFilter(t *testing.T) {
+	dir := t.TempDir()
+	list := "# comment\n\nhell\n  crap  \n"
+	if err := os.WriteFile(filepath.Join(dir, WordsFile), []byte(list), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := LoadFilter(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", f.Len())
+	}
+	if _, ok := f.Match("oh crap"); !ok {
+		t.Error(`Match("oh crap") found nothing`)
+	}
+
+	f, err = LoadFilter(t.TempDir())
+	if err != nil || f.Len() != 0 {
+		t.Errorf("LoadFilter() with no list = %d words, %v; want none", f.Len(), err)
+	}
+}
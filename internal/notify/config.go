@@ -0,0 +1,196 @@
+// Package notify sends server news — starts, stops, crashes, players
+// joining and leaving, backups and license expiry — to Discord, Slack or
+// any service that accepts a JSON webhook, so a family or team chat hears
+// about it without anyone watching the console.
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+)
+
+// ConfigFile lists the webhook targets in the server directory.
+const ConfigFile = "notifications.json"
+
+// Events a target can subscribe to.
+const (
+	Start         = management.EventStart
+	Stop          = management.EventStop
+	Crash         = "crash"
+	Join          = "join"
+	Leave         = "leave"
+	Chat          = "chat"
+	Backup        = management.EventBackup
+	BackupFailed  = management.EventBackupFailed
+	LicenseExpiry = "license_expiry"
+	JoinRequest   = "join_request"
+)
+
+// Kinds lists every event, in the order they are shown.
+var Kinds = []string{Start, Stop, Crash, Join, Leave, Chat, JoinRequest, Backup, BackupFailed, LicenseExpiry}
+
+// Target types, each with its own payload.
+const (
+	TypeDiscord = "discord"
+	TypeSlack   = "slack"
+	TypeJSON    = "json"
+)
+
+// defaultTemplates are the messages used unless the config overrides them.
+var defaultTemplates = map[string]string{
+	Start:         "🟢 {{.Server}} is starting",
+	Stop:          "🔴 {{.Server}} is shutting down",
+	Crash:         "💥 {{.Server}} stopped unexpectedly{{if .Detail}}: {{.Detail}}{{end}}",
+	Join:          "👋 {{.Player}} joined {{.Server}}",
+	Leave:         "{{.Player}} left {{.Server}}",
+	Chat:          "💬 <{{.Player}}> {{.Detail}}",
+	Backup:        "💾 Backup finished: {{.Detail}}",
+	BackupFailed:  "⚠️ Backup failed: {{.Detail}}",
+	LicenseExpiry: "🔑 The mc-dad-server license {{.Detail}}",
+	JoinRequest:   "🙋 {{.Player}} wants to join {{.Server}}. Approve with: mc-dad-server whitelist approve {{.Player}}",
+}
+
+// Defaults for the optional Settings fields.
+const (
+	DefaultServer    = "Minecraft server"
+	DefaultRateLimit = 10
+)
+
+// Settings is the on-disk form of ConfigFile:
+//
+//	{
+//	  "server": "Family server",
+//	  "templates": {"join": "🎮 {{.Player}} is on!"},
+//	  "targets": [
+//	    {"name": "family", "type": "discord", "url": "https://discord.com/api/webhooks/...",
+//	     "events": ["crash", "join"]},
+//	    {"name": "ops", "type": "json", "url": "https://example.com/hook"}
+//	  ]
+//	}
+type Settings struct {
+	// Server names the server in messages.
+	Server string `json:"server,omitempty"`
+	// Templates override the default message for an event, for every
+	// target. They are Go templates over Event.
+	Templates map[string]string `json:"templates,omitempty"`
+	// RateLimit is the most messages a target is sent a minute; more
+	// are dropped.
+	RateLimit int      `json:"rate_limit,omitempty"`
+	Targets   []Target `json:"targets"`
+}
+
+// Target is one webhook.
+type Target struct {
+	Name string `json:"name,omitempty"`
+	// Type is discord, slack or json.
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	// Events are those the target hears about; empty means all of them
+	// but chat, which is only sent to targets that ask for it.
+	Events []string `json:"events,omitempty"`
+	// Templates override the message for an event for this target only.
+	Templates map[string]string `json:"templates,omitempty"`
+}
+
+// Label names the target in output without revealing its URL, which is a
+// secret for Discord and Slack webhooks.
+func (t *Target) Label() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	if u, err := url.Parse(t.URL); err == nil && u.Host != "" {
+		return t.Type + " (" + u.Host + ")"
+	}
+	return t.Type
+}
+
+// Wants reports whether the target subscribes to event.
+func (t *Target) Wants(event string) bool {
+	if len(t.Events) == 0 {
+		return event != Chat
+	}
+	return slices.Contains(t.Events, event)
+}
+
+// Load reads ConfigFile from serverDir. A missing file has no targets.
+func Load(serverDir string) (*Settings, error) {
+	s := &Settings{}
+	data, err := os.ReadFile(filepath.Join(serverDir, ConfigFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading %s: %w", ConfigFile, err)
+	default:
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", ConfigFile, err)
+		}
+	}
+	return s, nil
+}
+
+// compiled is a target with its templates parsed.
+type compiled struct {
+	Target
+	templates map[string]*template.Template
+}
+
+// compile checks the settings and parses every target's templates.
+func (s *Settings) compile() ([]*compiled, error) {
+	for event := range s.Templates {
+		if !slices.Contains(Kinds, event) {
+			return nil, fmt.Errorf("%s: unknown event %q in templates (want one of %s)", ConfigFile, event, strings.Join(Kinds, ", "))
+		}
+	}
+	var targets []*compiled
+	for i := range s.Targets {
+		t := s.Targets[i]
+		where := fmt.Sprintf("%s: target %d", ConfigFile, i+1)
+		if t.Name != "" {
+			where = fmt.Sprintf("%s: target %q", ConfigFile, t.Name)
+		}
+		switch t.Type {
+		case TypeDiscord, TypeSlack, TypeJSON:
+		default:
+			return nil, fmt.Errorf("%s: unknown type %q (want discord, slack or json)", where, t.Type)
+		}
+		if u, err := url.Parse(t.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("%s: url must be an http or https URL", where)
+		}
+		for _, event := range t.Events {
+			if !slices.Contains(Kinds, event) {
+				return nil, fmt.Errorf("%s: unknown event %q (want one of %s)", where, event, strings.Join(Kinds, ", "))
+			}
+		}
+		c := &compiled{Target: t, templates: make(map[string]*template.Template, len(Kinds))}
+		for _, event := range Kinds {
+			text := defaultTemplates[event]
+			if override, ok := s.Templates[event]; ok {
+				text = override
+			}
+			if override, ok := t.Templates[event]; ok {
+				text = override
+			}
+			tmpl, err := template.New(event).Parse(text)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s template: %w", where, event, err)
+			}
+			c.templates[event] = tmpl
+		}
+		for event := range t.Templates {
+			if !slices.Contains(Kinds, event) {
+				return nil, fmt.Errorf("%s: unknown event %q in templates", where, event)
+			}
+		}
+		targets = append(targets, c)
+	}
+	return targets, nil
+}
@@ -0,0 +1,239 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// Delivery limits. A webhook that keeps failing is given up on after
+// maxAttempts rather than holding up the server's lifecycle.
+const (
+	maxAttempts    = 4
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+	requestTimeout = 10 * time.Second
+)
+
+// Event is something worth telling people about. Templates see its fields:
+// {{.Event}}, {{.Server}}, {{.Player}}, {{.Detail}} and {{.Time}}.
+type Event struct {
+	Event  string
+	Time   time.Time
+	Server string
+	// Player is set for join, leave and chat.
+	Player string
+	// Detail is a short description, such as the backup file or an error,
+	// or what was said in chat.
+	Detail string
+}
+
+// Notifier sends events to the configured targets. It is safe for
+// concurrent use.
+type Notifier struct {
+	server  string
+	targets []*compiled
+	limit   int
+	client  *http.Client
+	output  *ui.UI
+	// backoff is the pause before the first retry; it doubles after each.
+	backoff time.Duration
+
+	mu   sync.Mutex
+	sent map[*compiled][]time.Time
+}
+
+// New returns a Notifier for s. Delivery problems are reported to output.
+func New(s *Settings, output *ui.UI) (*Notifier, error) {
+	targets, err := s.compile()
+	if err != nil {
+		return nil, err
+	}
+	n := &Notifier{
+		server:  s.Server,
+		targets: targets,
+		limit:   s.RateLimit,
+		client:  &http.Client{Timeout: requestTimeout},
+		output:  output,
+		backoff: initialBackoff,
+		sent:    make(map[*compiled][]time.Time),
+	}
+	if n.server == "" {
+		n.server = DefaultServer
+	}
+	if n.limit <= 0 {
+		n.limit = DefaultRateLimit
+	}
+	return n, nil
+}
+
+// Enabled reports whether any target is configured.
+func (n *Notifier) Enabled() bool {
+	return len(n.targets) > 0
+}
+
+// Notify sends a lifecycle event, reporting rather than returning any
+// failure. It implements management.Notifier.
+func (n *Notifier) Notify(ctx context.Context, event, detail string) {
+	if err := n.Send(ctx, &Event{Event: event, Detail: detail}); err != nil {
+		n.output.Warn("Notification failed: %s", err)
+	}
+}
+
+// Send delivers ev to every target subscribed to it, filling in its time
+// and server name if unset.
+func (n *Notifier) Send(ctx context.Context, ev *Event) error {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if ev.Server == "" {
+		ev.Server = n.server
+	}
+	var errs []error
+	for _, t := range n.targets {
+		if !t.Wants(ev.Event) {
+			continue
+		}
+		if err := n.deliver(ctx, t, ev); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", t.Label(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver renders ev for t and posts it, within t's rate limit.
+func (n *Notifier) deliver(ctx context.Context, t *compiled, ev *Event) error {
+	var text bytes.Buffer
+	if err := t.templates[ev.Event].Execute(&text, ev); err != nil {
+		return fmt.Errorf("rendering %s message: %w", ev.Event, err)
+	}
+	body, err := payload(t.Type, ev, text.String())
+	if err != nil {
+		return err
+	}
+	if !n.allow(t, ev.Time) {
+		return fmt.Errorf("more than %d messages a minute, dropping %s message", n.limit, ev.Event)
+	}
+	return n.post(ctx, t.URL, body)
+}
+
+// allow records a message to t at now and reports whether it is within
+// the rate limit.
+func (n *Notifier) allow(t *compiled, now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	recent := n.sent[t][:0]
+	for _, at := range n.sent[t] {
+		if now.Sub(at) < time.Minute {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= n.limit {
+		n.sent[t] = recent
+		return false
+	}
+	n.sent[t] = append(recent, now)
+	return true
+}
+
+// slackEscape escapes the characters Slack gives a meaning in text.
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// payload is the request body a target type expects.
+func payload(kind string, ev *Event, text string) ([]byte, error) {
+	var v any
+	switch kind {
+	case TypeDiscord:
+		// Mentions are turned off so a message can never ping @everyone.
+		v = map[string]any{"content": text, "allowed_mentions": map[string]any{"parse": []string{}}}
+	case TypeSlack:
+		// Slack reads <!channel> and <@U123> as mentions; escaped, they
+		// are shown as typed.
+		v = map[string]string{"text": slackEscape.Replace(text)}
+	default:
+		v = map[string]any{
+			"event":  ev.Event,
+			"time":   ev.Time.Format(time.RFC3339),
+			"server": ev.Server,
+			"player": ev.Player,
+			"detail": ev.Detail,
+			"text":   text,
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s message: %w", kind, err)
+	}
+	return data, nil
+}
+
+// post sends body to url, retrying network errors, rate limiting and
+// server errors with exponential backoff. Other client errors mean the
+// webhook is wrong and are not retried.
+func (n *Notifier) post(ctx context.Context, url string, body []byte) error {
+	wait := n.backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retryAfter time.Duration
+		retryAfter, err = n.postOnce(ctx, url, body)
+		if err == nil {
+			return nil
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) || attempt == maxAttempts {
+			break
+		}
+		pause := max(wait, retryAfter)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(pause, maxBackoff)):
+		}
+		wait *= 2
+	}
+	return err
+}
+
+// permanentError is a response that retrying won't fix.
+type permanentError struct{ status string }
+
+func (e *permanentError) Error() string { return "webhook refused the message: " + e.status }
+
+// postOnce makes one attempt. For a 429 it also returns how long the
+// webhook asked us to wait.
+func (n *Notifier) postOnce(ctx context.Context, url string, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, &permanentError{status: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mc-dad-server")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		secs, _ := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64)
+		return time.Duration(secs * float64(time.Second)), fmt.Errorf("webhook is rate limiting: %s", resp.Status)
+	case resp.StatusCode >= 500:
+		return 0, fmt.Errorf("webhook error: %s", resp.Status)
+	default:
+		return 0, &permanentError{status: resp.Status}
+	}
+}
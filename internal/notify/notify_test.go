@@ -0,0 +1,194 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// webhook is a test server that records the bodies posted to it and
+// answers with the given statuses in turn, then 204.
+type webhook struct {
+	mu       sync.Mutex
+	bodies   []map[string]any
+	statuses []int
+}
+
+func (h *webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, _ := io.ReadAll(r.Body)
+	var body map[string]any
+	_ = json.Unmarshal(data, &body)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.bodies = append(h.bodies, body)
+	if len(h.statuses) > 0 {
+		status := h.statuses[0]
+		h.statuses = h.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serve starts h and returns its URL.
+func serve(t *testing.T, h *webhook) string {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func newNotifier(t *testing.T, s *Settings) *Notifier {
+	t.Helper()
+	n, err := New(s, ui.NewWriter(&bytes.Buffer{}, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.backoff = time.Millisecond
+	return n
+}
+
+func TestSendPayloads(t *testing.T) {
+	discord, slack, generic := &webhook{}, &webhook{}, &webhook{}
+	n := newNotifier(t, &Settings{
+		Server:    "Family server",
+		Templates: map[string]string{Join: "{{.Player}} is on {{.Server}}"},
+		Targets: []Target{
+			{Type: TypeDiscord, URL: serve(t, discord), Events: []string{Join, Crash}},
+			{Type: TypeSlack, URL: serve(t, slack), Templates: map[string]string{Join: "hi {{.Player}}"}},
+			{Type: TypeJSON, URL: serve(t, generic), Events: []string{Crash}},
+		},
+	})
+
+	if err := n.Send(t.Context(), &Event{Event: Join, Player: "Steve"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Send(t.Context(), &Event{Event: Stop}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(discord.bodies) != 1 || discord.bodies[0]["content"] != "Steve is on Family server" {
+		t.Errorf("discord got %v, want the join only, with the shared template", discord.bodies)
+	}
+	if len(slack.bodies) != 2 || slack.bodies[0]["text"] != "hi Steve" || slack.bodies[1]["text"] != "🔴 Family server is shutting down" {
+		t.Errorf("slack got %v, want both events, the join with its own template", slack.bodies)
+	}
+	if len(generic.bodies) != 0 {
+		t.Errorf("json target got %v, want nothing: it only hears about crashes", generic.bodies)
+	}
+
+	// A player can't ping a Slack channel from chat.
+	if err := n.Send(t.Context(), &Event{Event: Join, Player: "<!channel> & <@U123>"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := slack.bodies[2]["text"]; got != "hi &lt;!channel&gt; &amp; &lt;@U123&gt;" {
+		t.Errorf("slack got %q, want the mentions escaped", got)
+	}
+
+	if err := n.Send(t.Context(), &Event{Event: Crash}); err != nil {
+		t.Fatal(err)
+	}
+	if len(generic.bodies) != 1 {
+		t.Fatalf("json target got %v, want the crash", generic.bodies)
+	}
+	got := generic.bodies[0]
+	if got["event"] != Crash || got["server"] != "Family server" || got["text"] != "💥 Family server stopped unexpectedly" {
+		t.Errorf("json payload = %v", got)
+	}
+}
+
+func TestSendRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantErr   bool
+		wantTries int
+	}{
+		{"succeeds first time", nil, false, 1},
+		{"retries server errors", []int{500, 502}, false, 3},
+		{"retries rate limiting", []int{429}, false, 2},
+		{"gives up eventually", []int{500, 500, 500, 500, 500}, true, maxAttempts},
+		{"doesn't retry a bad webhook", []int{404}, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &webhook{statuses: tt.statuses}
+			n := newNotifier(t, &Settings{Targets: []Target{{Type: TypeSlack, URL: serve(t, h)}}})
+			err := n.Send(t.Context(), &Event{Event: Start})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(h.bodies) != tt.wantTries {
+				t.Errorf("webhook called %d times, want %d", len(h.bodies), tt.wantTries)
+			}
+		})
+	}
+}
+
+func TestSendRateLimit(t *testing.T) {
+	h := &webhook{}
+	n := newNotifier(t, &Settings{RateLimit: 2, Targets: []Target{{Type: TypeSlack, URL: serve(t, h)}}})
+	now := time.Now()
+	for i, want := range []bool{true, true, false} {
+		err := n.Send(t.Context(), &Event{Event: Join, Player: "Steve", Time: now})
+		if (err == nil) != want {
+			t.Errorf("message %d: Send() error = %v, want sent %v", i+1, err, want)
+		}
+	}
+	// A minute later there is room again.
+	if err := n.Send(t.Context(), &Event{Event: Join, Player: "Steve", Time: now.Add(time.Minute)}); err != nil {
+		t.Errorf("Send() a minute later = %v, want sent", err)
+	}
+	if len(h.bodies) != 3 {
+		t.Errorf("webhook called %d times, want 3", len(h.bodies))
+	}
+}
+
+func TestNewRejectsBadSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Settings
+		want string
+	}{
+		{"unknown type", Settings{Targets: []Target{{Type: "teams", URL: "https://example.com"}}}, "unknown type"},
+		{"bad url", Settings{Targets: []Target{{Type: TypeJSON, URL: "example.com/hook"}}}, "http or https"},
+		{"unknown event", Settings{Targets: []Target{{Type: TypeJSON, URL: "https://example.com", Events: []string{"explode"}}}}, `unknown event "explode"`},
+		{"bad template", Settings{Templates: map[string]string{Join: "{{.Player"}, Targets: []Target{{Type: TypeJSON, URL: "https://example.com"}}}, "join template"},
+	}
+	for _, tt := range tests {
+		_, err := New(&tt.s, ui.NewWriter(&bytes.Buffer{}, false))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: New() error = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestTargetWants(t *testing.T) {
+	all := Target{}
+	chatty := Target{Events: []string{Join, Chat}}
+	tests := []struct {
+		target *Target
+		event  string
+		want   bool
+	}{
+		{&all, Crash, true},
+		{&all, Join, true},
+		{&all, Chat, false},
+		{&chatty, Chat, true},
+		{&chatty, Join, true},
+		{&chatty, Crash, false},
+	}
+	for _, tt := range tests {
+		if got := tt.target.Wants(tt.event); got != tt.want {
+			t.Errorf("%v.Wants(%s) = %v, want %v", tt.target.Events, tt.event, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/parental"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/players"
+)
+
+// RunRequests queues the players the whitelist turns away, for whitelist
+// approve, until ctx is cancelled. With a Notifier each player is also
+// announced the first time they ask; cfg.Notifier may be nil, since the
+// queue is kept whether or not there are webhooks.
+func RunRequests(ctx context.Context, cfg *Config) error {
+	if cfg.Output == nil || cfg.ServerDir == "" {
+		return errors.New("join requests need output and a server directory")
+	}
+	stream := events.NewStream(cfg.ServerDir)
+	parser, err := events.LoadParser(cfg.ServerDir)
+	if err != nil {
+		cfg.Output.Warn("Ignoring custom chat formats: %s", err)
+	}
+	stream.Parser = parser
+	for ev := range stream.Subscribe(ctx, events.FromEnd) {
+		if ev.Kind == events.NotWhitelisted {
+			recordRequest(ctx, cfg, &ev)
+		}
+	}
+	return nil
+}
+
+// recordRequest queues a player the whitelist turned away and reports them
+// the first time they ask; later attempts only add to their count. Players
+// parental controls took off the whitelist aren't asking to join.
+func recordRequest(ctx context.Context, cfg *Config, ev *events.Event) {
+	st, err := parental.LoadState(cfg.ServerDir)
+	if err != nil {
+		cfg.Output.Warn("Join request from %s: %s", ev.Player, err)
+		return
+	}
+	if _, held := st.Held(ev.Player); held {
+		return
+	}
+	first := false
+	err = players.EditRequests(cfg.ServerDir, func(reqs *players.Requests) error {
+		first = reqs.Record(ev.Player, ev.UUID, ev.Time)
+		return nil
+	})
+	if err != nil {
+		cfg.Output.Warn("Join request from %s: %s", ev.Player, err)
+		return
+	}
+	if first && cfg.Notifier != nil {
+		if err := cfg.Notifier.Send(ctx, &Event{Event: JoinRequest, Player: ev.Player, Detail: ev.UUID}); err != nil {
+			cfg.Output.Warn("Notification failed: %s", err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/parental"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/players"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+func TestRecordRequest(t *testing.T) {
+	at := time.Date(2026, 10, 18, 16, 0, 0, 0, time.Local)
+	refusals := []events.Event{
+		// Parental controls took Kid off the whitelist; their refusal
+		// isn't a request.
+		{Kind: events.NotWhitelisted, Player: "Kid", Time: at},
+		// Each refusal is logged twice, then Steve tries again.
+		{Kind: events.NotWhitelisted, Player: "Steve", Time: at},
+		{Kind: events.NotWhitelisted, Player: "Steve", Time: at},
+		{Kind: events.NotWhitelisted, Player: "Steve", Time: at.Add(time.Minute)},
+	}
+	for _, webhooks := range []bool{true, false} {
+		dir := t.TempDir()
+		st := &parental.State{Removed: map[string]parental.Removal{"kid": {Player: "Kid", Reason: "bedtime", Until: at.Add(12 * time.Hour)}}}
+		if err := st.Save(dir); err != nil {
+			t.Fatal(err)
+		}
+		h := &webhook{}
+		cfg := &Config{ServerDir: dir, Output: ui.NewWriter(&bytes.Buffer{}, false)}
+		if webhooks {
+			cfg.Notifier = newNotifier(t, &Settings{Targets: []Target{{Type: TypeJSON, URL: serve(t, h)}}})
+		}
+		for i := range refusals {
+			recordRequest(t.Context(), cfg, &refusals[i])
+		}
+
+		if webhooks && (len(h.bodies) != 1 || h.bodies[0]["event"] != JoinRequest || h.bodies[0]["player"] != "Steve") {
+			t.Errorf("sent %v, want one join request from Steve", h.bodies)
+		}
+		reqs, err := players.LoadRequests(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(reqs) != 1 || reqs[0].Attempts != 2 {
+			t.Errorf("webhooks %v: queue = %+v, want only Steve, with 2 attempts", webhooks, reqs)
+		}
+	}
+}
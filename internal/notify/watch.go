@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/license"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// DefaultPollInterval is how often the server is checked without a
+// Config.PollInterval.
+const DefaultPollInterval = 15 * time.Second
+
+// licenseCheck is how often the stored license's expiry is looked at, and
+// licenseWarnings are how long before expiry it is announced.
+const licenseCheck = 6 * time.Hour
+
+var licenseWarnings = []time.Duration{7 * 24 * time.Hour, 24 * time.Hour}
+
+// Config configures the notification watcher.
+type Config struct {
+	ServerDir string
+	Manager   management.ServerManager
+	Runner    platform.CommandRunner
+	Port      int
+	Notifier  *Notifier
+	// PollInterval is how often the server is checked for having died.
+	PollInterval time.Duration
+	Output       *ui.UI
+}
+
+// Run watches the server log for players joining, leaving and chatting,
+// the server for dying without a clean shutdown, and the license for
+// nearing expiry, until ctx is cancelled. Starts, stops and backups are
+// reported by the management functions themselves; see
+// management.WithNotifier. Join requests are reported by RunRequests.
+func Run(ctx context.Context, cfg *Config) error {
+	if cfg.Manager == nil || cfg.Output == nil || cfg.ServerDir == "" || cfg.Notifier == nil {
+		return errors.New("notifications need a manager, notifier, output and server directory")
+	}
+	interval := cfg.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	w := &watcher{cfg: cfg, running: management.IsServerRunning(ctx, cfg.Manager, cfg.Runner, cfg.Port)}
+
+	stream := events.NewStream(cfg.ServerDir)
+	parser, err := events.LoadParser(cfg.ServerDir)
+	if err != nil {
+		cfg.Output.Warn("Ignoring custom chat formats: %s", err)
+	}
+	stream.Parser = parser
+	evs := stream.Subscribe(ctx, events.FromEnd)
+
+	poll := time.NewTicker(interval)
+	defer poll.Stop()
+	lic := time.NewTicker(licenseCheck)
+	defer lic.Stop()
+	w.checkLicense(ctx, time.Now())
+	for {
+		select {
+		case ev, ok := <-evs:
+			if !ok {
+				return nil
+			}
+			w.handle(ctx, &ev)
+		case <-poll.C:
+			w.poll(ctx, management.IsServerRunning(ctx, cfg.Manager, cfg.Runner, cfg.Port))
+		case <-lic.C:
+			w.checkLicense(ctx, time.Now())
+		}
+	}
+}
+
+type watcher struct {
+	cfg *Config
+	// running is whether the server was up at the last poll.
+	running bool
+	// stopping is set once the log shows a clean shutdown, so the server
+	// going away afterwards isn't a crash.
+	stopping bool
+	// warned is the license expiry already announced and how close to it.
+	warned      time.Time
+	warnedLevel int
+}
+
+// handle reports joins, leaves and chat and notes what the log says
+// about the server shutting down.
+func (w *watcher) handle(ctx context.Context, ev *events.Event) {
+	switch ev.Kind {
+	case events.Join:
+		w.send(ctx, &Event{Event: Join, Player: ev.Player})
+	case events.Leave:
+		w.send(ctx, &Event{Event: Leave, Player: ev.Player})
+	case events.Chat:
+		w.send(ctx, &Event{Event: Chat, Player: ev.Player, Detail: ev.Message})
+	case events.ServerStopping:
+		w.stopping = true
+	case events.ServerReady:
+		w.stopping = false
+	}
+}
+
+// poll compares whether the server is running with the last poll. A
+// server that went away without logging its shutdown crashed.
+func (w *watcher) poll(ctx context.Context, running bool) {
+	switch {
+	case w.running && !running && !w.stopping:
+		w.send(ctx, &Event{Event: Crash})
+	case !w.running && running:
+		w.stopping = false
+	}
+	w.running = running
+}
+
+// checkLicense announces a license that expires within a week, again
+// within a day, and once it has expired.
+func (w *watcher) checkLicense(ctx context.Context, now time.Time) {
+	stored, err := license.NewManager(w.cfg.ServerDir).Load()
+	if err != nil || stored == nil || stored.CachedResponse == nil || stored.CachedResponse.LicenseKey.ExpiresAt == nil {
+		return
+	}
+	expires := *stored.CachedResponse.LicenseKey.ExpiresAt
+	if detail, level := licenseDetail(expires, now); level > 0 && (!expires.Equal(w.warned) || level > w.warnedLevel) {
+		w.warned, w.warnedLevel = expires, level
+		w.send(ctx, &Event{Event: LicenseExpiry, Detail: detail})
+	}
+}
+
+// licenseDetail describes when a license expires and how urgent that is:
+// zero for not yet worth mentioning, higher as expiry nears.
+func licenseDetail(expires, now time.Time) (string, int) {
+	left := expires.Sub(now)
+	if left <= 0 {
+		return "expired on " + expires.Local().Format("Mon Jan 2"), len(licenseWarnings) + 1
+	}
+	level := 0
+	for i, warn := range licenseWarnings {
+		if left <= warn {
+			level = i + 1
+		}
+	}
+	if level == 0 {
+		return "", 0
+	}
+	switch days := int(left.Hours() / 24); days {
+	case 0:
+		return "expires within a day", level
+	case 1:
+		return "expires in 1 day", level
+	default:
+		return fmt.Sprintf("expires in %d days", days), level
+	}
+}
+
+func (w *watcher) send(ctx context.Context, ev *Event) {
+	if err := w.cfg.Notifier.Send(ctx, ev); err != nil {
+		w.cfg.Output.Warn("Notification failed: %s", err)
+	}
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+func TestWatcherCrash(t *testing.T) {
+	h := &webhook{}
+	n := newNotifier(t, &Settings{Targets: []Target{{Type: TypeJSON, URL: serve(t, h)}}})
+	w := &watcher{cfg: &Config{Notifier: n, Output: ui.NewWriter(&bytes.Buffer{}, false)}, running: true}
+	ctx := t.Context()
+
+	// A clean shutdown is logged before the server goes away.
+	w.handle(ctx, &events.Event{Kind: events.Join, Player: "Steve"})
+	w.handle(ctx, &events.Event{Kind: events.ServerStopping})
+	w.poll(ctx, false)
+
+	// Started again, then gone without a word: a crash.
+	w.poll(ctx, true)
+	w.poll(ctx, false)
+	w.poll(ctx, false)
+
+	var got []any
+	for _, b := range h.bodies {
+		got = append(got, b["event"])
+	}
+	if len(got) != 2 || got[0] != Join || got[1] != Crash {
+		t.Errorf("events = %v, want [join crash]", got)
+	}
+}
+
+func TestLicenseDetail(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		expires   time.Time
+		want      string
+		wantLevel int
+	}{
+		{now.AddDate(0, 0, 30), "", 0},
+		{now.AddDate(0, 0, 5), "expires in 5 days", 1},
+		{now.Add(30 * time.Hour), "expires in 1 day", 1},
+		{now.Add(3 * time.Hour), "expires within a day", 2},
+		{now.AddDate(0, 0, -1), "expired on Sat Oct 17", 3},
+	}
+	for _, tt := range tests {
+		got, level := licenseDetail(tt.expires, now)
+		if got != tt.want || level != tt.wantLevel {
+			t.Errorf("licenseDetail(%v) = %q, %d; want %q, %d", tt.expires, got, level, tt.want, tt.wantLevel)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package parental
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/players"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/sessions"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// DefaultPollInterval is how often players are checked against their rules.
+const DefaultPollInterval = 15 * time.Second
+
+// warnings are how long before a player must stop they are told so.
+var warnings = []time.Duration{10 * time.Minute, 5 * time.Minute, time.Minute}
+
+// retryRemoval is when a player refused with no opening in sight goes back
+// on the whitelist to be checked again.
+const retryRemoval = 24 * time.Hour
+
+// Config configures enforcement.
+type Config struct {
+	ServerDir string
+	Manager   management.ServerManager
+	Output    *ui.UI
+	// PollInterval is how often players are checked.
+	PollInterval time.Duration
+}
+
+// Run checks players against parental.json until ctx is cancelled. The
+// rules and state are re-read on every check, so parents' edits and
+// overrides take effect without a restart.
+func Run(ctx context.Context, cfg *Config) error {
+	if cfg.Manager == nil || cfg.Output == nil || cfg.ServerDir == "" {
+		return errors.New("parental controls need a manager, output and server directory")
+	}
+	q, ok := cfg.Manager.(management.Querier)
+	if !ok {
+		return errors.New("parental controls need RCON to see who is online")
+	}
+	if _, err := Load(cfg.ServerDir); err != nil {
+		return err
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+
+	e := &enforcer{cfg: cfg, q: q, warned: make(map[string]warned)}
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+	var lastErr string
+	for {
+		err := e.check(ctx, time.Now())
+		switch {
+		case err == nil:
+			lastErr = ""
+		case err.Error() != lastErr:
+			// A broken config is reported once, not every poll.
+			lastErr = err.Error()
+			cfg.Output.Warn("Parental controls: %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// warned tracks the warnings sent ahead of one stopping time.
+type warned struct {
+	until time.Time
+	// next indexes the next warning due.
+	next int
+}
+
+type enforcer struct {
+	cfg    *Config
+	q      management.Querier
+	warned map[string]warned
+}
+
+// check applies the rules once: players whose window has reopened go back
+// on the whitelist, players near the end of their time are warned, and
+// players out of time are kicked and taken off the whitelist. The state
+// is read at the start but only what check changed is written back at the
+// end, so a parent's override made meanwhile stands.
+func (e *enforcer) check(ctx context.Context, now time.Time) error {
+	dir := e.cfg.ServerDir
+	rules, err := Load(dir)
+	if err != nil {
+		return err
+	}
+	st, err := LoadState(dir)
+	if err != nil {
+		return err
+	}
+	pruned := st.prune(now)
+	var restored []string
+	taken := make(map[string]Removal)
+	defer func() {
+		if !pruned && len(restored) == 0 && len(taken) == 0 {
+			return
+		}
+		err := UpdateState(dir, func(cur *State) error {
+			cur.prune(now)
+			for _, key := range restored {
+				delete(cur.Removed, key)
+			}
+			maps.Copy(cur.Removed, taken)
+			return nil
+		})
+		if err != nil {
+			e.cfg.Output.Warn("Parental controls: %s", err)
+		}
+	}()
+
+	list, err := management.ListPlayers(ctx, e.q)
+	if err != nil {
+		// The server is down or starting; there is no one to check.
+		return nil
+	}
+
+	all, err := sessions.Load(dir, true)
+	if err != nil {
+		return err
+	}
+	played := make(map[string]time.Duration)
+	for _, t := range sessions.Playtime(all, midnight(now), now, false) {
+		played[strings.ToLower(t.Player)] = t.Playtime
+	}
+	// Players go back on the whitelist when their window reopens, or
+	// sooner if a parent allows them or gives them more time.
+	for key, r := range st.Removed {
+		if now.Before(r.Until) && !st.Overridden(r.Player, now) {
+			if l := rules.For(r.Player); l != nil && !l.Check(now, played[key], st.ExtraTime(r.Player, now)).Allowed {
+				continue
+			}
+		}
+		if err := e.cfg.Manager.SendCommand(ctx, "whitelist add "+r.Player); err != nil {
+			continue
+		}
+		restored = append(restored, key)
+		e.record(now, r.Player, ActionRestore, "")
+	}
+
+	online := make(map[string]string, len(list.Names))
+	for _, name := range list.Names {
+		online[strings.ToLower(name)] = name
+	}
+	whitelist, err := players.LoadWhitelist(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range rules.Players() {
+		key := strings.ToLower(l.Player)
+		if st.Overridden(l.Player, now) {
+			delete(e.warned, key)
+			continue
+		}
+		status := l.Check(now, played[key], st.ExtraTime(l.Player, now))
+		name, isOnline := online[key]
+		if status.Allowed {
+			if isOnline {
+				e.warn(ctx, name, &status, now)
+			}
+			continue
+		}
+
+		delete(e.warned, key)
+		if isOnline {
+			msg := kickMessage(&status, now)
+			if err := e.cfg.Manager.SendCommand(ctx, fmt.Sprintf("kick %s %s", name, msg)); err == nil {
+				e.cfg.Output.Info("Kicked %s: %s", name, status.Reason)
+				e.record(now, name, ActionKick, status.Reason)
+			}
+		}
+		if _, removed := st.Removed[key]; removed || !whitelist.Contains(l.Player) {
+			continue
+		}
+		if err := e.cfg.Manager.SendCommand(ctx, "whitelist remove "+l.Player); err != nil {
+			continue
+		}
+		until := status.Reopens
+		if until.IsZero() {
+			until = now.Add(retryRemoval)
+		}
+		taken[key] = Removal{Player: l.Player, Reason: status.Reason, Until: until}
+		e.record(now, l.Player, ActionRemove, fmt.Sprintf("%s until %s", status.Reason, until.Format("Mon Jan 2 15:04")))
+	}
+	return nil
+}
+
+// warn tells an allowed player their time is running out when the time
+// left first drops below each warning. A player who joins with three
+// minutes left gets one warning, not three.
+func (e *enforcer) warn(ctx context.Context, player string, status *Status, now time.Time) {
+	key := strings.ToLower(player)
+	if status.Until.IsZero() {
+		delete(e.warned, key)
+		return
+	}
+	w := e.warned[key]
+	if !w.until.Equal(status.Until) {
+		w = warned{until: status.Until}
+	}
+	left := status.Until.Sub(now)
+	due := false
+	for w.next < len(warnings) && left <= warnings[w.next] {
+		w.next++
+		due = true
+	}
+	e.warned[key] = w
+	if !due || left <= 0 {
+		return
+	}
+
+	minutes := int((left + time.Minute - 1) / time.Minute)
+	msg := warnMessage(status.Reason, minutes)
+	_ = e.cfg.Manager.SendCommand(ctx, management.Tellraw(player, management.Text{Text: msg, Color: "gold"}))
+	e.record(now, player, ActionWarn, fmt.Sprintf("%s in %s", status.Reason, plural(minutes, "minute")))
+}
+
+// record logs an action, reporting a failure to write it.
+func (e *enforcer) record(now time.Time, player, action, detail string) {
+	if err := AppendLog(e.cfg.ServerDir, &Action{Time: now, Player: player, Action: action, Detail: detail}); err != nil {
+		e.cfg.Output.Warn("Parental controls: %s", err)
+	}
+}
+
+// warnMessage tells a player they must stop in minutes.
+func warnMessage(reason string, minutes int) string {
+	left := plural(minutes, "minute")
+	switch reason {
+	case ReasonDailyLimit:
+		return fmt.Sprintf("You have %s of playtime left today", left)
+	case ReasonCurfew:
+		return fmt.Sprintf("School-night curfew in %s — time to wrap up", left)
+	default:
+		return fmt.Sprintf("Playtime ends in %s — time to wrap up", left)
+	}
+}
+
+// kickMessage tells a refused player why and when they may play again.
+func kickMessage(status *Status, now time.Time) string {
+	var msg string
+	switch status.Reason {
+	case ReasonDailyLimit:
+		msg = "That's all your playtime for today."
+	case ReasonCurfew:
+		msg = "It's a school night — time for bed!"
+	default:
+		msg = "It's outside your playtime hours."
+	}
+	if status.Reopens.IsZero() {
+		return msg + " Ask a parent for more time."
+	}
+	return fmt.Sprintf("%s You can play again %s.", msg, When(status.Reopens, now))
+}
+
+// When describes t relative to now: "at 15:00", "tomorrow at 08:00" or
+// "Sat at 08:00".
+func When(t, now time.Time) string {
+	days := int(midnight(t).Sub(midnight(now)).Hours()+12) / 24
+	clock := t.Format("15:04")
+	switch {
+	case days == 0:
+		return "at " + clock
+	case days == 1:
+		return "tomorrow at " + clock
+	case days < 7:
+		return t.Format("Mon") + " at " + clock
+	default:
+		return t.Format("Mon Jan 2") + " at " + clock
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,183 @@
+package parental
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// listing returns a manager that answers "list" with reply.
+func listing(reply string) *management.MockManager {
+	mgr := management.NewMockManager()
+	mgr.Replies["list"] = reply
+	return mgr
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func hasCommand(cmds []string, prefix string) bool {
+	return slices.ContainsFunc(cmds, func(c string) bool { return strings.HasPrefix(c, prefix) })
+}
+
+func TestEnforcerCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ConfigFile, `{
+  "players": {
+    "Steve": {"daily_minutes": 60},
+    "Alex": {"daily_minutes": 30},
+    "Kid": {"hours": {"mon": "none"}}
+  }
+}`)
+	writeFile(t, dir, "whitelist.json", `[{"uuid": "1", "name": "Steve"}, {"uuid": "2", "name": "Alex"}, {"uuid": "3", "name": "Kid"}]`)
+	writeFile(t, dir, "logs/latest.log", `[16:00:00] [Server thread/INFO]: Steve joined the game
+[16:10:00] [Server thread/INFO]: Alex joined the game
+`)
+	// 2026-10-19 is a Monday.
+	now := at("2026-10-19", "16:50")
+	if err := os.Chtimes(filepath.Join(dir, "logs", "latest.log"), now, now); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := listing("There are 2 of a max of 20 players online: Steve, Alex")
+	e := &enforcer{cfg: &Config{ServerDir: dir, Manager: mgr, Output: ui.NewWriter(&bytes.Buffer{}, false)}, q: mgr, warned: make(map[string]warned)}
+
+	// Steve has ten minutes left; Alex is out of time; Kid may not play
+	// on Mondays and is taken off the whitelist though offline.
+	if err := e.check(t.Context(), now); err != nil {
+		t.Fatal(err)
+	}
+	cmds := mgr.Take()
+	for _, want := range []string{`tellraw Steve ["",{"text":"You have 10 minutes`, "kick Alex That's all your playtime", "whitelist remove Alex", "whitelist remove Kid"} {
+		if !hasCommand(cmds, want) {
+			t.Errorf("first check sent %q, want %q", cmds, want)
+		}
+	}
+	if hasCommand(cmds, "kick Kid") || hasCommand(cmds, "kick Steve") {
+		t.Errorf("first check sent %q, want only Alex kicked", cmds)
+	}
+
+	// A minute on there's nothing new to warn about, and Alex, kicked
+	// again after rejoining, isn't removed twice.
+	if err := e.check(t.Context(), now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	cmds = mgr.Take()
+	if hasCommand(cmds, "tellraw") || hasCommand(cmds, "whitelist") {
+		t.Errorf("second check sent %q, want only a kick", cmds)
+	}
+
+	// At five minutes left Steve is warned again.
+	if err := e.check(t.Context(), now.Add(5*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if cmds = mgr.Take(); !hasCommand(cmds, `tellraw Steve ["",{"text":"You have 5 minutes`) {
+		t.Errorf("third check sent %q, want a five minute warning", cmds)
+	}
+
+	// A parent lets Alex play on, which puts them back on the whitelist.
+	st, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := st.Removed["alex"]; !ok || !r.Until.Equal(at("2026-10-20", "00:00")) {
+		t.Errorf("Alex's removal = %+v, want until midnight", r)
+	}
+	st.Allow("Alex", now.Add(time.Hour))
+	if err := st.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.check(t.Context(), now.Add(6*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	cmds = mgr.Take()
+	if !hasCommand(cmds, "whitelist add Alex") || hasCommand(cmds, "kick Alex") {
+		t.Errorf("check after override sent %q, want Alex restored and not kicked", cmds)
+	}
+
+	actions, err := LoadLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, a := range actions {
+		kinds = append(kinds, a.Player+" "+a.Action)
+	}
+	want := []string{
+		"Alex kick", "Alex remove", "Kid remove", "Steve warn",
+		"Alex kick",
+		"Alex kick", "Steve warn",
+		"Alex restore",
+	}
+	if !slices.Equal(kinds, want) {
+		t.Errorf("log = %q, want %q", kinds, want)
+	}
+}
+
+func TestEnforcerCheckKeepsParentsChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ConfigFile, `{"players": {"Alex": {"hours": {"mon": "none"}}, "Kid": {"hours": {"mon": "none"}}}}`)
+	writeFile(t, dir, "whitelist.json", `[{"uuid": "2", "name": "Alex"}, {"uuid": "3", "name": "Kid"}]`)
+	now := at("2026-10-19", "16:00")
+
+	// A parent lets Kid play while check is busy kicking Alex.
+	mgr := listing("There are 1 of a max of 20 players online: Alex")
+	mgr.OnCommand = func(cmd string) {
+		if strings.HasPrefix(cmd, "kick Alex") {
+			if err := UpdateState(dir, func(st *State) error {
+				st.Allow("Kid", now.Add(time.Hour))
+				return nil
+			}); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+	e := &enforcer{cfg: &Config{ServerDir: dir, Manager: mgr, Output: ui.NewWriter(&bytes.Buffer{}, false)}, q: mgr, warned: make(map[string]warned)}
+	if err := e.check(t.Context(), now); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.Overridden("Kid", now) {
+		t.Error("the parent's override was lost")
+	}
+	if _, ok := st.Held("Alex"); !ok {
+		t.Error("Alex's removal wasn't saved")
+	}
+}
+
+func TestWhen(t *testing.T) {
+	now := at("2026-10-19", "16:00")
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{at("2026-10-19", "20:00"), "at 20:00"},
+		{at("2026-10-20", "15:00"), "tomorrow at 15:00"},
+		{at("2026-10-24", "08:00"), "Sat at 08:00"},
+		{at("2026-10-27", "08:00"), "Tue Oct 27 at 08:00"},
+	}
+	for _, tt := range tests {
+		if got := When(tt.t, now); got != tt.want {
+			t.Errorf("When(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,433 @@
+// Package parental enforces playtime rules for players: a daily limit,
+// allowed hours per weekday and a school-night curfew. The daemon warns
+// players before their time runs out, kicks them when it does, and keeps
+// them off the whitelist until they may play again.
+package parental
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ConfigFile holds the rules in the server directory.
+const ConfigFile = "parental.json"
+
+// Settings is the on-disk form of ConfigFile:
+//
+//	{
+//	  "school_days": "mon-fri",
+//	  "groups": {
+//	    "kids": {
+//	      "players": ["Steve", "Alex"],
+//	      "daily_minutes": 90,
+//	      "hours": {"mon-fri": "15:00-20:00", "sat,sun": "08:00-21:00"},
+//	      "school_night_curfew": "20:30-07:00"
+//	    }
+//	  },
+//	  "players": {"Alex": {"daily_minutes": 60}}
+//	}
+type Settings struct {
+	// SchoolDays are the days with school the next morning's curfew
+	// protects; default "mon-fri".
+	SchoolDays string           `json:"school_days,omitempty"`
+	Groups     map[string]Group `json:"groups,omitempty"`
+	// Players holds rules for single players. Their settings replace
+	// those of the player's group.
+	Players map[string]Rule `json:"players,omitempty"`
+}
+
+// Group applies one rule to several players.
+type Group struct {
+	Players []string `json:"players"`
+	Rule
+}
+
+// Rule limits when and how long a player may play. Unset fields don't
+// limit anything.
+type Rule struct {
+	// DailyMinutes is the most a player may play per day.
+	DailyMinutes int `json:"daily_minutes,omitempty"`
+	// Hours maps days ("mon", "mon-fri", "sat,sun", "weekdays",
+	// "weekends", "daily") to the times play is allowed on them:
+	// "15:00-20:00", several separated by commas, or "none". Days not
+	// listed have no hours limit.
+	Hours map[string]string `json:"hours,omitempty"`
+	// SchoolNightCurfew stops play on nights before a school day, such as
+	// "20:30-07:00": from 20:30 until 07:00 the next morning.
+	SchoolNightCurfew string `json:"school_night_curfew,omitempty"`
+}
+
+// Reasons a player must stop playing.
+const (
+	ReasonDailyLimit = "daily limit"
+	ReasonHours      = "outside allowed hours"
+	ReasonCurfew     = "school-night curfew"
+)
+
+// window is a span of a day in minutes since midnight, [start, end).
+type window struct {
+	start, end int
+}
+
+// Limits is a player's compiled rule.
+type Limits struct {
+	// Player is the name as written in the config.
+	Player string
+	// Group is the player's group, if any.
+	Group string
+	Daily time.Duration
+	// hours holds the allowed windows per weekday; days without a limit
+	// are nil, and days with no play allowed are empty.
+	hours  [7][]window
+	curfew *window
+	school [7]bool
+}
+
+// Rules holds the limits for every player with a rule.
+type Rules struct {
+	byPlayer map[string]*Limits
+}
+
+// Load reads the rules from serverDir. A missing file means no rules.
+func Load(serverDir string) (*Rules, error) {
+	var cfg Settings
+	data, err := os.ReadFile(filepath.Join(serverDir, ConfigFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading %s: %w", ConfigFile, err)
+	default:
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", ConfigFile, err)
+		}
+	}
+	rules, err := cfg.Compile()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFile, err)
+	}
+	return rules, nil
+}
+
+// Compile checks the config and works out each player's limits.
+func (c *Settings) Compile() (*Rules, error) {
+	schoolSpec := c.SchoolDays
+	if schoolSpec == "" {
+		schoolSpec = "mon-fri"
+	}
+	school, err := parseDays(schoolSpec)
+	if err != nil {
+		return nil, fmt.Errorf("school_days: %w", err)
+	}
+
+	type entry struct {
+		name, group string
+		rule        Rule
+	}
+	entries := make(map[string]*entry)
+	for _, g := range slices.Sorted(maps.Keys(c.Groups)) {
+		for _, p := range c.Groups[g].Players {
+			key := strings.ToLower(p)
+			if e, ok := entries[key]; ok {
+				return nil, fmt.Errorf("%s is in groups %q and %q", p, e.group, g)
+			}
+			entries[key] = &entry{name: p, group: g, rule: c.Groups[g].Rule}
+		}
+	}
+	for _, p := range slices.Sorted(maps.Keys(c.Players)) {
+		key := strings.ToLower(p)
+		e, ok := entries[key]
+		if !ok {
+			e = &entry{name: p}
+			entries[key] = e
+		}
+		e.name = p
+		e.rule = e.rule.merge(c.Players[p])
+	}
+
+	r := &Rules{byPlayer: make(map[string]*Limits, len(entries))}
+	for key, e := range entries {
+		l, err := e.rule.compile()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.name, err)
+		}
+		l.Player, l.Group, l.school = e.name, e.group, school
+		r.byPlayer[key] = l
+	}
+	return r, nil
+}
+
+// For returns the limits on the named player, or nil if they have none.
+func (r *Rules) For(player string) *Limits {
+	return r.byPlayer[strings.ToLower(player)]
+}
+
+// Players returns everyone with a rule, sorted by name.
+func (r *Rules) Players() []*Limits {
+	out := make([]*Limits, 0, len(r.byPlayer))
+	for _, l := range r.byPlayer {
+		out = append(out, l)
+	}
+	slices.SortFunc(out, func(a, b *Limits) int { return strings.Compare(strings.ToLower(a.Player), strings.ToLower(b.Player)) })
+	return out
+}
+
+// merge returns r with the fields set in o replacing its own.
+func (r Rule) merge(o Rule) Rule { //nolint:gocritic // returns a modified copy
+	if o.DailyMinutes != 0 {
+		r.DailyMinutes = o.DailyMinutes
+	}
+	if o.Hours != nil {
+		r.Hours = o.Hours
+	}
+	if o.SchoolNightCurfew != "" {
+		r.SchoolNightCurfew = o.SchoolNightCurfew
+	}
+	return r
+}
+
+func (r *Rule) compile() (*Limits, error) {
+	if r.DailyMinutes < 0 {
+		return nil, fmt.Errorf("daily_minutes must not be negative")
+	}
+	l := &Limits{Daily: time.Duration(r.DailyMinutes) * time.Minute}
+	for _, spec := range slices.Sorted(maps.Keys(r.Hours)) {
+		days, err := parseDays(spec)
+		if err != nil {
+			return nil, fmt.Errorf("hours: %w", err)
+		}
+		windows, err := parseWindows(r.Hours[spec])
+		if err != nil {
+			return nil, fmt.Errorf("hours %q: %w", spec, err)
+		}
+		for d, ok := range days {
+			if !ok {
+				continue
+			}
+			if l.hours[d] != nil {
+				return nil, fmt.Errorf("hours: %s is listed twice", time.Weekday(d))
+			}
+			l.hours[d] = windows
+		}
+	}
+	if r.SchoolNightCurfew != "" {
+		w, err := parseSpan(r.SchoolNightCurfew, true)
+		if err != nil {
+			return nil, fmt.Errorf("school_night_curfew: %w", err)
+		}
+		l.curfew = &w
+	}
+	return l, nil
+}
+
+// dayNames are the weekday abbreviations, indexed by time.Weekday.
+var dayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// parseDays reads a list of days: "mon", "mon-fri", "sat,sun", "weekdays",
+// "weekends" or "daily".
+func parseDays(spec string) ([7]bool, error) {
+	var days [7]bool
+	for part := range strings.SplitSeq(strings.ToLower(spec), ",") {
+		part = strings.TrimSpace(part)
+		switch part {
+		case "daily", "all":
+			part = "sun-sat"
+		case "weekdays":
+			part = "mon-fri"
+		case "weekends":
+			part = "sat-sun"
+		}
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := dayIndex(from)
+		if !ok {
+			return days, fmt.Errorf("unknown day %q: use mon, tue, ..., sun", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = dayIndex(to); !ok {
+				return days, fmt.Errorf("unknown day %q: use mon, tue, ..., sun", to)
+			}
+		}
+		// Ranges may wrap round the week: "fri-mon".
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// dayIndex accepts a day's name or its first three letters.
+func dayIndex(s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	if len(s) < 3 {
+		return 0, false
+	}
+	i := slices.Index(dayNames, s[:3])
+	if i < 0 || !strings.HasPrefix(strings.ToLower(time.Weekday(i).String()), s) {
+		return 0, false
+	}
+	return i, true
+}
+
+// parseWindows reads "15:00-20:00", several separated by commas, or
+// "none".
+func parseWindows(s string) ([]window, error) {
+	windows := []window{}
+	if strings.EqualFold(strings.TrimSpace(s), "none") {
+		return windows, nil
+	}
+	for part := range strings.SplitSeq(s, ",") {
+		w, err := parseSpan(part, false)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// parseSpan reads "HH:MM-HH:MM". Only overnight spans may end before they
+// start.
+func parseSpan(s string, overnight bool) (window, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return window{}, fmt.Errorf("%q: use HH:MM-HH:MM", s)
+	}
+	start, err1 := parseClock(from)
+	end, err2 := parseClock(to)
+	if err1 != nil || err2 != nil {
+		return window{}, fmt.Errorf("%q: use HH:MM-HH:MM", s)
+	}
+	if start == end || (end < start && !overnight) {
+		return window{}, fmt.Errorf("%q: the end must be after the start", s)
+	}
+	return window{start, end}, nil
+}
+
+// parseClock reads "HH:MM" as minutes since midnight. "24:00" is the end
+// of the day.
+func parseClock(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// open reports whether the rule's hours and curfew allow play at t, and if
+// not, why.
+func (l *Limits) open(t time.Time) (bool, string) {
+	day := t.Weekday()
+	minute := t.Hour()*60 + t.Minute()
+	if c := l.curfew; c != nil {
+		tomorrow := (day + 1) % 7
+		var inCurfew bool
+		if c.end < c.start {
+			inCurfew = (minute >= c.start && l.school[tomorrow]) || (minute < c.end && l.school[day])
+		} else {
+			inCurfew = minute >= c.start && minute < c.end && l.school[tomorrow]
+		}
+		if inCurfew {
+			return false, ReasonCurfew
+		}
+	}
+	if windows := l.hours[day]; windows != nil {
+		if !slices.ContainsFunc(windows, func(w window) bool { return minute >= w.start && minute < w.end }) {
+			return false, ReasonHours
+		}
+	}
+	return true, ""
+}
+
+// Status is what a player's limits allow at a moment.
+type Status struct {
+	Allowed bool
+	// Reason is why a refused player may not play, or what will stop an
+	// allowed one at Until.
+	Reason string
+	// Until is when an allowed player must stop, or zero if nothing
+	// stops them within a day.
+	Until time.Time
+	// Reopens is when a refused player may play again, or zero if not
+	// within a week.
+	Reopens time.Time
+	// Left is the rest of the daily allowance, if there is one.
+	Left time.Duration
+}
+
+// scanLimit is how far ahead Check looks for the next change.
+const scanLimit = 8 * 24 * time.Hour
+
+// Check returns what the limits allow at now for a player who has played
+// for played today, with extra minutes granted on top of the daily limit.
+func (l *Limits) Check(now time.Time, played, extra time.Duration) Status {
+	var st Status
+	limited := l.Daily > 0
+	if limited {
+		st.Left = max(l.Daily+extra-played, 0)
+	}
+	if ok, reason := l.open(now); !ok {
+		st.Reason = reason
+		st.Reopens = l.nextOpen(now)
+		return st
+	}
+	if limited && st.Left <= 0 {
+		st.Reason = ReasonDailyLimit
+		st.Reopens = l.nextOpen(midnight(now).AddDate(0, 0, 1))
+		return st
+	}
+
+	st.Allowed = true
+	st.Until, st.Reason = l.closes(now)
+	if limited {
+		if end := now.Add(st.Left); st.Until.IsZero() || end.Before(st.Until) {
+			st.Until, st.Reason = end, ReasonDailyLimit
+		}
+	}
+	return st
+}
+
+// closes returns the next minute within a day at which play stops being
+// allowed, and why.
+func (l *Limits) closes(now time.Time) (time.Time, string) {
+	start := now.Truncate(time.Minute).Add(time.Minute)
+	for t := start; t.Sub(now) <= 24*time.Hour; t = t.Add(time.Minute) {
+		if ok, reason := l.open(t); !ok {
+			return t, reason
+		}
+	}
+	return time.Time{}, ""
+}
+
+// nextOpen returns the first minute from from on when play is allowed.
+func (l *Limits) nextOpen(from time.Time) time.Time {
+	t := from.Truncate(time.Minute)
+	if t.Before(from) {
+		t = t.Add(time.Minute)
+	}
+	for end := from.Add(scanLimit); t.Before(end); t = t.Add(time.Minute) {
+		if ok, _ := l.open(t); ok {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// midnight returns the midnight that starts t's day.
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
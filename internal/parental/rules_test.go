@@ -0,0 +1,200 @@
+package parental
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func at(day, clock string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", day+" "+clock, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseDays(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // days as initials from Sunday, "" for an error
+	}{
+		{"mon", "-M-----"},
+		{"mon-fri", "-MTWTF-"},
+		{"weekdays", "-MTWTF-"},
+		{"weekends", "S-----S"},
+		{"sat,sun", "S-----S"},
+		{"fri-mon", "SM---FS"},
+		{"Daily", "SMTWTFS"},
+		{"Tuesday, thurs", "--T-T--"},
+		{"funday", ""},
+		{"mo", ""},
+		{"mon-", ""},
+	}
+	for _, tt := range tests {
+		days, err := parseDays(tt.in)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("parseDays(%q) = %v, want error", tt.in, days)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseDays(%q): %v", tt.in, err)
+			continue
+		}
+		got := []byte("-------")
+		for d, ok := range days {
+			if ok {
+				got[d] = "SMTWTFS"[d]
+			}
+		}
+		if string(got) != tt.want {
+			t.Errorf("parseDays(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := map[string]Settings{
+		"two groups": {Groups: map[string]Group{
+			"a": {Players: []string{"Steve"}},
+			"b": {Players: []string{"steve"}},
+		}},
+		"negative limit":  {Players: map[string]Rule{"Steve": {DailyMinutes: -5}}},
+		"bad day":         {Players: map[string]Rule{"Steve": {Hours: map[string]string{"someday": "10:00-11:00"}}}},
+		"backwards hours": {Players: map[string]Rule{"Steve": {Hours: map[string]string{"mon": "20:00-10:00"}}}},
+		"day twice": {Players: map[string]Rule{"Steve": {Hours: map[string]string{
+			"mon-fri": "15:00-20:00",
+			"fri":     "15:00-22:00",
+		}}}},
+		"bad curfew":      {Players: map[string]Rule{"Steve": {SchoolNightCurfew: "bedtime"}}},
+		"bad school days": {SchoolDays: "term time"},
+	}
+	for name, cfg := range tests {
+		if _, err := cfg.Compile(); err == nil {
+			t.Errorf("%s: Compile() succeeded, want error", name)
+		}
+	}
+}
+
+func TestLoadMergesPlayerOverGroup(t *testing.T) {
+	dir := t.TempDir()
+	cfg := `{
+  "groups": {"kids": {"players": ["Steve", "Alex"], "daily_minutes": 90, "school_night_curfew": "20:30-07:00"}},
+  "players": {"alex": {"daily_minutes": 60}, "Dad": {"hours": {"daily": "none"}}}
+}`
+	if err := os.WriteFile(filepath.Join(dir, ConfigFile), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(rules.Players()); n != 3 {
+		t.Fatalf("Players() has %d entries, want 3", n)
+	}
+	alex := rules.For("ALEX")
+	if alex == nil || alex.Daily != time.Hour || alex.Group != "kids" || alex.curfew == nil {
+		t.Errorf("For(ALEX) = %+v, want kids with a 60 minute limit and the group's curfew", alex)
+	}
+	if steve := rules.For("Steve"); steve == nil || steve.Daily != 90*time.Minute {
+		t.Errorf("For(Steve) = %+v, want a 90 minute limit", steve)
+	}
+	if rules.For("Herobrine") != nil {
+		t.Error("For(Herobrine) has limits, want none")
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	rules, err := Load(t.TempDir())
+	if err != nil || len(rules.Players()) != 0 {
+		t.Errorf("Load() with no file = %v, %v; want no rules", rules, err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cfg := Settings{Players: map[string]Rule{"Steve": {
+		DailyMinutes:      60,
+		Hours:             map[string]string{"mon-fri": "15:00-20:00", "weekends": "08:00-21:00"},
+		SchoolNightCurfew: "20:30-07:00",
+	}}}
+	rules, err := cfg.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := rules.For("Steve")
+
+	// 2026-10-17 is a Saturday, the 18th a Sunday and the 19th a Monday.
+	tests := []struct {
+		name          string
+		now           time.Time
+		played, extra time.Duration
+		want          Status
+	}{
+		{
+			name: "plenty of time",
+			now:  at("2026-10-19", "16:00"),
+			want: Status{Allowed: true, Reason: ReasonDailyLimit, Until: at("2026-10-19", "17:00"), Left: time.Hour},
+		},
+		{
+			name:   "hours end first",
+			now:    at("2026-10-19", "19:30"),
+			played: 10 * time.Minute,
+			want:   Status{Allowed: true, Reason: ReasonHours, Until: at("2026-10-19", "20:00"), Left: 50 * time.Minute},
+		},
+		{
+			name: "before hours",
+			now:  at("2026-10-19", "14:00"),
+			want: Status{Reason: ReasonHours, Reopens: at("2026-10-19", "15:00"), Left: time.Hour},
+		},
+		{
+			name:   "out of time",
+			now:    at("2026-10-19", "16:00"),
+			played: time.Hour,
+			want:   Status{Reason: ReasonDailyLimit, Reopens: at("2026-10-20", "15:00")},
+		},
+		{
+			name:   "extra time",
+			now:    at("2026-10-19", "16:00"),
+			played: time.Hour,
+			extra:  30 * time.Minute,
+			want:   Status{Allowed: true, Reason: ReasonDailyLimit, Until: at("2026-10-19", "16:30"), Left: 30 * time.Minute},
+		},
+		{
+			name: "curfew before a school day",
+			now:  at("2026-10-18", "20:00"),
+			want: Status{Allowed: true, Reason: ReasonCurfew, Until: at("2026-10-18", "20:30"), Left: time.Hour},
+		},
+		{
+			name: "in curfew",
+			now:  at("2026-10-18", "20:45"),
+			want: Status{Reason: ReasonCurfew, Reopens: at("2026-10-19", "15:00"), Left: time.Hour},
+		},
+		{
+			name: "no curfew before a weekend",
+			now:  at("2026-10-17", "20:45"),
+			want: Status{Allowed: true, Reason: ReasonHours, Until: at("2026-10-17", "21:00"), Left: time.Hour},
+		},
+	}
+	for _, tt := range tests {
+		got := l.Check(tt.now, tt.played, tt.extra)
+		if got.Allowed != tt.want.Allowed || got.Reason != tt.want.Reason || !got.Until.Equal(tt.want.Until) ||
+			!got.Reopens.Equal(tt.want.Reopens) || got.Left != tt.want.Left {
+			t.Errorf("%s: Check() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckNoPlayAllowed(t *testing.T) {
+	cfg := Settings{Players: map[string]Rule{"Steve": {Hours: map[string]string{"daily": "none"}}}}
+	rules, err := cfg.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := rules.For("Steve").Check(at("2026-10-19", "12:00"), 0, 0)
+	if got.Allowed || got.Reason != ReasonHours || !got.Reopens.IsZero() {
+		t.Errorf("Check() = %+v, want refused with no reopening", got)
+	}
+}
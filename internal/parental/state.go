@@ -0,0 +1,250 @@
+package parental
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+)
+
+// Files in the server directory.
+const (
+	// StateFile holds parents' overrides and the players taken off the
+	// whitelist.
+	StateFile = "parental-state.json"
+	// LogFile is the JSON-lines record of everything enforced.
+	LogFile = "parental-log.jsonl"
+)
+
+// stateLock is held while the state is changed, so the daemon's
+// enforcement and parents' overrides don't undo each other.
+const stateLock = StateFile + ".lock"
+
+// State is what the daemon and parents have changed. Maps are keyed by
+// lower-cased player name.
+type State struct {
+	// Overrides let a player play regardless of their rule until a time.
+	Overrides map[string]Override `json:"overrides,omitempty"`
+	// Extra is extra time on top of today's daily limit.
+	Extra map[string]Extra `json:"extra,omitempty"`
+	// Removed are players taken off the whitelist and when to put them
+	// back.
+	Removed map[string]Removal `json:"removed,omitempty"`
+}
+
+// Override lets Player play regardless of their rule until Until.
+type Override struct {
+	Player string    `json:"player"`
+	Until  time.Time `json:"until"`
+}
+
+// Extra is extra playtime for Player on Day (YYYY-MM-DD).
+type Extra struct {
+	Player  string `json:"player"`
+	Day     string `json:"day"`
+	Minutes int    `json:"minutes"`
+}
+
+// Removal records a player taken off the whitelist.
+type Removal struct {
+	Player string `json:"player"`
+	Reason string `json:"reason"`
+	// Until is when the player goes back on the whitelist.
+	Until time.Time `json:"until"`
+}
+
+// LoadState reads the state from serverDir. A missing file gives an empty
+// state.
+func LoadState(serverDir string) (*State, error) {
+	st := &State{}
+	data, err := os.ReadFile(filepath.Join(serverDir, StateFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading %s: %w", StateFile, err)
+	default:
+		if err := json.Unmarshal(data, st); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", StateFile, err)
+		}
+	}
+	if st.Overrides == nil {
+		st.Overrides = make(map[string]Override)
+	}
+	if st.Extra == nil {
+		st.Extra = make(map[string]Extra)
+	}
+	if st.Removed == nil {
+		st.Removed = make(map[string]Removal)
+	}
+	return st, nil
+}
+
+// UpdateState loads serverDir's state, lets update change it and saves
+// it, holding the state's lock throughout. Nothing is saved if update
+// returns an error.
+func UpdateState(serverDir string, update func(*State) error) error {
+	unlock, err := platform.LockFile(filepath.Join(serverDir, stateLock))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	st, err := LoadState(serverDir)
+	if err != nil {
+		return err
+	}
+	if err := update(st); err != nil {
+		return err
+	}
+	return st.Save(serverDir)
+}
+
+// Save writes the state to serverDir. It is replaced whole, so a reader
+// never sees it half written; use UpdateState to change it.
+func (s *State) Save(serverDir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", StateFile, err)
+	}
+	if err := platform.WriteFileAtomic(filepath.Join(serverDir, StateFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", StateFile, err)
+	}
+	return nil
+}
+
+// Overridden reports whether player has an override in force at now.
+func (s *State) Overridden(player string, now time.Time) bool {
+	o, ok := s.Overrides[strings.ToLower(player)]
+	return ok && now.Before(o.Until)
+}
+
+// ExtraTime returns the extra time player has been given for now's day.
+func (s *State) ExtraTime(player string, now time.Time) time.Duration {
+	e, ok := s.Extra[strings.ToLower(player)]
+	if !ok || e.Day != now.Format(time.DateOnly) {
+		return 0
+	}
+	return time.Duration(e.Minutes) * time.Minute
+}
+
+// Held returns player's removal if the daemon has taken them off the
+// whitelist.
+func (s *State) Held(player string) (Removal, bool) {
+	r, ok := s.Removed[strings.ToLower(player)]
+	return r, ok
+}
+
+// Allow lets player play regardless of their rule until until.
+func (s *State) Allow(player string, until time.Time) {
+	s.Overrides[strings.ToLower(player)] = Override{Player: player, Until: until}
+}
+
+// Extend adds extra time to player's limit for now's day.
+func (s *State) Extend(player string, d time.Duration, now time.Time) {
+	key := strings.ToLower(player)
+	e := Extra{Player: player, Day: now.Format(time.DateOnly)}
+	if old, ok := s.Extra[key]; ok && old.Day == e.Day {
+		e.Minutes = old.Minutes
+	}
+	e.Minutes += int(d.Minutes())
+	s.Extra[key] = e
+}
+
+// Revoke drops player's override and extra time. It reports whether there
+// was anything to drop.
+func (s *State) Revoke(player string) bool {
+	key := strings.ToLower(player)
+	_, hadOverride := s.Overrides[key]
+	_, hadExtra := s.Extra[key]
+	delete(s.Overrides, key)
+	delete(s.Extra, key)
+	return hadOverride || hadExtra
+}
+
+// prune drops overrides and extra time that have run out.
+func (s *State) prune(now time.Time) bool {
+	changed := false
+	for key, o := range s.Overrides {
+		if !now.Before(o.Until) {
+			delete(s.Overrides, key)
+			changed = true
+		}
+	}
+	today := now.Format(time.DateOnly)
+	for key, e := range s.Extra {
+		if e.Day != today {
+			delete(s.Extra, key)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Actions in the enforcement log.
+const (
+	ActionWarn    = "warn"
+	ActionKick    = "kick"
+	ActionRemove  = "remove"
+	ActionRestore = "restore"
+	ActionAllow   = "allow"
+	ActionExtend  = "extend"
+	ActionRevoke  = "revoke"
+)
+
+// Action is one entry in the enforcement log.
+type Action struct {
+	Time   time.Time `json:"time"`
+	Player string    `json:"player"`
+	Action string    `json:"action"`
+	// Detail says why, or what a parent granted.
+	Detail string `json:"detail,omitempty"`
+}
+
+// AppendLog adds a to the enforcement log in serverDir.
+func AppendLog(serverDir string, a *Action) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("encoding parental action: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(serverDir, LogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", LogFile, err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %s: %w", LogFile, err)
+	}
+	return f.Close()
+}
+
+// LoadLog reads the enforcement log in serverDir, oldest first. A missing
+// log has no actions; malformed lines are skipped.
+func LoadLog(serverDir string) ([]Action, error) {
+	f, err := os.Open(filepath.Join(serverDir, LogFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", LogFile, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var actions []Action
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var a Action
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil {
+			continue
+		}
+		actions = append(actions, a)
+	}
+	if err := scanner.Err(); err != nil {
+		return actions, fmt.Errorf("reading %s: %w", LogFile, err)
+	}
+	return actions, nil
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
 )
@@ -54,3 +57,211 @@ func SetupCronBackup(ctx context.Context, runner CommandRunner, serverDir string
 	output.Success("Daily backup scheduled at 4:00 AM")
 	return nil
 }
+
+// CronJob is an mc-dad-server job in the user's crontab.
+type CronJob struct {
+	// Name is the subcommand or script the job runs, e.g. "backup".
+	Name     string
+	Schedule string
+	Command  string
+
+	spec cronSpec
+}
+
+// ScheduledJobs returns the crontab entries that run mc-dad-server. Entries with schedules it cannot interpret, and @reboot jobs,
+// are skipped.
+func ScheduledJobs(ctx context.Context, runner CommandRunner) ([]CronJob, error) {
+	out, err := runner.RunWithOutput(ctx, "crontab", "-l")
+	if err != nil {
+		return nil, fmt.Errorf("reading crontab: %w", err)
+	}
+	return parseCrontab(string(out)), nil
+}
+
+func parseCrontab(crontab string) []CronJob {
+	var jobs []CronJob
+	for line := range strings.Lines(crontab) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		n := 5
+		if strings.HasPrefix(fields[0], "@") {
+			n = 1
+		}
+		if len(fields) <= n {
+			// Too short, or an environment assignment like MAILTO=.
+			continue
+		}
+		schedule := strings.Join(fields[:n], " ")
+		name, ok := jobName(fields[n:])
+		if !ok {
+			continue
+		}
+		spec, err := parseCronSpec(schedule)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, CronJob{
+			Name:     name,
+			Schedule: schedule,
+			Command:  strings.Join(fields[n:], " "),
+			spec:     spec,
+		})
+	}
+	return jobs
+}
+
+// jobName picks out the subcommand a crontab command runs: the first
+// argument after the mc-dad-server binary that isn't a global flag.
+func jobName(command []string) (string, bool) {
+	i := slices.IndexFunc(command, func(f string) bool {
+		return filepath.Base(f) == "mc-dad-server"
+	})
+	if i < 0 {
+		return "", false
+	}
+	for j := i + 1; j < len(command); j++ {
+		switch arg := command[j]; {
+		case strings.HasPrefix(arg, "--") && strings.Contains(arg, "="):
+		case strings.HasPrefix(arg, "-"):
+			// Every global flag takes a value.
+			j++
+		default:
+			return arg, true
+		}
+	}
+	return "", false
+}
+
+// Next returns the first time after t that the job runs.
+func (j *CronJob) Next(t time.Time) time.Time {
+	return j.spec.next(t)
+}
+
+// cronSpec is a parsed five-field cron schedule, each field a set of
+// allowed values.
+type cronSpec struct {
+	minute, hour, dom, month, dow [64]bool
+	// domStar and dowStar record unrestricted day fields: when both day
+	// fields are restricted, cron runs on days matching either.
+	domStar, dowStar bool
+}
+
+// cronMacros are the @-schedules cron accepts besides @reboot.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func parseCronSpec(schedule string) (cronSpec, error) {
+	if strings.HasPrefix(schedule, "@") {
+		expanded, ok := cronMacros[schedule]
+		if !ok {
+			return cronSpec{}, fmt.Errorf("unsupported schedule %q", schedule)
+		}
+		schedule = expanded
+	}
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return cronSpec{}, fmt.Errorf("schedule %q: want 5 fields", schedule)
+	}
+	var s cronSpec
+	for i, f := range []struct {
+		set      *[64]bool
+		min, max int
+	}{
+		{&s.minute, 0, 59}, {&s.hour, 0, 23}, {&s.dom, 1, 31}, {&s.month, 1, 12}, {&s.dow, 0, 7},
+	} {
+		if err := parseCronField(fields[i], f.min, f.max, f.set); err != nil {
+			return cronSpec{}, fmt.Errorf("schedule %q: %w", schedule, err)
+		}
+	}
+	// Sunday is both 0 and 7.
+	if s.dow[7] {
+		s.dow[0] = true
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges and steps
+// ("*/15", "1-5", "0,30") into set.
+func parseCronField(field string, lo, hi int, set *[64]bool) error {
+	for part := range strings.SplitSeq(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+		}
+		first, last := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if first, err = strconv.Atoi(a); err != nil {
+				return fmt.Errorf("invalid value %q", part)
+			}
+			last = first
+			if isRange {
+				if last, err = strconv.Atoi(b); err != nil {
+					return fmt.Errorf("invalid value %q", part)
+				}
+			} else if hasStep {
+				last = hi
+			}
+		}
+		if first < lo || last > hi || first > last {
+			return fmt.Errorf("value %q out of range %d-%d", part, lo, hi)
+		}
+		for v := first; v <= last; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+// next returns the first minute after t matching the schedule, or the zero
+// time if none falls within the next five years (e.g. "0 0 31 2 *").
+func (s *cronSpec) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !s.month[t.Month()]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !s.hour[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !s.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *cronSpec) dayMatches(t time.Time) bool {
+	dom, dow := s.dom[t.Day()], s.dow[t.Weekday()]
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dow
+	case s.dowStar:
+		return dom
+	}
+	return dom || dow
+}
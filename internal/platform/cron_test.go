@@ -0,0 +1,71 @@
+package platform
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduledJobs(t *testing.T) {
+	mock := NewMockRunner()
+	mock.OutputMap["crontab [-l]"] = []byte(`MAILTO=dad@example.com
+# mc-dad-server daily backup
+0 4 * * * /usr/local/bin/mc-dad-server backup --dir /home/dad/minecraft-server >> /home/dad/minecraft-server/logs/backup.log 2>&1
+0 */4 * * * mc-dad-server --dir /srv/mc --session=kids rotate-parkour >> rotation.log 2>&1
+@reboot mc-dad-server start
+*/5 * * * * /usr/bin/some-other-tool
+`)
+
+	jobs, err := ScheduledJobs(context.Background(), mock)
+	if err != nil {
+		t.Fatalf("ScheduledJobs() error = %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2: %+v", len(jobs), jobs)
+	}
+	if jobs[0].Name != "backup" || jobs[0].Schedule != "0 4 * * *" {
+		t.Errorf("first job = %q %q", jobs[0].Name, jobs[0].Schedule)
+	}
+	if jobs[1].Name != "rotate-parkour" {
+		t.Errorf("second job name = %q, want rotate-parkour", jobs[1].Name)
+	}
+}
+
+func TestCronJobNext(t *testing.T) {
+	// Sunday 18 October 2026, 10:17.
+	now := time.Date(2026, 10, 18, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		schedule string
+		want     time.Time
+	}{
+		{"0 4 * * *", time.Date(2026, 10, 19, 4, 0, 0, 0, time.UTC)},
+		{"0 */4 * * *", time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 18, 10, 30, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches.
+		{"0 12 1 * 1", time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 18, 11, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		spec, err := parseCronSpec(tt.schedule)
+		if err != nil {
+			t.Fatalf("parseCronSpec(%q) error = %v", tt.schedule, err)
+		}
+		job := CronJob{spec: spec}
+		if got := job.Next(now); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, want %v", tt.schedule, got, tt.want)
+		}
+	}
+}
+
+func TestParseCronSpecErrors(t *testing.T) {
+	for _, schedule := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "@reboot", "a * * * *"} {
+		if _, err := parseCronSpec(schedule); err == nil {
+			t.Errorf("parseCronSpec(%q) expected error", schedule)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os/exec"
 )
@@ -17,6 +18,15 @@ type CommandRunner interface {
 	CommandExists(name string) bool
 }
 
+// CommandStreamer is implemented by runners that can stream the output of a
+// long-running command, such as `podman logs -f`, as it is produced.
+type CommandStreamer interface {
+	// Stream starts the command and returns its combined stdout and
+	// stderr. The reader reports EOF when the command exits; closing it
+	// stops the command.
+	Stream(ctx context.Context, name string, args ...string) (io.ReadCloser, error)
+}
+
 // OSCommandRunner executes real system commands.
 type OSCommandRunner struct{}
 
@@ -58,6 +68,38 @@ func (r *OSCommandRunner) RunSudo(ctx context.Context, name string, args ...stri
 	return r.Run(ctx, "sudo", sudoArgs...)
 }
 
+// Stream starts a system command and returns a reader over its output.
+func (r *OSCommandRunner) Stream(ctx context.Context, name string, args ...string) (io.ReadCloser, error) {
+	slog.Debug("exec", "cmd", name, "args", args)
+	ctx, cancel := context.WithCancel(ctx)
+	cmd := exec.CommandContext(ctx, name, args...)
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("%s %v: %w", name, args, err)
+	}
+	go func() {
+		err := cmd.Wait()
+		cancel()
+		// A nil error closes the pipe with a plain EOF.
+		_ = pw.CloseWithError(err)
+	}()
+	return &commandStream{PipeReader: pr, cancel: cancel}, nil
+}
+
+// commandStream stops its command when closed.
+type commandStream struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (s *commandStream) Close() error {
+	s.cancel()
+	return s.PipeReader.Close()
+}
+
 // CommandExists checks whether a command is available on the system PATH.
 func (r *OSCommandRunner) CommandExists(name string) bool {
 	_, err := exec.LookPath(name)
@@ -122,6 +164,16 @@ func (m *MockRunner) RunSudo(_ context.Context, name string, args ...string) err
 	return nil
 }
 
+// Stream records the command and returns its preconfigured output as a
+// stream that ends after the output, or the preconfigured error.
+func (m *MockRunner) Stream(_ context.Context, name string, args ...string) (io.ReadCloser, error) {
+	m.Commands = append(m.Commands, MockCommand{Name: name, Args: args})
+	if err, ok := m.ErrorMap[m.key(name, args...)]; ok {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(m.OutputMap[m.key(name, args...)])), nil
+}
+
 // CommandExists returns the preconfigured existence value for the given command.
 func (m *MockRunner) CommandExists(name string) bool {
 	if exists, ok := m.ExistsMap[name]; ok {
@@ -2,6 +2,8 @@ package platform
 
 import (
 	"context"
+	"io"
+	"strings"
 	"testing"
 )
 
@@ -57,3 +59,38 @@ func TestMockRunner_RunSudo(t *testing.T) {
 		t.Fatal("expected sudo flag to be set")
 	}
 }
+
+func TestOSCommandRunner_Stream(t *testing.T) {
+	r := NewOSCommandRunner()
+	if !r.CommandExists("sh") {
+		t.Skip("sh not available")
+	}
+
+	out, err := r.Stream(t.Context(), "sh", "-c", "echo out; echo err >&2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	data, err := io.ReadAll(out)
+	if err != nil {
+		t.Fatalf("reading stream: %v", err)
+	}
+	if got := string(data); !strings.Contains(got, "out\n") || !strings.Contains(got, "err\n") {
+		t.Fatalf("stream = %q, want stdout and stderr", got)
+	}
+}
+
+func TestMockRunner_Stream(t *testing.T) {
+	m := NewMockRunner()
+	m.OutputMap[m.key("podman", "logs", "-f", "minecraft")] = []byte("line\n")
+
+	out, err := m.Stream(context.Background(), "podman", "logs", "-f", "minecraft")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := io.ReadAll(out)
+	if string(data) != "line\n" {
+		t.Fatalf("expected preconfigured output, got %q", data)
+	}
+}
@@ -0,0 +1,69 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// lockRetry is how often a held lock is tried again, and lockTimeout
+	// how long it is waited for.
+	lockRetry   = 10 * time.Millisecond
+	lockTimeout = 10 * time.Second
+	// staleLock is how old a lock is when it was left behind by a process
+	// that died holding it. Locks are held for well under a second.
+	staleLock = 30 * time.Second
+)
+
+// LockFile takes the lock file at path, waiting while another process
+// holds it, and returns the function that releases it. Files the daemon
+// and the command line both change are read, changed and written under a
+// lock, so neither undoes the other's change.
+func LockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("locking %s: %w", filepath.Base(path), err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is held; remove it if mc-dad-server isn't running", path)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// WriteFileAtomic writes data to path by way of a temporary file renamed
+// into place, so the file is never read half written.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
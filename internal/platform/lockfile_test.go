@@ -0,0 +1,66 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockFile(t *testing.T) {
+	dir := t.TempDir()
+	lock := filepath.Join(dir, "state.json.lock")
+	counter := filepath.Join(dir, "counter")
+
+	// Each writer reads the count and writes it back one higher; under
+	// the lock none of the increments is lost.
+	const writers, rounds = 4, 25
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Go(func() {
+			for range rounds {
+				unlock, err := LockFile(lock)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				data, _ := os.ReadFile(counter)
+				if err := WriteFileAtomic(counter, append(data, 'x'), 0o644); err != nil {
+					t.Error(err)
+				}
+				unlock()
+			}
+		})
+	}
+	wg.Wait()
+	data, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != writers*rounds {
+		t.Errorf("count = %d, want %d", len(data), writers*rounds)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(matches) != 0 {
+		t.Errorf("temp files left behind: %v", matches)
+	}
+}
+
+func TestLockFileStale(t *testing.T) {
+	lock := filepath.Join(t.TempDir(), "state.json.lock")
+	if err := os.WriteFile(lock, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := LockFile(lock)
+	if err != nil {
+		t.Fatalf("LockFile() over a stale lock: %v", err)
+	}
+	unlock()
+	if _, err := os.Stat(lock); !os.IsNotExist(err) {
+		t.Errorf("lock still there after unlock: %v", err)
+	}
+}
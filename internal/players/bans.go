@@ -0,0 +1,92 @@
+package players
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BannedPlayersFile lists the players banned from the server.
+const BannedPlayersFile = "banned-players.json"
+
+// BanTimeFormat is how banned-players.json writes times.
+const BanTimeFormat = "2006-01-02 15:04:05 -0700"
+
+// BanForever is the expiry of a ban with none.
+const BanForever = "forever"
+
+// BannedPlayer is an entry in banned-players.json.
+type BannedPlayer struct {
+	UUID    string `json:"uuid"`
+	Name    string `json:"name"`
+	Created string `json:"created"`
+	Source  string `json:"source"`
+	// Expires is a time in BanTimeFormat, after which the server lets the
+	// player back in by itself, or BanForever.
+	Expires string `json:"expires"`
+	Reason  string `json:"reason"`
+}
+
+// BannedPlayers is the server's ban list.
+type BannedPlayers []BannedPlayer
+
+// NewBan returns the ban-list entry for banning e at now until the given
+// time, or for good if until is zero.
+func NewBan(e Entry, reason string, now, until time.Time) BannedPlayer {
+	expires := BanForever
+	if !until.IsZero() {
+		expires = until.Format(BanTimeFormat)
+	}
+	return BannedPlayer{UUID: e.UUID, Name: e.Name, Created: now.Format(BanTimeFormat), Source: "Server", Expires: expires, Reason: reason}
+}
+
+// LoadBannedPlayers reads banned-players.json from serverDir. A missing
+// file gives an empty list.
+func LoadBannedPlayers(serverDir string) (BannedPlayers, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, BannedPlayersFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", BannedPlayersFile, err)
+	}
+	var b BannedPlayers
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", BannedPlayersFile, err)
+	}
+	return b, nil
+}
+
+// Save writes the ban list to serverDir as the server does.
+func (b BannedPlayers) Save(serverDir string) error {
+	if b == nil {
+		b = BannedPlayers{}
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", BannedPlayersFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(serverDir, BannedPlayersFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", BannedPlayersFile, err)
+	}
+	return nil
+}
+
+// Put returns the ban list with ban in place of any earlier ban of the
+// same player.
+func (b BannedPlayers) Put(ban BannedPlayer) BannedPlayers {
+	if i := b.index(ban.Name); i >= 0 {
+		b[i] = ban
+		return b
+	}
+	return append(b, ban)
+}
+
+func (b BannedPlayers) index(name string) int {
+	return slices.IndexFunc(b, func(p BannedPlayer) bool { return strings.EqualFold(p.Name, name) })
+}
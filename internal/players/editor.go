@@ -0,0 +1,86 @@
+package players
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+)
+
+// Editor adds players to and removes them from the whitelist. While the
+// server runs, Java players go through its whitelist command so it looks
+// them up itself; the server can't look up Bedrock players, so they are
+// written to whitelist.json and the server told to reload it. While it is
+// stopped, whitelist.json is edited and read at the next start.
+type Editor struct {
+	ServerDir string
+	Manager   management.ServerManager
+	// Running is whether the server is up to take commands.
+	Running  bool
+	Resolver *Resolver
+}
+
+// Add whitelists name. It returns the entry and whether the player was
+// added, which they aren't if already on the list.
+func (e *Editor) Add(ctx context.Context, name string) (Entry, bool, error) {
+	if err := e.Resolver.Check(name); err != nil {
+		return Entry{}, false, err
+	}
+	wl, err := LoadWhitelist(e.ServerDir)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	if i := wl.index(name); i >= 0 {
+		return wl[i], false, nil
+	}
+	if e.Running && !e.Resolver.IsBedrock(name) {
+		if err := e.Manager.SendCommand(ctx, "whitelist add "+name); err != nil {
+			return Entry{}, false, fmt.Errorf("adding %s: %w", name, err)
+		}
+		return Entry{Name: name}, true, nil
+	}
+	entry, err := e.Resolver.Resolve(ctx, name)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	if err := append(wl, entry).Save(e.ServerDir); err != nil {
+		return Entry{}, false, err
+	}
+	return entry, true, e.reload(ctx)
+}
+
+// Remove takes name off the whitelist and reports whether they were on it.
+func (e *Editor) Remove(ctx context.Context, name string) (bool, error) {
+	if err := e.Resolver.Check(name); err != nil {
+		return false, err
+	}
+	wl, err := LoadWhitelist(e.ServerDir)
+	if err != nil {
+		return false, err
+	}
+	wl, removed := wl.Remove(name)
+	if !removed {
+		return false, nil
+	}
+	if e.Running && !e.Resolver.IsBedrock(name) {
+		if err := e.Manager.SendCommand(ctx, "whitelist remove "+name); err != nil {
+			return false, fmt.Errorf("removing %s: %w", name, err)
+		}
+		return true, nil
+	}
+	if err := wl.Save(e.ServerDir); err != nil {
+		return false, err
+	}
+	return true, e.reload(ctx)
+}
+
+// reload has a running server read the edited whitelist.json.
+func (e *Editor) reload(ctx context.Context) error {
+	if !e.Running {
+		return nil
+	}
+	if err := e.Manager.SendCommand(ctx, "whitelist reload"); err != nil {
+		return fmt.Errorf("reloading the whitelist: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Package players reads and edits the player lists the Minecraft server
+// keeps in its directory, such as ops.json and whitelist.json, and queues
+// the players the whitelist turned away.
+package players
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Player list files in the server directory.
+const (
+	// OpsFile lists the server operators.
+	OpsFile = "ops.json"
+	// WhitelistFile lists the players allowed to join when the whitelist
+	// is on.
+	WhitelistFile = "whitelist.json"
+)
+
+// Op is an entry in ops.json.
+type Op struct {
+	UUID  string `json:"uuid"`
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+}
+
+// Ops is the server's operator list.
+type Ops []Op
+
+// LoadOps reads ops.json from serverDir. A server that has never had an
+// operator has no file, which gives an empty list.
+func LoadOps(serverDir string) (Ops, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, OpsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", OpsFile, err)
+	}
+	var ops Ops
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", OpsFile, err)
+	}
+	return ops, nil
+}
+
+// Level returns the named player's permission level, or 0 if they are not
+// an operator. Names are matched ignoring case, as the server does.
+func (o Ops) Level(name string) int {
+	for i := range o {
+		if strings.EqualFold(o[i].Name, name) {
+			return o[i].Level
+		}
+	}
+	return 0
+}
+
+// IsOp reports whether the named player is an operator.
+func (o Ops) IsOp(name string) bool {
+	return o.Level(name) > 0
+}
+
+// Entry is a player in whitelist.json.
+type Entry struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+// Whitelist is the server's whitelist.
+type Whitelist []Entry
+
+// LoadWhitelist reads whitelist.json from serverDir. A missing file gives
+// an empty list.
+func LoadWhitelist(serverDir string) (Whitelist, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, WhitelistFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", WhitelistFile, err)
+	}
+	var wl Whitelist
+	if err := json.Unmarshal(data, &wl); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", WhitelistFile, err)
+	}
+	return wl, nil
+}
+
+// Save writes the whitelist to serverDir as the server does.
+func (w Whitelist) Save(serverDir string) error {
+	if w == nil {
+		w = Whitelist{}
+	}
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", WhitelistFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(serverDir, WhitelistFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", WhitelistFile, err)
+	}
+	return nil
+}
+
+// Contains reports whether the named player is on the whitelist, ignoring
+// case.
+func (w Whitelist) Contains(name string) bool {
+	return w.index(name) >= 0
+}
+
+// Remove returns the whitelist without the named player, ignoring case,
+// and whether they were on it.
+func (w Whitelist) Remove(name string) (Whitelist, bool) {
+	i := w.index(name)
+	if i < 0 {
+		return w, false
+	}
+	return slices.Delete(w, i, i+1), true
+}
+
+func (w Whitelist) index(name string) int {
+	return slices.IndexFunc(w, func(e Entry) bool { return strings.EqualFold(e.Name, name) })
+}
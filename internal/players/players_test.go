@@ -0,0 +1,74 @@
+package players
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadOps(t *testing.T) {
+	dir := t.TempDir()
+
+	ops, err := LoadOps(dir)
+	if err != nil || len(ops) != 0 {
+		t.Fatalf("LoadOps() without a file = %v, %v; want empty", ops, err)
+	}
+
+	data := `[
+  {"uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5", "name": "Dad", "level": 4, "bypassesPlayerLimit": false},
+  {"uuid": "00000000-0000-0000-0009-01f5a3b2c1d0", "name": ".KidTablet", "level": 2, "bypassesPlayerLimit": false}
+]`
+	if err := os.WriteFile(filepath.Join(dir, OpsFile), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ops, err = LoadOps(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		level int
+	}{
+		{"Dad", 4},
+		{"dad", 4},
+		{".KidTablet", 2},
+		{"Steve", 0},
+	}
+	for _, tt := range tests {
+		if got := ops.Level(tt.name); got != tt.level {
+			t.Errorf("Level(%q) = %d, want %d", tt.name, got, tt.level)
+		}
+		if got := ops.IsOp(tt.name); got != (tt.level > 0) {
+			t.Errorf("IsOp(%q) = %v", tt.name, got)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, OpsFile), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOps(dir); err == nil {
+		t.Error("LoadOps() with bad JSON: want error")
+	}
+}
+
+func TestLoadWhitelist(t *testing.T) {
+	dir := t.TempDir()
+
+	wl, err := LoadWhitelist(dir)
+	if err != nil || len(wl) != 0 {
+		t.Fatalf("LoadWhitelist() without a file = %v, %v; want empty", wl, err)
+	}
+
+	data := `[{"uuid": "853c80ef-3c37-49fd-aa49-938b674adae6", "name": "Steve"}]`
+	if err := os.WriteFile(filepath.Join(dir, WhitelistFile), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wl, err = LoadWhitelist(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !wl.Contains("STEVE") || wl.Contains("Alex") {
+		t.Errorf("Contains: got %v", wl)
+	}
+}
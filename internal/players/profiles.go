@@ -0,0 +1,261 @@
+package players
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // offline-mode UUIDs are defined as MD5 name UUIDs
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/serverctl"
+)
+
+// UserCacheFile is where the server remembers the names and UUIDs of
+// players it has seen.
+const UserCacheFile = "usercache.json"
+
+// DefaultBedrockPrefix is the prefix Floodgate gives Bedrock players'
+// names unless its config says otherwise.
+const DefaultBedrockPrefix = "."
+
+// ErrUnknownPlayer is returned when no account has the name.
+var ErrUnknownPlayer = errors.New("no such player")
+
+// javaName matches a Java Edition username, and bedrockName a Floodgate
+// name without its prefix: gamertags are looser, but Floodgate turns their
+// spaces into underscores.
+var (
+	javaName    = regexp.MustCompile(`^\w{3,16}$`)
+	bedrockName = regexp.MustCompile(`^\w{1,32}$`)
+)
+
+// ProfileLookup finds players' accounts by name. WebLookup asks Mojang and
+// GeyserMC; tests and servers behind other auth plug in their own.
+type ProfileLookup interface {
+	// Java returns the Java Edition account with name.
+	Java(ctx context.Context, name string) (Entry, error)
+	// Bedrock returns the Floodgate UUID of the Xbox gamertag.
+	Bedrock(ctx context.Context, gamertag string) (string, error)
+}
+
+// Resolver turns player names into whitelist entries.
+type Resolver struct {
+	ServerDir string
+	Lookup    ProfileLookup
+	// Offline is set for servers with online-mode=false, whose players'
+	// UUIDs are derived from their names rather than looked up.
+	Offline bool
+	// BedrockPrefix marks Floodgate players' names; empty when Floodgate
+	// isn't installed.
+	BedrockPrefix string
+}
+
+// NewResolver returns a Resolver for the server in serverDir, reading
+// online-mode from server.properties and the Bedrock prefix from the
+// Floodgate config.
+func NewResolver(serverDir string, lookup ProfileLookup) *Resolver {
+	return &Resolver{
+		ServerDir:     serverDir,
+		Lookup:        lookup,
+		Offline:       serverctl.ReadProperty(serverDir, "online-mode") == "false",
+		BedrockPrefix: FloodgatePrefix(serverDir),
+	}
+}
+
+// IsBedrock reports whether name is a Floodgate player's.
+func (r *Resolver) IsBedrock(name string) bool {
+	return r.BedrockPrefix != "" && strings.HasPrefix(name, r.BedrockPrefix)
+}
+
+// Check returns an error if name can't be a player's: that also keeps
+// anything but a name out of the console commands it is put in.
+func (r *Resolver) Check(name string) error {
+	if r.IsBedrock(name) {
+		if bedrockName.MatchString(strings.TrimPrefix(name, r.BedrockPrefix)) {
+			return nil
+		}
+	} else if javaName.MatchString(name) {
+		return nil
+	}
+	return fmt.Errorf("%q is not a valid player name", name)
+}
+
+// Resolve returns the whitelist entry for name. Java players are looked
+// up, or given their offline UUID on an offline-mode server, and Bedrock
+// players get their Floodgate UUID. If the lookup fails, a player the
+// server has seen is found in usercache.json instead.
+func (r *Resolver) Resolve(ctx context.Context, name string) (Entry, error) {
+	if err := r.Check(name); err != nil {
+		return Entry{}, err
+	}
+	var e Entry
+	var err error
+	switch {
+	case r.IsBedrock(name):
+		// Floodgate turned the gamertag's spaces into underscores.
+		gamertag := strings.ReplaceAll(strings.TrimPrefix(name, r.BedrockPrefix), "_", " ")
+		e.Name = name
+		e.UUID, err = r.Lookup.Bedrock(ctx, gamertag)
+	case r.Offline:
+		return Entry{UUID: OfflineUUID(name), Name: name}, nil
+	default:
+		e, err = r.Lookup.Java(ctx, name)
+	}
+	if err == nil {
+		return e, nil
+	}
+	if cached, ok := lookupUserCache(r.ServerDir, name); ok {
+		return cached, nil
+	}
+	return Entry{}, fmt.Errorf("looking up %s: %w", name, err)
+}
+
+// OfflineUUID is the UUID an offline-mode server gives name: a version 3
+// UUID of "OfflinePlayer:" and the name.
+func OfflineUUID(name string) string {
+	sum := md5.Sum([]byte("OfflinePlayer:" + name)) //nolint:gosec // see import
+	sum[6] = sum[6]&0x0f | 0x30
+	sum[8] = sum[8]&0x3f | 0x80
+	return formatUUID(sum[:])
+}
+
+// FloodgateUUID is the UUID Floodgate gives the Xbox account xuid.
+func FloodgateUUID(xuid uint64) string {
+	return fmt.Sprintf("00000000-0000-0000-%04x-%012x", xuid>>48, xuid&(1<<48-1))
+}
+
+// formatUUID writes 16 bytes in the usual dashed form.
+func formatUUID(b []byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// dashUUID adds the dashes to a 32-digit UUID as Mojang's API returns it.
+func dashUUID(id string) string {
+	if len(id) != 32 {
+		return id
+	}
+	return id[0:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:32]
+}
+
+// cachedUser is an entry in usercache.json.
+type cachedUser struct {
+	Name string `json:"name"`
+	UUID string `json:"uuid"`
+}
+
+// lookupUserCache finds name in serverDir's usercache.json, ignoring case.
+func lookupUserCache(serverDir, name string) (Entry, bool) {
+	data, err := os.ReadFile(filepath.Join(serverDir, UserCacheFile))
+	if err != nil {
+		return Entry{}, false
+	}
+	var users []cachedUser
+	if json.Unmarshal(data, &users) != nil {
+		return Entry{}, false
+	}
+	for _, u := range users {
+		if strings.EqualFold(u.Name, name) && u.UUID != "" {
+			return Entry{UUID: u.UUID, Name: u.Name}, true
+		}
+	}
+	return Entry{}, false
+}
+
+// FloodgatePrefix returns the prefix Floodgate gives Bedrock players'
+// names on the server in serverDir, or "" if Floodgate isn't installed.
+func FloodgatePrefix(serverDir string) string {
+	dir := filepath.Join(serverDir, "plugins", "floodgate")
+	if _, err := os.Stat(dir); err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.yml"))
+	if err != nil {
+		return DefaultBedrockPrefix
+	}
+	for line := range strings.SplitSeq(string(data), "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "username-prefix:"); ok {
+			return strings.Trim(strings.TrimSpace(v), `"'`)
+		}
+	}
+	return DefaultBedrockPrefix
+}
+
+// Public profile APIs used by WebLookup.
+const (
+	MojangProfileURL = "https://api.mojang.com/users/profiles/minecraft/"
+	GeyserXUIDURL    = "https://api.geysermc.org/v2/xbox/xuid/"
+)
+
+// WebLookup looks players up with Mojang's and GeyserMC's public APIs.
+type WebLookup struct {
+	Client *http.Client
+	// MojangURL and GeyserURL are the API prefixes the name is appended
+	// to; NewWebLookup sets them to MojangProfileURL and GeyserXUIDURL.
+	MojangURL string
+	GeyserURL string
+}
+
+// NewWebLookup returns a WebLookup with a short timeout.
+func NewWebLookup() *WebLookup {
+	return &WebLookup{Client: &http.Client{Timeout: 10 * time.Second}, MojangURL: MojangProfileURL, GeyserURL: GeyserXUIDURL}
+}
+
+// Java asks Mojang for name's UUID and the name's proper case.
+func (l *WebLookup) Java(ctx context.Context, name string) (Entry, error) {
+	var body struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := l.get(ctx, l.MojangURL+url.PathEscape(name), &body); err != nil {
+		return Entry{}, err
+	}
+	if body.ID == "" {
+		return Entry{}, ErrUnknownPlayer
+	}
+	return Entry{UUID: dashUUID(body.ID), Name: body.Name}, nil
+}
+
+// Bedrock asks GeyserMC for the gamertag's XUID.
+func (l *WebLookup) Bedrock(ctx context.Context, gamertag string) (string, error) {
+	var body struct {
+		XUID json.Number `json:"xuid"`
+	}
+	if err := l.get(ctx, l.GeyserURL+url.PathEscape(gamertag), &body); err != nil {
+		return "", err
+	}
+	xuid, err := body.XUID.Int64()
+	if err != nil || xuid <= 0 {
+		return "", ErrUnknownPlayer
+	}
+	return FloodgateUUID(uint64(xuid)), nil
+}
+
+func (l *WebLookup) get(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "mc-dad-server")
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode == http.StatusNoContent, resp.StatusCode == http.StatusNotFound:
+		return ErrUnknownPlayer
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("profile lookup: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("profile lookup: %w", err)
+	}
+	return nil
+}
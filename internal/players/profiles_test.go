@@ -0,0 +1,174 @@
+package players
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+)
+
+func TestOfflineUUID(t *testing.T) {
+	if got, want := OfflineUUID("Notch"), "b50ad385-829d-3141-a216-7e7d7539ba7f"; got != want {
+		t.Errorf("OfflineUUID(Notch) = %s, want %s", got, want)
+	}
+}
+
+func TestFloodgateUUID(t *testing.T) {
+	if got, want := FloodgateUUID(0x000901f5a3b2c1d0), "00000000-0000-0000-0009-01f5a3b2c1d0"; got != want {
+		t.Errorf("FloodgateUUID() = %s, want %s", got, want)
+	}
+}
+
+func TestFloodgatePrefix(t *testing.T) {
+	dir := t.TempDir()
+	if got := FloodgatePrefix(dir); got != "" {
+		t.Errorf("FloodgatePrefix() without Floodgate = %q, want none", got)
+	}
+	floodgate := filepath.Join(dir, "plugins", "floodgate")
+	if err := os.MkdirAll(floodgate, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := FloodgatePrefix(dir); got != DefaultBedrockPrefix {
+		t.Errorf("FloodgatePrefix() before Floodgate wrote its config = %q, want %q", got, DefaultBedrockPrefix)
+	}
+	if err := os.WriteFile(filepath.Join(floodgate, "config.yml"), []byte("key-file-name: key.pem\nusername-prefix: \"*\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := FloodgatePrefix(dir); got != "*" {
+		t.Errorf("FloodgatePrefix() = %q, want *", got)
+	}
+}
+
+// fakeLookup knows Steve's Java account and Kid Tablet's Xbox one.
+type fakeLookup struct{}
+
+func (fakeLookup) Java(_ context.Context, name string) (Entry, error) {
+	if strings.EqualFold(name, "steve") {
+		return Entry{UUID: "853c80ef-3c37-49fd-aa49-938b674adae6", Name: "Steve"}, nil
+	}
+	return Entry{}, ErrUnknownPlayer
+}
+
+func (fakeLookup) Bedrock(_ context.Context, gamertag string) (string, error) {
+	if gamertag == "Kid Tablet" {
+		return FloodgateUUID(0x000901f5a3b2c1d0), nil
+	}
+	return "", ErrUnknownPlayer
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	cache := `[{"name":"Alex","uuid":"ec561538-f3fd-461d-aff5-086b22154bce","expiresOn":"2026-11-18 12:00:00 +0000"}]`
+	if err := os.WriteFile(filepath.Join(dir, UserCacheFile), []byte(cache), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		resolver Resolver
+		player   string
+		want     Entry
+		wantErr  bool
+	}{
+		{"java", Resolver{}, "steve", Entry{"853c80ef-3c37-49fd-aa49-938b674adae6", "Steve"}, false},
+		{"usercache when the lookup fails", Resolver{}, "alex", Entry{"ec561538-f3fd-461d-aff5-086b22154bce", "Alex"}, false},
+		{"unknown", Resolver{}, "Nobody", Entry{}, true},
+		{"offline mode", Resolver{Offline: true}, "Notch", Entry{"b50ad385-829d-3141-a216-7e7d7539ba7f", "Notch"}, false},
+		{"bedrock", Resolver{BedrockPrefix: "."}, ".Kid_Tablet", Entry{"00000000-0000-0000-0009-01f5a3b2c1d0", ".Kid_Tablet"}, false},
+		{"prefix without floodgate", Resolver{}, ".Kid_Tablet", Entry{}, true},
+		{"not a name", Resolver{}, "Steve; op Steve", Entry{}, true},
+	}
+	for _, tt := range tests {
+		r := tt.resolver
+		r.ServerDir, r.Lookup = dir, fakeLookup{}
+		got, err := r.Resolve(t.Context(), tt.player)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: Resolve(%q) = %+v, %v; want %+v, error %v", tt.name, tt.player, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestWebLookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mojang/steve":
+			_, _ = w.Write([]byte(`{"id":"853c80ef3c3749fdaa49938b674adae6","name":"Steve"}`))
+		case "/geyser/Kid Tablet":
+			_, _ = w.Write([]byte(`{"xuid":2533274793189840}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	l := &WebLookup{Client: srv.Client(), MojangURL: srv.URL + "/mojang/", GeyserURL: srv.URL + "/geyser/"}
+
+	e, err := l.Java(t.Context(), "steve")
+	if err != nil || e != (Entry{"853c80ef-3c37-49fd-aa49-938b674adae6", "Steve"}) {
+		t.Errorf("Java(steve) = %+v, %v", e, err)
+	}
+	if _, err := l.Java(t.Context(), "Nobody"); !errors.Is(err, ErrUnknownPlayer) {
+		t.Errorf("Java(Nobody) error = %v, want ErrUnknownPlayer", err)
+	}
+	uuid, err := l.Bedrock(t.Context(), "Kid Tablet")
+	if err != nil || uuid != FloodgateUUID(2533274793189840) {
+		t.Errorf("Bedrock(Kid Tablet) = %s, %v", uuid, err)
+	}
+}
+
+func TestEditor(t *testing.T) {
+	dir := t.TempDir()
+	mgr := management.NewMockManager()
+	ed := &Editor{
+		ServerDir: dir,
+		Manager:   mgr,
+		Resolver:  &Resolver{ServerDir: dir, Lookup: fakeLookup{}, BedrockPrefix: "."},
+	}
+	ctx := t.Context()
+
+	// Stopped: the file is edited.
+	if e, added, err := ed.Add(ctx, "steve"); err != nil || !added || e.Name != "Steve" {
+		t.Fatalf("Add(steve) while stopped = %+v, %v, %v", e, added, err)
+	}
+	if _, added, err := ed.Add(ctx, "Steve"); err != nil || added {
+		t.Errorf("Add(Steve) again = %v, %v; want already on the list", added, err)
+	}
+	if sent := mgr.Commands(); len(sent) != 0 {
+		t.Errorf("sent %v while stopped, want nothing", sent)
+	}
+
+	// Running: Java players go through the server, Bedrock players
+	// through the file and a reload.
+	ed.Running = true
+	if _, added, err := ed.Add(ctx, "Alex"); err != nil || !added {
+		t.Errorf("Add(Alex) while running = %v, %v", added, err)
+	}
+	if _, added, err := ed.Add(ctx, ".Kid_Tablet"); err != nil || !added {
+		t.Errorf("Add(.Kid_Tablet) while running = %v, %v", added, err)
+	}
+	if removed, err := ed.Remove(ctx, "steve"); err != nil || !removed {
+		t.Errorf("Remove(steve) while running = %v, %v", removed, err)
+	}
+	if removed, err := ed.Remove(ctx, "Herobrine"); err != nil || removed {
+		t.Errorf("Remove(Herobrine) = %v, %v; want not on the list", removed, err)
+	}
+	want := []string{"whitelist add Alex", "whitelist reload", "whitelist remove steve"}
+	if sent := mgr.Commands(); !slices.Equal(sent, want) {
+		t.Errorf("sent %v, want %v", sent, want)
+	}
+
+	wl, err := LoadWhitelist(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fake server doesn't act on its commands, so Steve is still in
+	// the file and Alex never reached it.
+	if !wl.Contains("Steve") || wl.Contains("Alex") || !wl.Contains(".Kid_Tablet") {
+		t.Errorf("whitelist.json = %v", wl)
+	}
+}
@@ -0,0 +1,210 @@
+package players
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
+)
+
+// RequestsFile queues the players the whitelist turned away, for a parent
+// to approve or deny.
+const RequestsFile = "whitelist-requests.json"
+
+// requestsLock is held while the queue is changed, so the daemon recording
+// refusals and parents approving or denying them don't undo each other.
+const requestsLock = RequestsFile + ".lock"
+
+// Request is a player who tried to join without being on the whitelist.
+type Request struct {
+	Name string `json:"name"`
+	// UUID is empty if the server didn't log it.
+	UUID     string    `json:"uuid,omitempty"`
+	First    time.Time `json:"first"`
+	Last     time.Time `json:"last"`
+	Attempts int       `json:"attempts"`
+	// Denied requests stay in the queue so later attempts aren't asked
+	// about again.
+	Denied bool `json:"denied,omitempty"`
+}
+
+// Requests is the queue of players asking to join, in the order they
+// first tried.
+type Requests []Request
+
+// LoadRequests reads the queue from serverDir. A missing file gives an
+// empty queue.
+func LoadRequests(serverDir string) (Requests, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, RequestsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", RequestsFile, err)
+	}
+	var r Requests
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", RequestsFile, err)
+	}
+	return r, nil
+}
+
+// EditRequests loads serverDir's queue, lets edit change it and saves it,
+// holding the queue's lock throughout. Nothing is saved if edit returns an
+// error.
+func EditRequests(serverDir string, edit func(*Requests) error) error {
+	unlock, err := platform.LockFile(filepath.Join(serverDir, requestsLock))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	reqs, err := LoadRequests(serverDir)
+	if err != nil {
+		return err
+	}
+	if err := edit(&reqs); err != nil {
+		return err
+	}
+	return reqs.Save(serverDir)
+}
+
+// Save writes the queue to serverDir. It is replaced whole, so a reader
+// never sees it half written; use EditRequests to change it.
+func (r Requests) Save(serverDir string) error {
+	if r == nil {
+		r = Requests{}
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", RequestsFile, err)
+	}
+	if err := platform.WriteFileAtomic(filepath.Join(serverDir, RequestsFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", RequestsFile, err)
+	}
+	return nil
+}
+
+// Record notes that the named player was refused at the given time and
+// reports whether they are newly asking to join. Refusals no later than
+// the player's last are already counted: the server logs each one twice,
+// and the same log may be read again.
+func (r *Requests) Record(name, uuid string, at time.Time) bool {
+	i := r.index(name)
+	if i < 0 {
+		*r = append(*r, Request{Name: name, UUID: uuid, First: at, Last: at, Attempts: 1})
+		return true
+	}
+	req := &(*r)[i]
+	if !at.After(req.Last) {
+		return false
+	}
+	req.Last = at
+	req.Attempts++
+	if uuid != "" {
+		req.UUID = uuid
+	}
+	return false
+}
+
+// Find returns the named player's request, ignoring case.
+func (r Requests) Find(name string) (Request, bool) {
+	if i := r.index(name); i >= 0 {
+		return r[i], true
+	}
+	return Request{}, false
+}
+
+// Pending returns the requests not yet denied.
+func (r Requests) Pending() Requests {
+	return slices.DeleteFunc(slices.Clone(r), func(req Request) bool { return req.Denied })
+}
+
+// Deny marks the named player's request denied and reports whether they
+// had one.
+func (r Requests) Deny(name string) bool {
+	i := r.index(name)
+	if i < 0 {
+		return false
+	}
+	r[i].Denied = true
+	return true
+}
+
+// Remove returns the queue without the named player's request, ignoring
+// case, and whether they had one.
+func (r Requests) Remove(name string) (Requests, bool) {
+	i := r.index(name)
+	if i < 0 {
+		return r, false
+	}
+	return slices.Delete(r, i, i+1), true
+}
+
+func (r Requests) index(name string) int {
+	return slices.IndexFunc(r, func(req Request) bool { return strings.EqualFold(req.Name, name) })
+}
+
+// UpdateRequests brings serverDir's queue up to date with the refusals in
+// logs/latest.log, drops players who have since been whitelisted, and
+// returns it. The daemon records refusals as they happen; this catches
+// those made while it wasn't running, since the server last started.
+//
+// Players for whom held is true, such as those parental controls took off
+// the whitelist, are kept out of the queue: being refused is the point.
+func UpdateRequests(serverDir string, held func(name string) bool) (Requests, error) {
+	unlock, err := platform.LockFile(filepath.Join(serverDir, requestsLock))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	reqs, err := LoadRequests(serverDir)
+	if err != nil {
+		return nil, err
+	}
+	wl, err := LoadWhitelist(serverDir)
+	if err != nil {
+		return nil, err
+	}
+	changed := false
+
+	latest := filepath.Join(serverDir, "logs", "latest.log")
+	info, err := os.Stat(latest)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading latest.log: %w", err)
+	default:
+		lines, err := events.ReadLog(latest, info.ModTime())
+		if err != nil {
+			return nil, err
+		}
+		parser, _ := events.LoadParser(serverDir)
+		for _, line := range lines {
+			if line.Time.IsZero() || !strings.Contains(line.Text, "listed on this server") {
+				continue
+			}
+			y, m, d := line.Time.Date()
+			ev, ok := parser.Parse(line.Text, time.Date(y, m, d, 0, 0, 0, 0, line.Time.Location()))
+			if ok && ev.Kind == events.NotWhitelisted && !wl.Contains(ev.Player) && !held(ev.Player) {
+				reqs.Record(ev.Player, ev.UUID, ev.Time)
+				changed = true
+			}
+		}
+	}
+
+	before := len(reqs)
+	reqs = slices.DeleteFunc(reqs, func(req Request) bool { return wl.Contains(req.Name) || held(req.Name) })
+	if changed || len(reqs) != before {
+		if err := reqs.Save(serverDir); err != nil {
+			return nil, err
+		}
+	}
+	return reqs, nil
+}
@@ -0,0 +1,79 @@
+package players
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecord(t *testing.T) {
+	at := time.Date(2026, 10, 18, 16, 0, 0, 0, time.Local)
+	var reqs Requests
+	if !reqs.Record("Steve", "", at) {
+		t.Error("first refusal isn't a new request")
+	}
+	if reqs.Record("steve", "", at) {
+		t.Error("the same refusal logged twice is a new request")
+	}
+	if reqs.Record("Steve", "853c80ef-3c37-49fd-aa49-938b674adae6", at.Add(time.Minute)) {
+		t.Error("a second attempt is a new request")
+	}
+	want := Request{Name: "Steve", UUID: "853c80ef-3c37-49fd-aa49-938b674adae6", First: at, Last: at.Add(time.Minute), Attempts: 2}
+	if len(reqs) != 1 || reqs[0] != want {
+		t.Errorf("queue = %+v, want [%+v]", reqs, want)
+	}
+
+	reqs.Record("Alex", "", at)
+	if !reqs.Deny("alex") || reqs.Deny("Herobrine") {
+		t.Error("Deny() found the wrong requests")
+	}
+	if p := reqs.Pending(); len(p) != 1 || p[0].Name != "Steve" {
+		t.Errorf("Pending() = %+v, want only Steve", p)
+	}
+	if reqs.Record("Alex", "", at.Add(time.Hour)) {
+		t.Error("a denied player trying again is a new request")
+	}
+	reqs, ok := reqs.Remove("STEVE")
+	if !ok || len(reqs) != 1 || reqs[0].Name != "Alex" || !reqs[0].Denied {
+		t.Errorf("Remove(STEVE) = %+v, %v", reqs, ok)
+	}
+}
+
+func TestUpdateRequests(t *testing.T) {
+	dir := t.TempDir()
+	logs := filepath.Join(dir, "logs")
+	if err := os.MkdirAll(logs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	log := `[16:00:01] [Server thread/INFO]: Disconnecting Steve (853c80ef-3c37-49fd-aa49-938b674adae6): You are not white-listed on this server!
+[16:00:01] [Server thread/INFO]: Steve (853c80ef-3c37-49fd-aa49-938b674adae6) lost connection: You are not white-listed on this server!
+[16:05:40] [Server thread/INFO]: Disconnecting Alex (/192.168.1.21:50000): You are not white-listed on this server!
+[16:07:02] [Server thread/INFO]: Disconnecting Steve (853c80ef-3c37-49fd-aa49-938b674adae6): You are not white-listed on this server!
+[16:09:15] [Server thread/INFO]: Disconnecting Kid (/192.168.1.30:50000): You are not white-listed on this server!
+`
+	if err := os.WriteFile(filepath.Join(logs, "latest.log"), []byte(log), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Whitelist{{Name: "Alex", UUID: "ec561538-f3fd-461d-aff5-086b22154bce"}}).Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Kid is held off the whitelist, so their refusal isn't a request.
+	held := func(name string) bool { return strings.EqualFold(name, "kid") }
+
+	// Reading the log again doesn't count its attempts twice.
+	for range 2 {
+		reqs, err := UpdateRequests(dir, held)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(reqs) != 1 || reqs[0].Name != "Steve" || reqs[0].Attempts != 2 || reqs[0].UUID == "" {
+			t.Errorf("UpdateRequests() = %+v, want Steve with 2 attempts; Alex is already whitelisted and Kid held", reqs)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, RequestsFile)); err != nil {
+		t.Errorf("queue not saved: %v", err)
+	}
+}
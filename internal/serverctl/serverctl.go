@@ -12,6 +12,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/KevinTCoughlin/mc-dad-server/internal/config"
@@ -76,11 +77,32 @@ func Resolve(ctx context.Context, t Target, runner platform.CommandRunner) Resol
 		}
 	}
 	return Resolved{
-		Manager: management.NewScreenManager(runner, t.Session, filepath.Join(t.Dir, "start.sh")),
-		Mode:    mode,
+		Manager: &screenManager{
+			ScreenManager: management.NewScreenManager(runner, t.Session, filepath.Join(t.Dir, "start.sh")),
+			rcon:          container.NewPersistentRCON(DefaultRCONAddr, ReadRCONPassword(t.Dir)),
+		},
+		Mode: mode,
 	}
 }
 
+// screenManager is a screen-session manager that also answers Query over the
+// server's RCON listener, which the shipped configs enable in every mode.
+// Commands still go through screen so that they work without a password.
+type screenManager struct {
+	*management.ScreenManager
+	rcon *container.PersistentRCON
+}
+
+// Query sends cmd over RCON and returns the server's reply.
+func (s *screenManager) Query(ctx context.Context, cmd string) (string, error) {
+	return s.rcon.Command(ctx, cmd)
+}
+
+// Close releases the RCON connection, if one was opened.
+func (s *screenManager) Close() error {
+	return s.rcon.Close()
+}
+
 // ResolveMode determines the server mode from the target's mode setting,
 // auto-detecting when it is unset or "auto".
 func ResolveMode(ctx context.Context, t Target, runner platform.CommandRunner) string {
@@ -119,22 +141,36 @@ func ReadRCONPassword(serverDir string) string {
 	if pass := os.Getenv("RCON_PASSWORD"); pass != "" {
 		return pass
 	}
+	return ReadProperty(serverDir, "rcon.password")
+}
+
+// ReadProperty returns the trimmed value of key in the server dir's
+// server.properties, or "" when the file or key is missing.
+func ReadProperty(serverDir, key string) string {
 	data, err := os.ReadFile(filepath.Join(serverDir, "server.properties"))
 	if err != nil {
 		return ""
 	}
 	for line := range strings.SplitSeq(string(data), "\n") {
-		if after, ok := strings.CutPrefix(line, "rcon.password="); ok {
+		if after, ok := strings.CutPrefix(line, key+"="); ok {
 			return strings.TrimSpace(after)
 		}
 	}
 	return ""
 }
 
-// Config returns a ServerConfig seeded with defaults for the target.
+// Config returns a ServerConfig seeded with defaults for the target. The
+// port and MOTD are taken from the server's server.properties when present,
+// so a server installed with a custom --port is still found.
 func Config(t Target) *config.ServerConfig {
 	cfg := config.DefaultConfig()
 	cfg.Dir = t.Dir
 	cfg.SessionName = t.Session
+	if port, err := strconv.Atoi(ReadProperty(t.Dir, "server-port")); err == nil && port > 0 && port <= 65535 {
+		cfg.Port = port
+	}
+	if motd := ReadProperty(t.Dir, "motd"); motd != "" {
+		cfg.MOTD = motd
+	}
 	return cfg
 }
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/platform"
 )
 
@@ -117,3 +118,35 @@ func TestReadRCONPasswordMissingFile(t *testing.T) {
 		t.Fatalf("got %q, want empty", got)
 	}
 }
+
+func TestResolveScreenSupportsQuery(t *testing.T) {
+	t.Parallel()
+
+	res := Resolve(t.Context(), Target{Mode: ModeScreen, Dir: t.TempDir(), Session: "minecraft"}, platform.NewMockRunner())
+	defer func() { _ = res.Close() }()
+
+	if _, ok := res.Manager.(management.Querier); !ok {
+		t.Fatal("screen manager should answer queries over RCON")
+	}
+}
+
+func TestConfigReadsServerProperties(t *testing.T) {
+	dir := t.TempDir()
+	props := "server-port=25570\nmotd=Kids Server\n"
+	if err := os.WriteFile(filepath.Join(dir, "server.properties"), []byte(props), 0o600); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	cfg := Config(Target{Dir: dir, Session: "minecraft"})
+	if cfg.Port != 25570 {
+		t.Errorf("Port = %d, want 25570", cfg.Port)
+	}
+	if cfg.MOTD != "Kids Server" {
+		t.Errorf("MOTD = %q, want %q", cfg.MOTD, "Kids Server")
+	}
+
+	// Without server.properties the defaults stand.
+	if cfg := Config(Target{Dir: t.TempDir()}); cfg.Port != 25565 {
+		t.Errorf("default Port = %d, want 25565", cfg.Port)
+	}
+}
@@ -0,0 +1,167 @@
+package sessions
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Player summarises one player's sessions.
+type Player struct {
+	Name   string
+	Online bool
+	// LastSeen is when the player last left, or when they joined if they
+	// are online.
+	LastSeen time.Time
+	Sessions int
+	Playtime time.Duration
+}
+
+// Players summarises sessions per player: online players first, then the
+// most recently seen.
+func Players(sessions []Session, now time.Time) []Player {
+	byName := make(map[string]*Player)
+	for i := range sessions {
+		s := &sessions[i]
+		key := strings.ToLower(s.Player)
+		p := byName[key]
+		if p == nil {
+			p = &Player{}
+			byName[key] = p
+		}
+		// Sessions are oldest first, so the latest spelling wins.
+		p.Name = s.Player
+		p.Sessions++
+		p.Playtime += s.Duration(now)
+		if s.End.IsZero() {
+			p.Online = true
+			p.LastSeen = s.Start
+		} else if !p.Online {
+			p.LastSeen = s.End
+		}
+	}
+
+	out := make([]Player, 0, len(byName))
+	for _, p := range byName {
+		out = append(out, *p)
+	}
+	slices.SortFunc(out, func(a, b Player) int {
+		if a.Online != b.Online {
+			if a.Online {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(b.LastSeen.Compare(a.LastSeen), strings.Compare(a.Name, b.Name))
+	})
+	return out
+}
+
+// Total is a player's playtime over a report's window, or over one day of
+// it.
+type Total struct {
+	Player string
+	// Day is the local midnight that starts the day, or zero for the whole
+	// window.
+	Day      time.Time
+	Sessions int
+	Playtime time.Duration
+}
+
+// Playtime totals each player's time online between since and now. With
+// byDay the totals are per local day, splitting sessions at midnight. The
+// totals are in day order, most played first.
+func Playtime(sessions []Session, since, now time.Time, byDay bool) []Total {
+	type key struct {
+		player string
+		day    time.Time
+	}
+	totals := make(map[key]*Total)
+	add := func(player string, day time.Time, d time.Duration) {
+		k := key{strings.ToLower(player), day}
+		t := totals[k]
+		if t == nil {
+			t = &Total{Day: day}
+			totals[k] = t
+		}
+		t.Player = player
+		t.Sessions++
+		t.Playtime += d
+	}
+
+	for i := range sessions {
+		s := &sessions[i]
+		end := s.End
+		if end.IsZero() || end.After(now) {
+			end = now
+		}
+		start := s.Start
+		if start.Before(since) {
+			start = since
+		}
+		if !end.After(start) {
+			continue
+		}
+		if !byDay {
+			add(s.Player, time.Time{}, end.Sub(start))
+			continue
+		}
+		for day := midnight(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+			from, to := start, end
+			if from.Before(day) {
+				from = day
+			}
+			if next := day.AddDate(0, 0, 1); to.After(next) {
+				to = next
+			}
+			if to.After(from) {
+				add(s.Player, day, to.Sub(from))
+			}
+		}
+	}
+
+	out := make([]Total, 0, len(totals))
+	for _, t := range totals {
+		out = append(out, *t)
+	}
+	slices.SortFunc(out, func(a, b Total) int {
+		return cmp.Or(a.Day.Compare(b.Day), cmp.Compare(b.Playtime, a.Playtime), strings.Compare(a.Player, b.Player))
+	})
+	return out
+}
+
+// midnight returns the local midnight that starts t's day.
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// sinceDays matches a report window in days or weeks: "7d", "2w".
+var sinceDays = regexp.MustCompile(`^(\d+)([dw])$`)
+
+// ParseSince reads the start of a report window: a number of days ("7d")
+// or weeks ("2w") counting today, a duration ("12h"), or a date
+// ("2026-10-01").
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	if m := sinceDays.FindStringSubmatch(s); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n < 1 {
+			return time.Time{}, fmt.Errorf("since %q: count at least one day", s)
+		}
+		if m[2] == "w" {
+			n *= 7
+		}
+		return midnight(now).AddDate(0, 0, 1-n), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("since %q: use days (7d), weeks (2w), a duration (12h) or a date (2026-10-01)", s)
+}
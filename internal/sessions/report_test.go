@@ -0,0 +1,98 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlayers(t *testing.T) {
+	now := at("2026-10-18", "12:00:00")
+	sessions := []Session{
+		{"Alex", at("2026-10-16", "10:00:00"), at("2026-10-16", "11:00:00")},
+		{"steve", at("2026-10-17", "10:00:00"), at("2026-10-17", "10:30:00")},
+		{"Kid", at("2026-10-17", "18:00:00"), at("2026-10-17", "19:00:00")},
+		{"Steve", at("2026-10-18", "11:00:00"), time.Time{}},
+	}
+	got := Players(sessions, now)
+	want := []Player{
+		{Name: "Steve", Online: true, LastSeen: at("2026-10-18", "11:00:00"), Sessions: 2, Playtime: 90 * time.Minute},
+		{Name: "Kid", LastSeen: at("2026-10-17", "19:00:00"), Sessions: 1, Playtime: time.Hour},
+		{Name: "Alex", LastSeen: at("2026-10-16", "11:00:00"), Sessions: 1, Playtime: time.Hour},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Players() = %v, want %v", got, want)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Name != w.Name || g.Online != w.Online || !g.LastSeen.Equal(w.LastSeen) || g.Sessions != w.Sessions || g.Playtime != w.Playtime {
+			t.Errorf("Players()[%d] = %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestPlaytime(t *testing.T) {
+	now := at("2026-10-18", "12:00:00")
+	since := at("2026-10-17", "00:00:00")
+	sessions := []Session{
+		{"Alex", at("2026-10-16", "23:00:00"), at("2026-10-17", "01:00:00")},  // starts before the window
+		{"Steve", at("2026-10-17", "23:30:00"), at("2026-10-18", "00:30:00")}, // crosses midnight
+		{"steve", at("2026-10-18", "11:00:00"), time.Time{}},                  // online now
+	}
+
+	total := Playtime(sessions, since, now, false)
+	wantTotal := []Total{
+		{Player: "steve", Sessions: 2, Playtime: 2 * time.Hour},
+		{Player: "Alex", Sessions: 1, Playtime: time.Hour},
+	}
+	checkTotals(t, "total", total, wantTotal)
+
+	byDay := Playtime(sessions, since, now, true)
+	wantByDay := []Total{
+		{Player: "Alex", Day: at("2026-10-17", "00:00:00"), Sessions: 1, Playtime: time.Hour},
+		{Player: "Steve", Day: at("2026-10-17", "00:00:00"), Sessions: 1, Playtime: 30 * time.Minute},
+		{Player: "steve", Day: at("2026-10-18", "00:00:00"), Sessions: 2, Playtime: 90 * time.Minute},
+	}
+	checkTotals(t, "by day", byDay, wantByDay)
+}
+
+func checkTotals(t *testing.T, label string, got, want []Total) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: Playtime() = %+v, want %+v", label, got, want)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Player != w.Player || !g.Day.Equal(w.Day) || g.Sessions != w.Sessions || g.Playtime != w.Playtime {
+			t.Errorf("%s: Playtime()[%d] = %+v, want %+v", label, i, g, w)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := at("2026-10-18", "15:30:00")
+	tests := []struct {
+		in   string
+		want time.Time // zero for an error
+	}{
+		{"1d", at("2026-10-18", "00:00:00")},
+		{"7d", at("2026-10-12", "00:00:00")},
+		{"2w", at("2026-10-05", "00:00:00")},
+		{"12h", at("2026-10-18", "03:30:00")},
+		{"2026-10-01", at("2026-10-01", "00:00:00")},
+		{"0d", time.Time{}},
+		{"-3h", time.Time{}},
+		{"last week", time.Time{}},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.in, now)
+		if tt.want.IsZero() {
+			if err == nil {
+				t.Errorf("ParseSince(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
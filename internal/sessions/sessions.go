@@ -0,0 +1,246 @@
+// Package sessions works out who played and for how long from the join and
+// leave lines in the server logs. Sessions from rotated logs are kept in
+// sessions.json, so playtime outlives the logs themselves.
+package sessions
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+)
+
+// StoreFile holds the sessions read from rotated logs, in the server
+// directory.
+const StoreFile = "sessions.json"
+
+// serverStarting is the first line a starting server logs. Players still
+// online at that point left when the server went down.
+var serverStarting = regexp.MustCompile(`^Starting minecraft server version`)
+
+// Session is one stretch of a player being online.
+type Session struct {
+	Player string    `json:"player"`
+	Start  time.Time `json:"start"`
+	// End is zero while the player is online.
+	End time.Time `json:"end,omitzero"`
+}
+
+// Duration returns how long the session lasted, or has lasted by now for
+// a player still online.
+func (s *Session) Duration(now time.Time) time.Duration {
+	end := s.End
+	if end.IsZero() {
+		end = now
+	}
+	return max(end.Sub(s.Start), 0)
+}
+
+// store is the on-disk form of StoreFile. Online and Last are the state at
+// the end of the last rotated log, so a player online when the log rolled
+// over at midnight carries on into the next one.
+type store struct {
+	Sessions []Session `json:"sessions"`
+	// Scanned names the rotated logs already read.
+	Scanned []string  `json:"scanned"`
+	Online  []Session `json:"online,omitempty"`
+	Last    time.Time `json:"last,omitzero"`
+}
+
+// Load returns every session in serverDir's logs, oldest first. Rotated
+// logs are read once and their sessions saved; latest.log, which is still
+// being written, is read every time. Players still online have sessions
+// with no End, unless the server isn't running, in which case their
+// sessions end at the last line it logged.
+func Load(serverDir string, running bool) ([]Session, error) {
+	st, err := loadStore(serverDir)
+	if err != nil {
+		return nil, err
+	}
+	parser, _ := events.LoadParser(serverDir)
+	tr := newTracker(st)
+
+	logs, err := events.RotatedLogs(serverDir)
+	if err != nil {
+		return nil, err
+	}
+	scanned := make([]string, 0, len(logs))
+	changed := false
+	for _, l := range logs {
+		scanned = append(scanned, l.Name)
+		if slices.Contains(st.Scanned, l.Name) {
+			continue
+		}
+		if err := tr.readFile(filepath.Join(serverDir, "logs", l.Name), l.Date, parser); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	// Forget logs that have since been deleted; their sessions stay.
+	if changed || len(scanned) != len(st.Scanned) {
+		if err := tr.store(scanned).save(serverDir); err != nil {
+			return nil, err
+		}
+	}
+
+	latest := filepath.Join(serverDir, "logs", "latest.log")
+	info, err := os.Stat(latest)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading latest.log: %w", err)
+	default:
+		if err := tr.readFile(latest, info.ModTime(), parser); err != nil {
+			return nil, err
+		}
+	}
+	if !running {
+		tr.closeAll(tr.last)
+	}
+	return tr.sessions(), nil
+}
+
+func loadStore(serverDir string) (*store, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, StoreFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return &store{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", StoreFile, err)
+	}
+	var st store
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", StoreFile, err)
+	}
+	return &st, nil
+}
+
+func (st *store) save(serverDir string) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", StoreFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(serverDir, StoreFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", StoreFile, err)
+	}
+	return nil
+}
+
+// tracker follows joins and leaves through the logs in order.
+type tracker struct {
+	closed []Session
+	// open holds the sessions of players online, keyed by lower-cased
+	// name: the server treats names case-insensitively.
+	open map[string]Session
+	// last is the time of the last line read.
+	last time.Time
+}
+
+func newTracker(st *store) *tracker {
+	t := &tracker{closed: st.Sessions, open: make(map[string]Session), last: st.Last}
+	for _, s := range st.Online {
+		t.open[strings.ToLower(s.Player)] = s
+	}
+	return t
+}
+
+// readFile reads the joins and leaves in a log whose last line was logged
+// on end's date.
+func (t *tracker) readFile(path string, end time.Time, parser *events.Parser) error {
+	lines, err := events.ReadLog(path, end)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		at := line.Time
+		if at.IsZero() {
+			continue
+		}
+		if _, level, msg, _ := events.SplitPrefix(line.Text); level == "INFO" && serverStarting.MatchString(msg) {
+			t.closeAll(t.last)
+		}
+		y, m, d := at.Date()
+		if ev, ok := parser.Parse(line.Text, time.Date(y, m, d, 0, 0, 0, 0, at.Location())); ok {
+			switch ev.Kind {
+			case events.Join:
+				t.join(ev.Player, at)
+			case events.Leave:
+				t.leave(ev.Player, at)
+			case events.ServerStopping:
+				t.closeAll(at)
+			default:
+			}
+		}
+		t.last = at
+	}
+	return nil
+}
+
+// join starts a session. A player who joins again without having left
+// (the leave line was lost) ends their earlier session there.
+func (t *tracker) join(player string, at time.Time) {
+	key := strings.ToLower(player)
+	if s, ok := t.open[key]; ok {
+		s.End = at
+		t.closed = append(t.closed, s)
+	}
+	t.open[key] = Session{Player: player, Start: at}
+}
+
+func (t *tracker) leave(player string, at time.Time) {
+	key := strings.ToLower(player)
+	if s, ok := t.open[key]; ok {
+		s.End = at
+		t.closed = append(t.closed, s)
+		delete(t.open, key)
+	}
+}
+
+// closeAll ends every open session at at: the server stopped.
+func (t *tracker) closeAll(at time.Time) {
+	for _, s := range t.online() {
+		s.End = at
+		if s.End.Before(s.Start) {
+			s.End = s.Start
+		}
+		t.closed = append(t.closed, s)
+	}
+	clear(t.open)
+}
+
+// online returns the open sessions, oldest first.
+func (t *tracker) online() []Session {
+	out := make([]Session, 0, len(t.open))
+	for _, s := range t.open {
+		out = append(out, s)
+	}
+	sortSessions(out)
+	return out
+}
+
+// sessions returns every session, closed and open, oldest first.
+func (t *tracker) sessions() []Session {
+	out := slices.Concat(t.closed, t.online())
+	sortSessions(out)
+	return out
+}
+
+// store returns the tracker's state for saving after reading the rotated
+// logs scanned.
+func (t *tracker) store(scanned []string) *store {
+	return &store{Sessions: t.closed, Scanned: scanned, Online: t.online(), Last: t.last}
+}
+
+func sortSessions(s []Session) {
+	slices.SortStableFunc(s, func(a, b Session) int {
+		return cmp.Or(a.Start.Compare(b.Start), strings.Compare(a.Player, b.Player))
+	})
+}
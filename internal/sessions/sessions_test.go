@@ -0,0 +1,131 @@
+package sessions
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeLog(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, "logs", name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	if filepath.Ext(name) != ".gz" {
+		if _, err := f.WriteString(content); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func at(day, clock string) time.Time {
+	t, err := time.ParseInLocation(time.DateTime, day+" "+clock, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// The first log runs past midnight, so it rolled over while Steve was
+// online; the server then crashed with Alex online and started again.
+const (
+	log1 = `[20:00:00] [Server thread/INFO]: Starting minecraft server version 1.21.4
+[20:00:05] [Server thread/INFO]: Done (4.2s)! For help, type "help"
+[20:10:00] [Server thread/INFO]: Steve joined the game
+[20:15:00] [Server thread/INFO]: <Steve> Alex joined the game
+[21:00:00] [Server thread/INFO]: Steve left the game
+[23:30:00] [Server thread/INFO]: Steve joined the game
+[00:00:10] [Server thread/INFO]: <Steve> happy new day
+`
+	log2 = `[00:00:20] [Server thread/INFO]: Alex joined the game
+[00:30:00] [Server thread/INFO]: Steve left the game
+[01:00:00] [Server thread/INFO]: Saving chunks for level 'ServerLevel[world]'/minecraft:overworld
+`
+	latest = `[09:00:00] [Server thread/INFO]: Starting minecraft server version 1.21.4
+[09:00:05] [Server thread/INFO]: Done (4.2s)! For help, type "help"
+[09:05:00] [Server thread/INFO]: Kid joined the game
+[09:20:00] [Server thread/INFO]: STEVE joined the game
+[09:40:00] [Server thread/INFO]: Steve left the game
+`
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeLog(t, dir, "2026-10-16-1.log.gz", log1)
+	writeLog(t, dir, "2026-10-16-2.log.gz", log2)
+	writeLog(t, dir, "latest.log", latest)
+	mtime := at("2026-10-17", "09:40:00")
+	if err := os.Chtimes(filepath.Join(dir, "logs", "latest.log"), mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rotated logs are dated by their last line, so the first spans
+	// the 15th and 16th. Alex's session ends at the last line before the
+	// restart.
+	want := []Session{
+		{"Steve", at("2026-10-15", "20:10:00"), at("2026-10-15", "21:00:00")},
+		{"Steve", at("2026-10-15", "23:30:00"), at("2026-10-16", "00:30:00")},
+		{"Alex", at("2026-10-16", "00:00:20"), at("2026-10-16", "01:00:00")},
+		{"Kid", at("2026-10-17", "09:05:00"), time.Time{}},
+		{"STEVE", at("2026-10-17", "09:20:00"), at("2026-10-17", "09:40:00")},
+	}
+	check := func(label string, got []Session) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d sessions %v, want %d", label, len(got), got, len(want))
+		}
+		for i := range want {
+			if got[i].Player != want[i].Player || !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+				t.Errorf("%s: session %d = %v, want %v", label, i, got[i], want[i])
+			}
+		}
+	}
+
+	got, err := Load(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("first load", got)
+
+	// Rotated logs are read once; their sessions survive the logs.
+	if err := os.Remove(filepath.Join(dir, "logs", "2026-10-16-1.log.gz")); err != nil {
+		t.Fatal(err)
+	}
+	got, err = Load(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("after deleting a log", got)
+
+	// With the server down, nobody is online.
+	got, err = Load(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid := got[3]; !kid.End.Equal(at("2026-10-17", "09:40:00")) {
+		t.Errorf("Kid's session with the server down ends %v, want the last log line", kid.End)
+	}
+}
+
+func TestLoadWithoutLogs(t *testing.T) {
+	got, err := Load(t.TempDir(), true)
+	if err != nil || len(got) != 0 {
+		t.Errorf("Load() with no logs = %v, %v; want nothing", got, err)
+	}
+}
@@ -19,6 +19,7 @@ type InstallSummary struct {
 	ChatFilter   bool
 	PlayitSetup  bool
 	BunEnabled   bool
+	LANBroadcast bool
 	LicenseLabel string
 	InitSystem   string
 }
@@ -77,10 +78,13 @@ func (u *UI) PrintInstallSummary(s *InstallSummary) {
 		fmt.Println()
 	}
 
-	if s.ServerType == "paper" && s.ChatFilter {
+	if s.ChatFilter {
 		fmt.Println(u.colorize(colorCyan+colorBold, "  Chat Filter:"))
 		fmt.Printf("    Blocked words list: %s\n", u.Bold(s.ServerDir+"/blocked-words.txt"))
 		fmt.Println("    Edit the list to customize for your family")
+		if s.ServerType != "paper" {
+			fmt.Printf("    Filter chat with:   %s\n", u.Bold("mc-dad-server daemon --chat-filter"))
+		}
 		fmt.Println()
 	}
 
@@ -110,6 +114,9 @@ func (u *UI) PrintInstallSummary(s *InstallSummary) {
 		u.Bold(fmt.Sprintf("localhost:%d", s.Port)))
 	fmt.Printf("       Or your %s (same network)\n",
 		u.Bold(fmt.Sprintf("local IP:%d", s.Port)))
+	if s.LANBroadcast {
+		fmt.Println("       It also appears under LAN Worlds in the Multiplayer menu")
+	}
 	fmt.Println()
 	fmt.Println(u.colorize(colorGreen+colorBold, divider))
 	fmt.Println()
@@ -0,0 +1,163 @@
+package vote
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// historyFile is the JSON-lines log of completed votes in the server dir.
+const historyFile = "vote-history.jsonl"
+
+// Record is one completed vote as stored in the history file.
+type Record struct {
+	Time       time.Time      `json:"time"`
+	Candidates []string       `json:"candidates"`
+	Votes      map[string]int `json:"votes"`
+	// Mode is the voting mode, omitted for plurality.
+	Mode Mode `json:"mode,omitempty"`
+	// Ballots maps each player who voted to the map they voted for, or
+	// ranked first.
+	Ballots map[string]string `json:"ballots,omitempty"`
+	// Choices holds the full ranking or approvals of ballots that named
+	// more than one map.
+	Choices map[string][]string `json:"choices,omitempty"`
+	Winner  string              `json:"winner"`
+	Voters  int                 `json:"voters"`
+}
+
+// AppendHistory appends rec to the vote history in serverDir.
+func AppendHistory(serverDir string, rec *Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshaling vote record: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(serverDir, historyFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening vote history: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing vote history: %w", err)
+	}
+	return f.Close()
+}
+
+// LoadHistory reads every recorded vote in serverDir, oldest first. A missing
+// history file yields no records and no error; malformed lines are skipped so
+// one bad write cannot hide the rest of the history.
+func LoadHistory(serverDir string) ([]Record, error) {
+	f, err := os.Open(filepath.Join(serverDir, historyFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening vote history: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return records, fmt.Errorf("reading vote history: %w", err)
+	}
+	return records, nil
+}
+
+// RecentWinners returns the distinct winners of the last n votes, most
+// recent first.
+func RecentWinners(records []Record, n int) []string {
+	var out []string
+	for i := len(records) - 1; i >= 0 && len(out) < n; i-- {
+		if w := records[i].Winner; !slices.Contains(out, w) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// Plays counts how many votes each map has won.
+func Plays(records []Record) map[string]int {
+	plays := make(map[string]int)
+	for i := range records {
+		plays[records[i].Winner]++
+	}
+	return plays
+}
+
+// MapStats summarises a map's record in votes.
+type MapStats struct {
+	Name string
+	// Offered is how many votes the map was a candidate in.
+	Offered int
+	// Wins is how many of those it won, and so was played.
+	Wins int
+	// Votes is the total votes cast for it.
+	Votes      int
+	LastPlayed time.Time
+}
+
+// WinRate is the fraction of the votes the map was offered in that it won.
+func (s *MapStats) WinRate() float64 {
+	if s.Offered == 0 {
+		return 0
+	}
+	return float64(s.Wins) / float64(s.Offered)
+}
+
+// Stats summarises records per map, most played first. Maps in pool that
+// have never been offered are included with zero counts.
+func Stats(records []Record, pool []string) []MapStats {
+	byName := make(map[string]*MapStats)
+	get := func(name string) *MapStats {
+		s, ok := byName[name]
+		if !ok {
+			s = &MapStats{Name: name}
+			byName[name] = s
+		}
+		return s
+	}
+	for _, name := range pool {
+		get(name)
+	}
+	for i := range records {
+		rec := &records[i]
+		for _, c := range rec.Candidates {
+			s := get(c)
+			s.Offered++
+			s.Votes += rec.Votes[c]
+		}
+		if rec.Winner != "" {
+			s := get(rec.Winner)
+			s.Wins++
+			if rec.Time.After(s.LastPlayed) {
+				s.LastPlayed = rec.Time
+			}
+		}
+	}
+
+	out := make([]MapStats, 0, len(byName))
+	for _, s := range byName {
+		out = append(out, *s)
+	}
+	slices.SortFunc(out, func(a, b MapStats) int {
+		if a.Wins != b.Wins {
+			return b.Wins - a.Wins
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return out
+}
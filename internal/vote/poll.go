@@ -0,0 +1,130 @@
+package vote
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+// Option is one answer in a poll, with the console commands run if it
+// wins.
+type Option struct {
+	Label    string
+	Commands []string
+}
+
+// ParseOption parses "Label=command; command" as used by the poll command.
+// An option with no commands is allowed, for answers such as "Keep it".
+func ParseOption(s string) (Option, error) {
+	label, cmds, _ := strings.Cut(s, "=")
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return Option{}, fmt.Errorf("option %q: missing label before '='", s)
+	}
+	opt := Option{Label: label}
+	for c := range strings.SplitSeq(cmds, ";") {
+		// Commands are sent from the console, where the slash is optional.
+		if c = strings.TrimPrefix(strings.TrimSpace(c), "/"); c != "" {
+			opt.Commands = append(opt.Commands, c)
+		}
+	}
+	return opt, nil
+}
+
+// PollConfig configures a poll.
+type PollConfig struct {
+	Question  string
+	Options   []Option
+	Duration  time.Duration
+	Mode      Mode
+	OpWeight  int
+	ServerDir string
+	Manager   management.ServerManager
+	Output    *ui.UI
+}
+
+// RunPoll asks players a question in chat and runs the commands of the
+// winning option. If no one votes, nothing is run and the result has no
+// winner.
+func RunPoll(ctx context.Context, cfg *PollConfig) (*Result, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("poll config is nil")
+	}
+	if cfg.Manager == nil {
+		return nil, fmt.Errorf("poll config Manager is nil")
+	}
+	if cfg.Output == nil {
+		return nil, fmt.Errorf("poll config Output is nil")
+	}
+	if cfg.ServerDir == "" {
+		return nil, fmt.Errorf("poll config ServerDir is empty")
+	}
+	if cfg.Duration <= 0 {
+		return nil, fmt.Errorf("poll duration must be positive")
+	}
+	if strings.TrimSpace(cfg.Question) == "" {
+		return nil, fmt.Errorf("poll question is empty")
+	}
+	if len(cfg.Options) < 2 {
+		return nil, fmt.Errorf("a poll needs at least two options")
+	}
+	mode, err := ParseMode(string(cfg.Mode))
+	if err != nil {
+		return nil, err
+	}
+
+	choices := make([]choice, len(cfg.Options))
+	for i, o := range cfg.Options {
+		if slices.ContainsFunc(choices[:i], func(c choice) bool { return strings.EqualFold(c.name, o.Label) }) {
+			return nil, fmt.Errorf("option %q is listed twice", o.Label)
+		}
+		choices[i] = choice{name: o.Label, label: o.Label}
+	}
+
+	cfg.Output.Info("Starting %s poll %q with %d options for %s", mode, cfg.Question, len(choices), cfg.Duration)
+
+	box := &ballotBox{
+		title:     cfg.Question,
+		choices:   choices,
+		mode:      mode,
+		duration:  cfg.Duration,
+		weigh:     opWeigher(mode, cfg.OpWeight, cfg.ServerDir, cfg.Output),
+		serverDir: cfg.ServerDir,
+		mgr:       cfg.Manager,
+		output:    cfg.Output,
+		needVotes: true,
+	}
+	outcome, playerVotes, err := box.run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if outcome.Winner == "" {
+		return &Result{}, nil
+	}
+	result := &Result{
+		Winner: outcome.Winner,
+		Votes:  outcome.Final(),
+		Rounds: outcome.Rounds,
+		Voters: len(playerVotes),
+	}
+
+	winner := cfg.Options[slices.IndexFunc(cfg.Options, func(o Option) bool { return o.Label == outcome.Winner })]
+	if len(winner.Commands) == 0 {
+		return result, nil
+	}
+	if err := countdown(ctx, cfg.Manager, winner.Label); err != nil {
+		return result, err
+	}
+	for _, c := range winner.Commands {
+		cfg.Output.Info("Running: %s", c)
+		if err := cfg.Manager.SendCommand(ctx, c); err != nil {
+			return result, fmt.Errorf("running %q: %w", c, err)
+		}
+	}
+	return result, nil
+}
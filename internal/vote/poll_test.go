@@ -0,0 +1,64 @@
+package vote
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+func TestParseOption(t *testing.T) {
+	tests := []struct {
+		in       string
+		label    string
+		commands []string
+		wantErr  bool
+	}{
+		{in: "Day=time set day", label: "Day", commands: []string{"time set day"}},
+		{in: "Hard = difficulty hard; say Good luck!", label: "Hard", commands: []string{"difficulty hard", "say Good luck!"}},
+		{in: "Rain=/weather rain", label: "Rain", commands: []string{"weather rain"}},
+		{in: "Keep it", label: "Keep it"},
+		{in: "Keep it=", label: "Keep it"},
+		{in: "=time set day", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseOption(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseOption(%q) = %+v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseOption(%q) error = %v", tt.in, err)
+			continue
+		}
+		if got.Label != tt.label || !slices.Equal(got.Commands, tt.commands) {
+			t.Errorf("ParseOption(%q) = %+v, want %q %q", tt.in, got, tt.label, tt.commands)
+		}
+	}
+}
+
+func TestRunPollNoVotes(t *testing.T) {
+	mgr := newManager()
+	result, err := RunPoll(t.Context(), &PollConfig{
+		Question:  "Weather?",
+		Options:   []Option{{Label: "Rain", Commands: []string{"weather rain"}}, {Label: "Clear", Commands: []string{"weather clear"}}},
+		Duration:  50 * time.Millisecond,
+		ServerDir: t.TempDir(),
+		Manager:   mgr,
+		Output:    ui.NewWriter(&bytes.Buffer{}, false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Winner != "" {
+		t.Errorf("Winner = %q with no votes, want none", result.Winner)
+	}
+	if !sentContaining(mgr, "No votes") || sentContaining(mgr, "weather") {
+		t.Errorf("sent %q, want no votes announced and nothing run", mgr.Commands())
+	}
+}
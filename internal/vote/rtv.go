@@ -0,0 +1,262 @@
+package vote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/mappool"
+)
+
+// Rock-the-vote defaults.
+const (
+	// DefaultRTVThreshold is the fraction of online players who must ask
+	// for a vote before one starts.
+	DefaultRTVThreshold = 0.6
+	// DefaultRTVCooldown is the least time between the end of one vote and
+	// the start of the next.
+	DefaultRTVCooldown = 10 * time.Minute
+)
+
+// RTVConfig configures rock the vote.
+type RTVConfig struct {
+	// Vote is the vote to run. Nominated is filled in from chat.
+	Vote      Config
+	Threshold float64
+	Cooldown  time.Duration
+}
+
+// RockTheVote lets players start map votes from chat. "!rtv" (or "!vote")
+// asks for a vote, which starts once enough of the online players have
+// asked; "!nominate <map>" puts a map on the next ballot. It returns when
+// ctx is cancelled.
+func RockTheVote(ctx context.Context, cfg *RTVConfig) error {
+	vc := cfg.Vote
+	if vc.Manager == nil || vc.Output == nil || vc.ServerDir == "" {
+		return errors.New("rock the vote needs a manager, output and server directory")
+	}
+	q, ok := vc.Manager.(management.Querier)
+	if !ok {
+		return errors.New("rock the vote needs RCON to count players")
+	}
+	if cfg.Threshold <= 0 || cfg.Threshold > 1 {
+		cfg.Threshold = DefaultRTVThreshold
+	}
+	if cfg.Cooldown < 0 {
+		cfg.Cooldown = DefaultRTVCooldown
+	}
+
+	stream := events.NewStream(vc.ServerDir)
+	parser, err := events.LoadParser(vc.ServerDir)
+	if err != nil {
+		vc.Output.Warn("Ignoring custom chat formats: %s", err)
+	}
+	stream.Parser = parser
+	evs := stream.Subscribe(ctx, events.FromEnd)
+
+	st := newRTV(cfg.Threshold, cfg.Cooldown)
+	done := make(chan error, 1)
+	for {
+		var ev events.Event
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-done:
+			if err != nil {
+				vc.Output.Warn("Map vote failed: %s", err)
+			}
+			st.finish(time.Now())
+			continue
+		case ev, ok = <-evs:
+			if !ok {
+				return nil
+			}
+		}
+
+		switch ev.Kind {
+		case events.Leave:
+			st.leave(ev.Player)
+			continue
+		case events.ServerStopping:
+			st.reset()
+			continue
+		case events.Chat:
+		default:
+			continue
+		}
+
+		verb, arg, _ := strings.Cut(strings.TrimSpace(ev.Message), " ")
+		switch strings.ToLower(verb) {
+		case "!rtv", "!vote":
+			list, err := management.ListPlayers(ctx, q)
+			if err != nil {
+				vc.Output.Warn("Rock the vote: counting players: %s", err)
+				continue
+			}
+			have, need, refusal := st.request(ev.Player, list.Online, time.Now())
+			if refusal != "" {
+				tell(ctx, vc.Manager, ev.Player, refusal, "red")
+				continue
+			}
+			if have < need {
+				say(ctx, vc.Manager, fmt.Sprintf("%s wants to vote for a new map (%d/%d). Type !rtv to agree!", ev.Player, have, need), "yellow")
+				continue
+			}
+			say(ctx, vc.Manager, "The players have spoken: time for a map vote!", "gold")
+			vote := vc
+			vote.Nominated = st.start()
+			go func() { done <- runRTVVote(ctx, &vote) }()
+
+		case "!nominate", "!nom":
+			pool, err := rtvPool(&vc)
+			if err != nil {
+				vc.Output.Warn("Rock the vote: %s", err)
+				continue
+			}
+			m, refusal := st.nominate(ev.Player, strings.TrimSpace(arg), pool, vc.MaxChoices)
+			if refusal != "" {
+				tell(ctx, vc.Manager, ev.Player, refusal, "red")
+				continue
+			}
+			say(ctx, vc.Manager, fmt.Sprintf("%s nominated %s for the next map vote", ev.Player, m.Label()), "yellow")
+		}
+	}
+}
+
+// runRTVVote runs a vote started from chat.
+func runRTVVote(ctx context.Context, cfg *Config) error {
+	_, err := RunVote(ctx, cfg)
+	return err
+}
+
+// rtvPool returns the maps that can be nominated.
+func rtvPool(cfg *Config) ([]mappool.Map, error) {
+	if cfg.Maps != nil {
+		return cfg.Maps, nil
+	}
+	pool, _, err := mappool.Playable(cfg.ServerDir)
+	return pool, err
+}
+
+// rtv tracks requests and nominations between votes.
+type rtv struct {
+	threshold float64
+	cooldown  time.Duration
+
+	// requesters and nominations are keyed by lower-cased player name:
+	// the server treats names case-insensitively.
+	requesters map[string]bool
+	// nominations are in the order they were made, one per player.
+	nominations []nomination
+	running     bool
+	lastVote    time.Time
+}
+
+type nomination struct {
+	player, name string
+}
+
+func newRTV(threshold float64, cooldown time.Duration) *rtv {
+	return &rtv{threshold: threshold, cooldown: cooldown, requesters: make(map[string]bool)}
+}
+
+// request records player asking for a vote with online players on the
+// server. It returns how many have asked and how many are needed, or the
+// reason to show the player when a vote can't be asked for now.
+func (s *rtv) request(player string, online int, now time.Time) (have, need int, refusal string) {
+	if s.running {
+		return 0, 0, "A map vote is already running"
+	}
+	if wait := s.lastVote.Add(s.cooldown).Sub(now); !s.lastVote.IsZero() && wait > 0 {
+		return 0, 0, "Next map vote in " + waitText(wait)
+	}
+	s.requesters[strings.ToLower(player)] = true
+	// The player asking is online, whatever a stale count says.
+	online = max(online, len(s.requesters))
+	need = max(int(math.Ceil(s.threshold*float64(online))), 1)
+	return len(s.requesters), need, ""
+}
+
+// nominate puts the map matching query onto the next ballot for player,
+// replacing their earlier nomination. query matches a map's name or label,
+// or a unique prefix of one, ignoring case. On refusal it returns the
+// reason to show the player, which never repeats what they typed.
+func (s *rtv) nominate(player, query string, pool []mappool.Map, maxChoices int) (m mappool.Map, refusal string) {
+	if s.running {
+		return m, "A map vote is already running"
+	}
+	if query == "" {
+		return m, "Usage: !nominate <map>"
+	}
+	m, err := mappool.Find(pool, query)
+	switch {
+	case errors.Is(err, mappool.ErrNoMap):
+		return m, "No map by that name"
+	case err != nil:
+		return m, "More than one map starts like that; type more of the name"
+	}
+
+	key := strings.ToLower(player)
+	s.nominations = slices.DeleteFunc(s.nominations, func(n nomination) bool { return n.player == key })
+	if slices.ContainsFunc(s.nominations, func(n nomination) bool { return n.name == m.Name }) {
+		return m, m.Label() + " is already nominated"
+	}
+	if maxChoices > 0 && len(s.nominations) >= maxChoices {
+		return m, "The ballot is already full of nominations"
+	}
+	s.nominations = append(s.nominations, nomination{player: key, name: m.Name})
+	return m, ""
+}
+
+// leave forgets a player's request when they log off. Their nomination
+// stands.
+func (s *rtv) leave(player string) {
+	delete(s.requesters, strings.ToLower(player))
+}
+
+// start marks a vote as running and returns the nominated maps.
+func (s *rtv) start() []string {
+	s.running = true
+	names := make([]string, len(s.nominations))
+	for i, n := range s.nominations {
+		names[i] = n.name
+	}
+	return names
+}
+
+// finish records the end of a vote, starting the cooldown.
+func (s *rtv) finish(now time.Time) {
+	s.running = false
+	s.reset()
+	s.lastVote = now
+}
+
+// reset drops requests and nominations.
+func (s *rtv) reset() {
+	clear(s.requesters)
+	s.nominations = nil
+}
+
+// waitText formats a cooldown for chat: "7m" or "45s".
+func waitText(d time.Duration) string {
+	if d >= time.Minute {
+		return fmt.Sprintf("%dm", int(math.Ceil(d.Minutes())))
+	}
+	return fmt.Sprintf("%ds", int(math.Ceil(d.Seconds())))
+}
+
+// say sends text to every player.
+func say(ctx context.Context, mgr management.ServerManager, text, color string) {
+	_ = mgr.SendCommand(ctx, management.Tellraw("@a", management.Text{Text: text, Color: color}))
+}
+
+// tell sends text to one player.
+func tell(ctx context.Context, mgr management.ServerManager, player, text, color string) {
+	_ = mgr.SendCommand(ctx, management.Tellraw(player, management.Text{Text: text, Color: color}))
+}
@@ -0,0 +1,199 @@
+package vote
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/mappool"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
+)
+
+func TestRTVRequest(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+	s := newRTV(0.6, 10*time.Minute)
+
+	// 60% of 4 online rounds up to 3.
+	for i, player := range []string{"Steve", "steve", "Alex"} {
+		have, need, refusal := s.request(player, 4, now)
+		if refusal != "" || need != 3 {
+			t.Fatalf("request %d = %d/%d %q", i, have, need, refusal)
+		}
+	}
+	if have, _, _ := s.request("Alex", 4, now); have != 2 {
+		t.Errorf("repeated requests counted: have = %d, want 2", have)
+	}
+
+	// Requests from players who left no longer count.
+	s.leave("ALEX")
+	if have, _, _ := s.request("Kid", 4, now); have != 2 {
+		t.Errorf("have = %d after a leave, want 2", have)
+	}
+	if have, need, _ := s.request("Alex", 4, now); have < need {
+		t.Fatalf("have %d, need %d: vote should start", have, need)
+	}
+
+	s.start()
+	if _, _, refusal := s.request("Steve", 4, now); refusal == "" {
+		t.Error("request during a vote accepted")
+	}
+
+	s.finish(now)
+	_, _, refusal := s.request("Steve", 4, now.Add(3*time.Minute))
+	if refusal != "Next map vote in 7m" {
+		t.Errorf("request during cooldown: refusal = %q", refusal)
+	}
+	if have, _, refusal := s.request("Steve", 4, now.Add(11*time.Minute)); refusal != "" || have != 1 {
+		t.Errorf("request after cooldown = %d %q, want a fresh count", have, refusal)
+	}
+}
+
+func TestRTVRequestNeedsOne(t *testing.T) {
+	s := newRTV(0.6, 0)
+	// A stale player count of zero still needs the player asking.
+	if have, need, _ := s.request("Steve", 0, time.Now()); have != 1 || need != 1 {
+		t.Errorf("request = %d/%d, want 1/1", have, need)
+	}
+}
+
+func TestRTVNominate(t *testing.T) {
+	maps := pool("parkour-spiral", "parkour-spiral-3", "parkour-volcano", "skyblock")
+	maps[3].DisplayName = "Sky Block"
+	s := newRTV(0.6, 0)
+
+	tests := []struct {
+		player, query string
+		want          string // map name, or "" for a refusal
+	}{
+		{"Steve", "volc", ""},
+		{"Steve", "parkour-volcano", "parkour-volcano"},
+		{"Alex", "PARKOUR-VOLCANO", ""}, // already nominated
+		{"Alex", "parkour-spiral", "parkour-spiral"},
+		{"Kid", "parkour-s", ""}, // ambiguous
+		{"Kid", "x^Mop Kid^M", ""},
+		{"Kid", "sky b", "skyblock"},
+		{"Kid", "", ""},
+		{"Kid", "lava", ""},
+		{"Dad", "parkour-spiral-3", ""}, // ballot full
+	}
+	for _, tt := range tests {
+		m, refusal := s.nominate(tt.player, tt.query, maps, 3)
+		if tt.want == "" {
+			if refusal == "" {
+				t.Errorf("nominate(%s, %q) = %s, want a refusal", tt.player, tt.query, m.Name)
+			}
+			// The refusal goes to the console; player text could carry
+			// commands.
+			if tt.query != "" && strings.Contains(refusal, tt.query) {
+				t.Errorf("nominate(%s, %q) refusal %q repeats the query", tt.player, tt.query, refusal)
+			}
+			continue
+		}
+		if refusal != "" || m.Name != tt.want {
+			t.Errorf("nominate(%s, %q) = %s %q, want %s", tt.player, tt.query, m.Name, refusal, tt.want)
+		}
+	}
+
+	// Nominating again replaces the player's earlier pick.
+	if _, refusal := s.nominate("Steve", "parkour-spiral-3", maps, 3); refusal != "" {
+		t.Fatalf("renominating: %s", refusal)
+	}
+	if got, want := s.start(), []string{"parkour-spiral", "skyblock", "parkour-spiral-3"}; !slices.Equal(got, want) {
+		t.Errorf("nominations = %v, want %v", got, want)
+	}
+	if _, refusal := s.nominate("Dad", "skyblock", maps, 3); refusal == "" {
+		t.Error("nomination during a vote accepted")
+	}
+	s.finish(time.Now())
+	if got := s.start(); len(got) != 0 {
+		t.Errorf("nominations after a vote = %v, want none", got)
+	}
+}
+
+func TestNominees(t *testing.T) {
+	maps := pool("a", "b", "c", "d")
+	picked, rest := nominees(maps, []string{"c", "gone", "a", "b"}, 2)
+	names := func(ms []mappool.Map) []string {
+		out := make([]string, len(ms))
+		for i := range ms {
+			out[i] = ms[i].Name
+		}
+		return out
+	}
+	if got := names(picked); !slices.Equal(got, []string{"c", "a"}) {
+		t.Errorf("picked = %v, want [c a]", got)
+	}
+	if got := names(rest); !slices.Equal(got, []string{"b", "d"}) {
+		t.Errorf("rest = %v, want [b d]", got)
+	}
+}
+
+// newManager returns a manager that answers "list" with two players
+// online.
+func newManager() *management.MockManager {
+	mgr := management.NewMockManager()
+	mgr.Replies["list"] = "There are 2 of a max of 20 players online: Steve, Alex"
+	return mgr
+}
+
+// sentContaining reports whether a command containing s was sent to mgr.
+func sentContaining(mgr *management.MockManager, s string) bool {
+	return slices.ContainsFunc(mgr.Commands(), func(c string) bool { return strings.Contains(c, s) })
+}
+
+func TestRockTheVoteAnnouncesRequests(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "logs", "latest.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := newManager()
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- RockTheVote(ctx, &RTVConfig{
+			Vote: Config{
+				Duration:   time.Second,
+				MaxChoices: 3,
+				Maps:       pool("a", "b"),
+				ServerDir:  dir,
+				Manager:    mgr,
+				Output:     ui.NewWriter(&bytes.Buffer{}, false),
+			},
+			Threshold: 1,
+		})
+	}()
+
+	// Give the follower time to open the log at its end.
+	time.Sleep(500 * time.Millisecond)
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("[15:00:01] [Server thread/INFO]: <Steve> !rtv\n" +
+		"[15:00:02] [Server thread/INFO]: <Alex> !nominate b\n")
+	_ = f.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !(sentContaining(mgr, "Steve wants to vote for a new map (1/2)") && sentContaining(mgr, "Alex nominated b")) {
+		if time.Now().After(deadline) {
+			t.Fatalf("announcements not sent; got %q", mgr.Commands())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("RockTheVote() = %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package vote
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Mode is how votes are counted.
+type Mode string
+
+// Voting modes.
+const (
+	// Plurality: one choice each; the most votes wins.
+	Plurality Mode = "plurality"
+	// Runoff is instant-runoff: players rank maps ("3 1 2"). The map with
+	// the fewest first preferences is eliminated and its ballots pass to
+	// their next choice, until one map has a majority.
+	Runoff Mode = "runoff"
+	// Approval: players name every map they would play ("1 3"); the most
+	// approved wins.
+	Approval Mode = "approval"
+	// Weighted is plurality with operators' votes counting extra.
+	Weighted Mode = "weighted"
+)
+
+// Modes lists the voting modes.
+var Modes = []Mode{Plurality, Runoff, Approval, Weighted}
+
+// ParseMode returns the named mode. An empty name is Plurality.
+func ParseMode(s string) (Mode, error) {
+	if s == "" {
+		return Plurality, nil
+	}
+	m := Mode(strings.ToLower(s))
+	if !slices.Contains(Modes, m) {
+		return "", fmt.Errorf("unknown voting mode %q: use plurality, runoff, approval or weighted", s)
+	}
+	return m, nil
+}
+
+// ranked reports whether a ballot in this mode may name several maps.
+func (m Mode) ranked() bool {
+	return m == Runoff || m == Approval
+}
+
+// instructions tells players how to vote.
+func (m Mode) instructions() string {
+	switch m {
+	case Runoff:
+		return "Rank the maps, favourite first (e.g. 3 1 2):"
+	case Approval:
+		return "Type every map you'd play (e.g. 1 3):"
+	case Plurality, Weighted:
+	}
+	return "Type a number to vote:"
+}
+
+// Ballot is one player's vote.
+type Ballot struct {
+	Player string
+	// Choices are candidate names, most preferred first. Plurality and
+	// weighted ballots have one.
+	Choices []string
+	// Weight is how many votes the ballot counts as.
+	Weight int
+}
+
+// parseBallot reads a chat message as a vote for the given candidates. It
+// reports false for messages that aren't a valid vote in mode. Numbers may
+// be separated by spaces or commas; repeats are ignored.
+func parseBallot(mode Mode, msg string, candidates []string) ([]string, bool) {
+	fields := strings.FieldsFunc(msg, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 0 || (len(fields) > 1 && !mode.ranked()) {
+		return nil, false
+	}
+	var choices []string
+	for _, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 1 || n > len(candidates) {
+			return nil, false
+		}
+		if c := candidates[n-1]; !slices.Contains(choices, c) {
+			choices = append(choices, c)
+		}
+	}
+	return choices, true
+}
+
+// Round is one count of the ballots.
+type Round struct {
+	// Counts holds every candidate still standing, including those with
+	// no votes.
+	Counts map[string]int
+	// Eliminated are the candidates knocked out after this round of an
+	// instant-runoff count.
+	Eliminated []string
+}
+
+// Outcome is the result of counting a vote.
+type Outcome struct {
+	Winner string
+	// Rounds holds every count. Only instant-runoff has more than one.
+	Rounds []Round
+}
+
+// Final returns the counts of the last round.
+func (o *Outcome) Final() map[string]int {
+	return o.Rounds[len(o.Rounds)-1].Counts
+}
+
+// tieBreaker chooses among tied candidates.
+type tieBreaker func([]string) string
+
+// randomTieBreak picks a tied candidate at random.
+func randomTieBreak(tied []string) string {
+	return tied[rand.IntN(len(tied))]
+}
+
+// tally counts ballots for candidates in mode. With no ballots, a
+// candidate is chosen by breakTie from all of them.
+func tally(mode Mode, candidates []string, ballots []Ballot, breakTie tieBreaker) Outcome {
+	if mode == Runoff {
+		return runoff(candidates, ballots, breakTie)
+	}
+	counts := zeroCounts(candidates)
+	for _, b := range ballots {
+		choices := b.Choices
+		if mode != Approval {
+			choices = choices[:min(len(choices), 1)]
+		}
+		for _, c := range choices {
+			counts[c] += b.Weight
+		}
+	}
+	return Outcome{
+		Winner: breakTie(leaders(candidates, counts)),
+		Rounds: []Round{{Counts: counts}},
+	}
+}
+
+// runoff counts ranked ballots by instant-runoff. Each round, every ballot
+// counts for its highest-ranked candidate still standing; ballots with none
+// left are exhausted. A candidate with more than half the live votes wins.
+// Otherwise the candidates with the fewest votes are all eliminated, unless
+// that would leave nobody, in which case the tie is broken among them.
+func runoff(candidates []string, ballots []Ballot, breakTie tieBreaker) Outcome {
+	standing := slices.Clone(candidates)
+	var out Outcome
+	for {
+		counts := zeroCounts(standing)
+		live := 0
+		for _, b := range ballots {
+			for _, c := range b.Choices {
+				if slices.Contains(standing, c) {
+					counts[c] += b.Weight
+					live += b.Weight
+					break
+				}
+			}
+		}
+		round := Round{Counts: counts}
+
+		top := leaders(standing, counts)
+		if len(standing) == 1 || (live > 0 && 2*counts[top[0]] > live) {
+			out.Rounds = append(out.Rounds, round)
+			out.Winner = breakTie(top)
+			return out
+		}
+
+		lowest := trailers(standing, counts)
+		if len(lowest) == len(standing) {
+			// Everyone still standing is level.
+			out.Rounds = append(out.Rounds, round)
+			out.Winner = breakTie(lowest)
+			return out
+		}
+		round.Eliminated = lowest
+		out.Rounds = append(out.Rounds, round)
+		standing = slices.DeleteFunc(standing, func(c string) bool { return slices.Contains(lowest, c) })
+	}
+}
+
+func zeroCounts(candidates []string) map[string]int {
+	counts := make(map[string]int, len(candidates))
+	for _, c := range candidates {
+		counts[c] = 0
+	}
+	return counts
+}
+
+// leaders returns the candidates with the most votes, in candidate order.
+func leaders(candidates []string, counts map[string]int) []string {
+	best := counts[candidates[0]]
+	for _, c := range candidates {
+		best = max(best, counts[c])
+	}
+	return slices.DeleteFunc(slices.Clone(candidates), func(c string) bool { return counts[c] != best })
+}
+
+// trailers returns the candidates with the fewest votes, in candidate
+// order.
+func trailers(candidates []string, counts map[string]int) []string {
+	worst := counts[candidates[0]]
+	for _, c := range candidates {
+		worst = min(worst, counts[c])
+	}
+	return slices.DeleteFunc(slices.Clone(candidates), func(c string) bool { return counts[c] != worst })
+}
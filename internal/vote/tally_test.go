@@ -0,0 +1,211 @@
+package vote
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// first breaks ties deterministically for tests.
+func first(tied []string) string { return tied[0] }
+
+// ballot is a weight-1 ballot.
+func ballot(choices ...string) Ballot {
+	return Ballot{Choices: choices, Weight: 1}
+}
+
+// repeat returns n copies of b.
+func repeat(n int, b Ballot) []Ballot {
+	out := make([]Ballot, n)
+	for i := range out {
+		out[i] = b
+	}
+	return out
+}
+
+func TestParseMode(t *testing.T) {
+	for _, s := range []string{"", "plurality", "Runoff", "approval", "weighted"} {
+		if _, err := ParseMode(s); err != nil {
+			t.Errorf("ParseMode(%q) error = %v", s, err)
+		}
+	}
+	if m, _ := ParseMode(""); m != Plurality {
+		t.Errorf("ParseMode(\"\") = %q, want plurality", m)
+	}
+	if _, err := ParseMode("borda"); err == nil {
+		t.Error("ParseMode(borda) succeeded")
+	}
+}
+
+func TestParseBallot(t *testing.T) {
+	candidates := []string{"a", "b", "c"}
+	tests := []struct {
+		mode Mode
+		msg  string
+		want []string
+	}{
+		{Plurality, "2", []string{"b"}},
+		{Plurality, "2 1", nil},
+		{Plurality, "4", nil},
+		{Plurality, "gg", nil},
+		{Weighted, "3", []string{"c"}},
+		{Runoff, "3 1 2", []string{"c", "a", "b"}},
+		{Runoff, "3,1", []string{"c", "a"}},
+		{Runoff, "1 1 2", []string{"a", "b"}},
+		{Runoff, "1 0", nil},
+		{Runoff, "1 lol", nil},
+		{Approval, "1 3", []string{"a", "c"}},
+		{Approval, "2", []string{"b"}},
+		{Approval, "", nil},
+	}
+	for _, tt := range tests {
+		got, ok := parseBallot(tt.mode, tt.msg, candidates)
+		if ok != (tt.want != nil) || !slices.Equal(got, tt.want) {
+			t.Errorf("parseBallot(%s, %q) = %v, %v; want %v", tt.mode, tt.msg, got, ok, tt.want)
+		}
+	}
+}
+
+func TestTally(t *testing.T) {
+	candidates := []string{"a", "b", "c"}
+	op := Ballot{Choices: []string{"c"}, Weight: 3}
+
+	tests := []struct {
+		name    string
+		mode    Mode
+		ballots []Ballot
+		winner  string
+		counts  map[string]int // final round
+		rounds  int
+	}{
+		{
+			name:    "plurality clear winner",
+			mode:    Plurality,
+			ballots: slices.Concat(repeat(1, ballot("a")), repeat(5, ballot("b")), repeat(2, ballot("c"))),
+			winner:  "b",
+			counts:  map[string]int{"a": 1, "b": 5, "c": 2},
+			rounds:  1,
+		},
+		{
+			name:    "plurality single voter",
+			mode:    Plurality,
+			ballots: []Ballot{ballot("c")},
+			winner:  "c",
+			counts:  map[string]int{"a": 0, "b": 0, "c": 1},
+			rounds:  1,
+		},
+		{
+			name:    "plurality tie broken among the tied",
+			mode:    Plurality,
+			ballots: slices.Concat(repeat(3, ballot("b")), repeat(3, ballot("c"))),
+			winner:  "b",
+			counts:  map[string]int{"a": 0, "b": 3, "c": 3},
+			rounds:  1,
+		},
+		{
+			name:   "no votes",
+			mode:   Plurality,
+			winner: "a",
+			counts: map[string]int{"a": 0, "b": 0, "c": 0},
+			rounds: 1,
+		},
+		{
+			name:    "weighted op outvotes two players",
+			mode:    Weighted,
+			ballots: []Ballot{ballot("a"), ballot("a"), op},
+			winner:  "c",
+			counts:  map[string]int{"a": 2, "b": 0, "c": 3},
+			rounds:  1,
+		},
+		{
+			name:    "approval counts every choice",
+			mode:    Approval,
+			ballots: []Ballot{ballot("a", "b"), ballot("b", "c"), ballot("a")},
+			winner:  "a",
+			counts:  map[string]int{"a": 2, "b": 2, "c": 1},
+			rounds:  1,
+		},
+		{
+			name:    "runoff first-round majority",
+			mode:    Runoff,
+			ballots: slices.Concat(repeat(3, ballot("a", "b")), repeat(2, ballot("b"))),
+			winner:  "a",
+			counts:  map[string]int{"a": 3, "b": 2, "c": 0},
+			rounds:  1,
+		},
+		{
+			// a leads on first preferences, but c's voters prefer b.
+			name: "runoff transfers eliminated ballots",
+			mode: Runoff,
+			ballots: slices.Concat(
+				repeat(4, ballot("a")),
+				repeat(3, ballot("b", "a")),
+				repeat(2, ballot("c", "b")),
+			),
+			winner: "b",
+			counts: map[string]int{"a": 4, "b": 5},
+			rounds: 2,
+		},
+		{
+			name: "runoff exhausted ballots drop out",
+			mode: Runoff,
+			ballots: slices.Concat(
+				repeat(3, ballot("a")),
+				repeat(2, ballot("b")),
+				repeat(2, ballot("c")),
+			),
+			// b and c tie for last and both go; a then has every live vote.
+			winner: "a",
+			counts: map[string]int{"a": 3},
+			rounds: 2,
+		},
+		{
+			name:    "runoff all level",
+			mode:    Runoff,
+			ballots: []Ballot{ballot("b", "c"), ballot("c", "b")},
+			// a goes first with no votes, then b and c are level.
+			winner: "b",
+			counts: map[string]int{"b": 1, "c": 1},
+			rounds: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tally(tt.mode, candidates, tt.ballots, first)
+			if got.Winner != tt.winner {
+				t.Errorf("winner = %q, want %q", got.Winner, tt.winner)
+			}
+			if len(got.Rounds) != tt.rounds {
+				t.Errorf("rounds = %+v, want %d", got.Rounds, tt.rounds)
+			}
+			if final := got.Final(); !maps.Equal(final, tt.counts) {
+				t.Errorf("final counts = %v, want %v", final, tt.counts)
+			}
+		})
+	}
+}
+
+func TestTallyRunoffRounds(t *testing.T) {
+	ballots := slices.Concat(
+		repeat(4, ballot("a")),
+		repeat(3, ballot("b", "a")),
+		repeat(2, ballot("c", "b")),
+	)
+	got := tally(Runoff, []string{"a", "b", "c"}, ballots, first)
+	want := map[string]int{"a": 4, "b": 3, "c": 2}
+	if !maps.Equal(got.Rounds[0].Counts, want) || !slices.Equal(got.Rounds[0].Eliminated, []string{"c"}) {
+		t.Errorf("round 1 = %+v, want counts %v and c out", got.Rounds[0], want)
+	}
+	if len(got.Rounds[1].Eliminated) != 0 {
+		t.Errorf("final round eliminated %v", got.Rounds[1].Eliminated)
+	}
+}
+
+func TestRandomTieBreak(t *testing.T) {
+	tied := []string{"a", "b"}
+	for range 20 {
+		if w := randomTieBreak(tied); w != "a" && w != "b" {
+			t.Fatalf("randomTieBreak() = %q", w)
+		}
+	}
+}
@@ -1,33 +1,53 @@
 package vote
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"math/rand/v2"
-	"path/filepath"
-	"strconv"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/KevinTCoughlin/mc-dad-server/internal/events"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/management"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/mappool"
+	"github.com/KevinTCoughlin/mc-dad-server/internal/players"
 	"github.com/KevinTCoughlin/mc-dad-server/internal/ui"
 )
 
 // Config configures a map vote session.
 type Config struct {
-	Maps       []string      // candidate map pool
+	Maps       []mappool.Map // candidate map pool; nil uses the playable maps in ServerDir
 	Duration   time.Duration // vote window
 	MaxChoices int           // maps shown per vote
-	ServerDir  string
-	Manager    management.ServerManager
-	Output     *ui.UI
+	// ExcludeRecent leaves the winners of the last few votes out of the
+	// candidates, so the same map isn't played again and again.
+	ExcludeRecent int
+	// Nominated names maps players asked for; they are on the ballot
+	// ahead of the random picks, recent winners or not.
+	Nominated []string
+	// Mode is how votes are counted; empty means Plurality.
+	Mode Mode
+	// OpWeight is how many votes an operator's ballot counts as in
+	// Weighted mode; zero means DefaultOpWeight.
+	OpWeight  int
+	ServerDir string
+	Manager   management.ServerManager
+	Output    *ui.UI
 }
 
+// DefaultOpWeight is how many votes an operator's ballot counts as in
+// Weighted mode.
+const DefaultOpWeight = 2
+
 // Result holds the outcome of a completed vote.
 type Result struct {
 	Winner string
+	// Votes are the counts of the final round.
 	Votes  map[string]int
+	Rounds []Round
 	Voters int
 }
 
@@ -52,151 +72,351 @@ func RunVote(ctx context.Context, cfg *Config) (*Result, error) {
 	if cfg.MaxChoices <= 0 {
 		return nil, fmt.Errorf("vote MaxChoices must be positive")
 	}
+	mode, err := ParseMode(string(cfg.Mode))
+	if err != nil {
+		return nil, err
+	}
+
+	pool := cfg.Maps
+	if pool == nil {
+		var problems []mappool.Status
+		pool, problems, err = mappool.Playable(cfg.ServerDir)
+		if err != nil {
+			return nil, err
+		}
+		for i := range problems {
+			cfg.Output.Warn("Leaving %s out of the vote: %s", problems[i].Name, problems[i].Problem())
+		}
+	}
 
-	candidates := pickCandidates(cfg.Maps, cfg.MaxChoices)
-	if len(candidates) == 0 {
+	history, err := LoadHistory(cfg.ServerDir)
+	if err != nil {
+		cfg.Output.Warn("Could not read vote history: %s", err)
+	}
+	picked, pool := nominees(pool, cfg.Nominated, cfg.MaxChoices)
+	pool = excludeRecent(pool, RecentWinners(history, cfg.ExcludeRecent))
+	picked = append(picked, pickCandidates(pool, cfg.MaxChoices-len(picked), Plays(history))...)
+	if len(picked) == 0 {
 		return nil, fmt.Errorf("no maps available for voting")
 	}
+	choices := make([]choice, len(picked))
+	for i := range picked {
+		choices[i] = choice{name: picked[i].Name, label: picked[i].Label()}
+	}
+
+	cfg.Output.Info("Starting %s map vote with %d candidates for %s", mode, len(choices), cfg.Duration)
+
+	box := &ballotBox{
+		title:     "VOTE FOR NEXT MAP!",
+		choices:   choices,
+		mode:      mode,
+		duration:  cfg.Duration,
+		weigh:     opWeigher(mode, cfg.OpWeight, cfg.ServerDir, cfg.Output),
+		serverDir: cfg.ServerDir,
+		mgr:       cfg.Manager,
+		output:    cfg.Output,
+	}
+	outcome, playerVotes, err := box.run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	winner := outcome.Winner
+	result := &Result{
+		Winner: winner,
+		Votes:  outcome.Final(),
+		Rounds: outcome.Rounds,
+		Voters: len(playerVotes),
+	}
+
+	firstChoices := make(map[string]string, len(playerVotes))
+	ranked := make(map[string][]string)
+	for player, choices := range playerVotes {
+		firstChoices[player] = choices[0]
+		if len(choices) > 1 {
+			ranked[player] = choices
+		}
+	}
+	rec := &Record{
+		Time:       time.Now(),
+		Candidates: box.names(),
+		Votes:      outcome.Rounds[0].Counts,
+		Ballots:    firstChoices,
+		Winner:     winner,
+		Voters:     result.Voters,
+	}
+	if mode != Plurality {
+		rec.Mode = mode
+	}
+	if len(ranked) > 0 {
+		rec.Choices = ranked
+	}
+	if err := AppendHistory(cfg.ServerDir, rec); err != nil {
+		cfg.Output.Warn("Could not record vote history: %s", err)
+	}
+
+	// Countdown then teleport.
+	winnerMap := &picked[slices.IndexFunc(picked, func(m mappool.Map) bool { return m.Name == winner })]
+	if err := countdown(ctx, cfg.Manager, "Loading "+winnerMap.Label()); err != nil {
+		return result, fmt.Errorf("teleporting: %w", err)
+	}
+	cfg.Output.Info("Teleporting all players to %s", winnerMap.Name)
+	if err := management.RotateToMap(ctx, winnerMap, cfg.Manager, cfg.Output); err != nil {
+		return result, fmt.Errorf("teleporting: %w", err)
+	}
+
+	return result, nil
+}
+
+// opWeigher returns how many votes each player's ballot counts as: one,
+// or opWeight (DefaultOpWeight if zero) for operators in Weighted mode.
+func opWeigher(mode Mode, opWeight int, serverDir string, output *ui.UI) func(player string) int {
+	if mode != Weighted {
+		return func(string) int { return 1 }
+	}
+	opWeight = cmp.Or(opWeight, DefaultOpWeight)
+	ops, err := players.LoadOps(serverDir)
+	if err != nil {
+		output.Warn("Counting every vote once: %s", err)
+	}
+	return func(player string) int {
+		if ops.IsOp(player) {
+			return opWeight
+		}
+		return 1
+	}
+}
+
+// choice is one option on a ballot.
+type choice struct {
+	// name identifies the option in tallies and history; label is shown
+	// to players.
+	name, label string
+}
+
+// ballotBox runs a vote in chat: it broadcasts the choices, collects
+// ballots from the server log until the time is up, and announces the
+// count.
+type ballotBox struct {
+	title     string
+	choices   []choice
+	mode      Mode
+	duration  time.Duration
+	weigh     func(player string) int
+	serverDir string
+	mgr       management.ServerManager
+	output    *ui.UI
+	// needVotes ends a vote no one took part in with no winner, instead
+	// of one picked at random.
+	needVotes bool
+}
 
-	cfg.Output.Info("Starting map vote with %d candidates for %s", len(candidates), cfg.Duration)
+// run holds the vote, returning the count and each voter's choices. The
+// outcome has no winner if needVotes is set and no one voted.
+func (b *ballotBox) run(ctx context.Context) (Outcome, map[string][]string, error) {
+	names := b.names()
 
 	// Broadcast vote options.
-	if err := broadcastVoteStart(ctx, cfg.Manager, candidates, int(cfg.Duration.Seconds())); err != nil {
-		return nil, fmt.Errorf("broadcasting vote: %w", err)
+	if err := broadcastVoteStart(ctx, b.mgr, b.title, b.mode, b.choices, int(b.duration.Seconds())); err != nil {
+		return Outcome{}, nil, fmt.Errorf("broadcasting vote: %w", err)
 	}
 
-	// Start tailing the log.
-	logPath := filepath.Join(cfg.ServerDir, "logs", "latest.log")
-	voteCtx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	// Follow chat in the server log.
+	voteCtx, cancel := context.WithTimeout(ctx, b.duration)
 	defer cancel()
 
-	lines, err := TailLog(voteCtx, logPath)
+	stream := events.NewStream(b.serverDir)
+	parser, err := events.LoadParser(b.serverDir)
 	if err != nil {
-		return nil, fmt.Errorf("tailing log: %w", err)
+		b.output.Warn("Ignoring custom chat formats: %s", err)
 	}
+	stream.Parser = parser
+	chat := stream.Subscribe(voteCtx, events.FromEnd)
 
 	// Collect votes.
 	var mu sync.Mutex
-	playerVotes := make(map[string]int) // player -> choice index (1-based)
+	playerVotes := make(map[string][]string) // player -> chosen names
 
 	// Schedule reminders.
-	go sendReminders(voteCtx, cfg.Manager, candidates, cfg.Duration)
+	go sendReminders(voteCtx, b.mgr, b.mode, b.duration)
 
 	// Read votes until timeout.
-	for line := range lines {
-		player, msg, ok := ParseChatMessage(line)
-		if !ok {
+	for ev := range chat {
+		if ev.Kind != events.Chat {
 			continue
 		}
-		msg = strings.TrimSpace(msg)
-		choice, err := strconv.Atoi(msg)
-		if err != nil || choice < 1 || choice > len(candidates) {
+		player := ev.Player
+		choices, ok := parseBallot(b.mode, strings.TrimSpace(ev.Message), names)
+		if !ok {
 			continue
 		}
 		mu.Lock()
-		playerVotes[player] = choice
+		playerVotes[player] = choices
 		mu.Unlock()
-		cfg.Output.Info("%s voted for [%d] %s", player, choice, candidates[choice-1])
+		sep := ", "
+		if b.mode == Runoff {
+			sep = " > "
+		}
+		b.output.Info("%s voted for %s", player, strings.Join(b.labels(choices), sep))
 	}
 
 	// Tally.
 	mu.Lock()
-	tally := make(map[string]int)
-	for _, choice := range playerVotes {
-		tally[candidates[choice-1]]++
+	ballots := make([]Ballot, 0, len(playerVotes))
+	for player, choices := range playerVotes {
+		ballots = append(ballots, Ballot{Player: player, Choices: choices, Weight: b.weigh(player)})
 	}
 	mu.Unlock()
 
-	winner := pickWinner(candidates, tally)
-	result := &Result{
-		Winner: winner,
-		Votes:  tally,
-		Voters: len(playerVotes),
+	if len(ballots) == 0 && b.needVotes {
+		b.output.Warn("Vote complete: no one voted")
+		if err := b.mgr.SendCommand(ctx, management.Tellraw("@a", management.Text{Text: "No votes, so nothing changes", Color: "gold"})); err != nil {
+			return Outcome{}, playerVotes, fmt.Errorf("broadcasting results: %w", err)
+		}
+		return Outcome{}, playerVotes, nil
 	}
 
-	cfg.Output.Success("Vote complete: %s wins with %d votes (%d voters)",
-		winner, tally[winner], result.Voters)
+	outcome := tally(b.mode, names, ballots, randomTieBreak)
+	final := outcome.Final()
+	b.output.Success("Vote complete: %s wins with %d votes (%d voters)",
+		outcome.Winner, final[outcome.Winner], len(playerVotes))
 
-	// Announce results and teleport.
-	if err := broadcastResults(ctx, cfg.Manager, candidates, tally, winner); err != nil {
-		return result, fmt.Errorf("broadcasting results: %w", err)
+	// Announce results.
+	if len(outcome.Rounds) > 1 {
+		if err := broadcastRounds(ctx, b.mgr, b.choices, outcome.Rounds); err != nil {
+			return outcome, playerVotes, fmt.Errorf("broadcasting rounds: %w", err)
+		}
+	}
+	if err := broadcastResults(ctx, b.mgr, b.choices, final, outcome.Winner); err != nil {
+		return outcome, playerVotes, fmt.Errorf("broadcasting results: %w", err)
 	}
+	return outcome, playerVotes, nil
+}
 
-	// Countdown then teleport.
-	if err := countdownAndTeleport(ctx, cfg.Manager, winner, cfg.Output); err != nil {
-		return result, fmt.Errorf("teleporting: %w", err)
+// names returns the names of the choices.
+func (b *ballotBox) names() []string {
+	out := make([]string, len(b.choices))
+	for i := range b.choices {
+		out[i] = b.choices[i].name
 	}
+	return out
+}
 
-	return result, nil
+// labels returns the labels of the named choices.
+func (b *ballotBox) labels(names []string) []string {
+	return choiceLabels(b.choices, names)
 }
 
-// pickCandidates selects up to maxChoices maps from the pool via random sampling.
-func pickCandidates(pool []string, maxChoices int) []string {
-	if len(pool) <= maxChoices {
-		out := make([]string, len(pool))
-		copy(out, pool)
-		return out
+// nominees takes up to maxChoices of the nominated maps out of pool,
+// returning them and the rest of the pool.
+func nominees(pool []mappool.Map, nominated []string, maxChoices int) (picked, rest []mappool.Map) {
+	rest = slices.Clone(pool)
+	for _, name := range nominated {
+		if len(picked) == maxChoices {
+			break
+		}
+		if i := slices.IndexFunc(rest, func(m mappool.Map) bool { return m.Name == name }); i >= 0 {
+			picked = append(picked, rest[i])
+			rest = slices.Delete(rest, i, i+1)
+		}
 	}
-	perm := rand.Perm(len(pool))
-	out := make([]string, maxChoices)
-	for i := range maxChoices {
-		out[i] = pool[perm[i]]
+	return picked, rest
+}
+
+// minCandidates is the fewest maps worth voting on. Recent winners are let
+// back in rather than leave fewer.
+const minCandidates = 2
+
+// excludeRecent drops the recent winners, most recent first, from pool. If
+// that would leave fewer than minCandidates maps, the oldest of them are
+// kept in.
+func excludeRecent(pool []mappool.Map, recent []string) []mappool.Map {
+	for n := len(recent); n > 0; n-- {
+		kept := slices.DeleteFunc(slices.Clone(pool), func(m mappool.Map) bool {
+			return slices.Contains(recent[:n], m.Name)
+		})
+		if len(kept) >= min(minCandidates, len(pool)) {
+			return kept
+		}
 	}
-	return out
+	return pool
 }
 
-// pickWinner returns the map with the most votes. Ties are broken randomly.
-// If no votes were cast, a random candidate is chosen.
-func pickWinner(candidates []string, tally map[string]int) string {
-	if len(tally) == 0 {
-		return candidates[rand.IntN(len(candidates))]
+// pickCandidates selects up to maxChoices maps from the pool by random
+// sampling without replacement. Each draw favours maps by their weight,
+// divided by one more than the number of times they have been played so
+// less-played maps come up more often.
+func pickCandidates(pool []mappool.Map, maxChoices int, plays map[string]int) []mappool.Map {
+	remaining := slices.Clone(pool)
+	weight := func(m *mappool.Map) float64 {
+		return float64(max(m.Weight, 1)) / float64(plays[m.Name]+1)
 	}
+	out := make([]mappool.Map, 0, min(len(pool), maxChoices))
+	for len(out) < maxChoices && len(remaining) > 0 {
+		total := 0.0
+		for i := range remaining {
+			total += weight(&remaining[i])
+		}
+		r := rand.Float64() * total
+		i := 0
+		for ; i < len(remaining)-1 && r >= weight(&remaining[i]); i++ {
+			r -= weight(&remaining[i])
+		}
+		out = append(out, remaining[i])
+		remaining = slices.Delete(remaining, i, i+1)
+	}
+	return out
+}
 
-	maxVotes := 0
-	for _, v := range tally {
-		if v > maxVotes {
-			maxVotes = v
+// choiceLabels returns the labels of the named choices.
+func choiceLabels(choices []choice, names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = n
+		for _, c := range choices {
+			if c.name == n {
+				out[i] = c.label
+			}
 		}
 	}
+	return out
+}
+
+// rule is the line framing vote announcements.
+var rule = management.Tellraw("@a", management.Text{Text: "==========================", Color: "gold"})
 
-	var tied []string
-	for _, c := range candidates {
-		if tally[c] == maxVotes {
-			tied = append(tied, c)
+// sendAll sends each command in turn, stopping at the first error.
+func sendAll(ctx context.Context, mgr management.ServerManager, cmds []string) error {
+	for _, c := range cmds {
+		if err := mgr.SendCommand(ctx, c); err != nil {
+			return err
 		}
 	}
-	return tied[rand.IntN(len(tied))]
+	return nil
 }
 
 // broadcastVoteStart sends the vote options to all players via tellraw.
-func broadcastVoteStart(ctx context.Context, mgr management.ServerManager, candidates []string, durationSec int) error {
+func broadcastVoteStart(ctx context.Context, mgr management.ServerManager, title string, mode Mode, candidates []choice, durationSec int) error {
 	lines := []string{
-		`["",{"text":"==========================","color":"gold"}]`,
-		`["",{"text":"   VOTE FOR NEXT MAP!","color":"gold","bold":true}]`,
-		`["",{"text":"==========================","color":"gold"}]`,
-		`["",{"text":"Type a number to vote:","color":"white"}]`,
+		rule,
+		management.Tellraw("@a", management.Text{Text: "   " + title, Color: "gold", Bold: true}),
+		rule,
+		management.Tellraw("@a", management.Text{Text: mode.instructions(), Color: "white"}),
 	}
-	for i, m := range candidates {
-		lines = append(lines, fmt.Sprintf(
-			`["",{"text":"  [%d] ","color":"green","bold":true},{"text":%q,"color":"white"}]`,
-			i+1, m))
+	for i := range candidates {
+		lines = append(lines, management.Tellraw("@a",
+			management.Text{Text: fmt.Sprintf("  [%d] ", i+1), Color: "green", Bold: true},
+			management.Text{Text: candidates[i].label, Color: "white"}))
 	}
 	lines = append(lines,
-		`["",{"text":"==========================","color":"gold"}]`,
-		fmt.Sprintf(`["",{"text":"  Voting ends in %d seconds","color":"yellow"}]`, durationSec),
-		`["",{"text":"==========================","color":"gold"}]`,
+		rule,
+		management.Tellraw("@a", management.Text{Text: fmt.Sprintf("  Voting ends in %d seconds", durationSec), Color: "yellow"}),
+		rule,
 	)
-
-	for _, l := range lines {
-		if err := mgr.SendCommand(ctx, "tellraw @a "+l); err != nil {
-			return err
-		}
-	}
-	return nil
+	return sendAll(ctx, mgr, lines)
 }
 
 // sendReminders sends periodic vote reminders at halfway and 5s remaining.
-func sendReminders(ctx context.Context, mgr management.ServerManager, candidates []string, duration time.Duration) {
+func sendReminders(ctx context.Context, mgr management.ServerManager, mode Mode, duration time.Duration) {
 	half := duration / 2
 	fiveSecondsMark := duration - 5*time.Second
 
@@ -204,9 +424,7 @@ func sendReminders(ctx context.Context, mgr management.ServerManager, candidates
 	case <-ctx.Done():
 		return
 	case <-time.After(half):
-		msg := fmt.Sprintf(`["",{"text":"Vote reminder! %d seconds left. Type 1-%d to vote!","color":"yellow"}]`,
-			int(duration.Seconds())-int(half.Seconds()), len(candidates))
-		_ = mgr.SendCommand(ctx, "tellraw @a "+msg)
+		_ = mgr.SendCommand(ctx, management.Tellraw("@a", management.Text{Text: reminderText(mode, duration-half), Color: "yellow"}))
 	}
 
 	if fiveSecondsMark > half {
@@ -215,21 +433,30 @@ func sendReminders(ctx context.Context, mgr management.ServerManager, candidates
 		case <-ctx.Done():
 			return
 		case <-time.After(remaining):
-			msg := `["",{"text":"5 seconds left to vote!","color":"red","bold":true}]`
-			_ = mgr.SendCommand(ctx, "tellraw @a "+msg)
+			_ = mgr.SendCommand(ctx, management.Tellraw("@a", management.Text{Text: "5 seconds left to vote!", Color: "red", Bold: true}))
 		}
 	}
 }
 
+// reminderText reminds players how to vote in mode with left to go.
+func reminderText(mode Mode, left time.Duration) string {
+	return fmt.Sprintf("Vote reminder! %d seconds left. %s", int(left.Seconds()), strings.TrimSuffix(mode.instructions(), ":"))
+}
+
 // broadcastResults announces the vote results to all players.
-func broadcastResults(ctx context.Context, mgr management.ServerManager, candidates []string, tally map[string]int, winner string) error {
+func broadcastResults(ctx context.Context, mgr management.ServerManager, candidates []choice, tally map[string]int, winner string) error {
 	lines := []string{
-		`["",{"text":"==========================","color":"gold"}]`,
-		`["",{"text":"   RESULTS","color":"gold","bold":true}]`,
-		`["",{"text":"==========================","color":"gold"}]`,
+		rule,
+		management.Tellraw("@a", management.Text{Text: "   RESULTS", Color: "gold", Bold: true}),
+		rule,
 	}
-	for _, c := range candidates {
-		count := tally[c]
+	for i := range candidates {
+		c := candidates[i].name
+		count, standing := tally[c]
+		if !standing {
+			// Knocked out in an earlier round.
+			continue
+		}
 		label := "votes"
 		if count == 1 {
 			label = "vote"
@@ -238,16 +465,34 @@ func broadcastResults(ctx context.Context, mgr management.ServerManager, candida
 		if c == winner {
 			marker = " ***"
 		}
-		lines = append(lines, fmt.Sprintf(
-			`["",{"text":"  %s: %d %s%s","color":%q}]`,
-			c, count, label, marker, mapResultColor(c, winner)))
+		text := fmt.Sprintf("  %s: %d %s%s", candidates[i].label, count, label, marker)
+		lines = append(lines, management.Tellraw("@a", management.Text{Text: text, Color: mapResultColor(c, winner)}))
 	}
-	lines = append(lines,
-		`["",{"text":"==========================","color":"gold"}]`,
-	)
+	lines = append(lines, rule)
+	return sendAll(ctx, mgr, lines)
+}
 
-	for _, l := range lines {
-		if err := mgr.SendCommand(ctx, "tellraw @a "+l); err != nil {
+// broadcastRounds announces each round of an instant-runoff count but the
+// last, which broadcastResults shows.
+func broadcastRounds(ctx context.Context, mgr management.ServerManager, candidates []choice, rounds []Round) error {
+	for i, r := range rounds[:len(rounds)-1] {
+		var counts []string
+		for j := range candidates {
+			if n, ok := r.Counts[candidates[j].name]; ok {
+				counts = append(counts, fmt.Sprintf("%s %d", candidates[j].label, n))
+			}
+		}
+		lines := []string{
+			management.Tellraw("@a",
+				management.Text{Text: fmt.Sprintf("Round %d: ", i+1), Color: "gold", Bold: true},
+				management.Text{Text: strings.Join(counts, ", "), Color: "white"}),
+			management.Tellraw("@a",
+				management.Text{Text: "  Out: " + strings.Join(choiceLabels(candidates, r.Eliminated), ", "), Color: "gray"}),
+		}
+		if err := sendAll(ctx, mgr, lines); err != nil {
+			return err
+		}
+		if err := management.Sleep(ctx, 1); err != nil {
 			return err
 		}
 	}
@@ -261,10 +506,10 @@ func mapResultColor(candidate, winner string) string {
 	return "gray"
 }
 
-// countdownAndTeleport announces a 5-second countdown then teleports all players.
-func countdownAndTeleport(ctx context.Context, mgr management.ServerManager, mapName string, output *ui.UI) error {
-	msg := fmt.Sprintf(`["",{"text":"  Loading %s in 5...","color":"yellow","bold":true}]`, mapName)
-	if err := mgr.SendCommand(ctx, "tellraw @a "+msg); err != nil {
+// countdown announces "<what> in 5..." and counts down to zero.
+func countdown(ctx context.Context, mgr management.ServerManager, what string) error {
+	msg := management.Tellraw("@a", management.Text{Text: "  " + what + " in 5...", Color: "yellow", Bold: true})
+	if err := mgr.SendCommand(ctx, msg); err != nil {
 		return err
 	}
 
@@ -272,16 +517,11 @@ func countdownAndTeleport(ctx context.Context, mgr management.ServerManager, map
 		if err := management.Sleep(ctx, 1); err != nil {
 			return err
 		}
-		msg := fmt.Sprintf(`["",{"text":"%d...","color":"yellow"}]`, i)
-		if err := mgr.SendCommand(ctx, "tellraw @a "+msg); err != nil {
+		msg := management.Tellraw("@a", management.Text{Text: fmt.Sprintf("%d...", i), Color: "yellow"})
+		if err := mgr.SendCommand(ctx, msg); err != nil {
 			return err
 		}
 	}
 
-	if err := management.Sleep(ctx, 1); err != nil {
-		return err
-	}
-
-	output.Info("Teleporting all players to %s", mapName)
-	return management.RotateToMap(ctx, mapName, mgr, output)
+	return management.Sleep(ctx, 1)
 }
@@ -3,162 +3,210 @@ package vote
 import (
 	"slices"
 	"testing"
+	"time"
+
+	"github.com/KevinTCoughlin/mc-dad-server/internal/mappool"
 )
 
-func TestParseChatMessage(t *testing.T) {
-	tests := []struct {
-		name       string
-		line       string
-		wantPlayer string
-		wantMsg    string
-		wantOk     bool
-	}{
-		{
-			name:       "valid vote",
-			line:       "[12:34:56] [Server thread/INFO]: <Steve> 1",
-			wantPlayer: "Steve",
-			wantMsg:    "1",
-			wantOk:     true,
-		},
-		{
-			name:       "valid chat message",
-			line:       "[09:00:00] [Server thread/INFO]: <Alex> hello world",
-			wantPlayer: "Alex",
-			wantMsg:    "hello world",
-			wantOk:     true,
-		},
-		{
-			name:       "multi digit vote",
-			line:       "[12:34:56] [Server thread/INFO]: <Player123> 5",
-			wantPlayer: "Player123",
-			wantMsg:    "5",
-			wantOk:     true,
-		},
-		{
-			name:   "server message not chat",
-			line:   "[12:34:56] [Server thread/INFO]: Steve joined the game",
-			wantOk: false,
-		},
-		{
-			name:   "empty line",
-			line:   "",
-			wantOk: false,
-		},
-		{
-			name:   "different thread",
-			line:   "[12:34:56] [Async Chat Thread/INFO]: <Steve> 1",
-			wantOk: false,
-		},
-		{
-			name:       "underscore in name",
-			line:       "[12:34:56] [Server thread/INFO]: <Cool_Kid99> 3",
-			wantPlayer: "Cool_Kid99",
-			wantMsg:    "3",
-			wantOk:     true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			player, msg, ok := ParseChatMessage(tt.line)
-			if ok != tt.wantOk {
-				t.Fatalf("ok = %v, want %v", ok, tt.wantOk)
-			}
-			if !ok {
-				return
-			}
-			if player != tt.wantPlayer {
-				t.Errorf("player = %q, want %q", player, tt.wantPlayer)
-			}
-			if msg != tt.wantMsg {
-				t.Errorf("msg = %q, want %q", msg, tt.wantMsg)
-			}
-		})
+// pool returns maps with the given names and weight 1.
+func pool(names ...string) []mappool.Map {
+	out := make([]mappool.Map, len(names))
+	for i, n := range names {
+		out[i] = mappool.Map{Name: n, World: n, Weight: 1}
 	}
+	return out
 }
 
 func TestPickCandidates(t *testing.T) {
 	t.Run("all maps fit", func(t *testing.T) {
-		maps := []string{"a", "b", "c"}
-		got := pickCandidates(maps, 5)
+		got := pickCandidates(pool("a", "b", "c"), 5, nil)
 		if len(got) != 3 {
 			t.Fatalf("got %d candidates, want 3", len(got))
 		}
 	})
 
 	t.Run("subset selected", func(t *testing.T) {
-		maps := []string{"a", "b", "c", "d", "e", "f", "g"}
-		got := pickCandidates(maps, 3)
+		maps := pool("a", "b", "c", "d", "e", "f", "g")
+		got := pickCandidates(maps, 3, nil)
 		if len(got) != 3 {
 			t.Fatalf("got %d candidates, want 3", len(got))
 		}
 		// All results should be from the pool.
-		pool := make(map[string]bool)
-		for _, m := range maps {
-			pool[m] = true
-		}
 		for _, c := range got {
-			if !pool[c] {
-				t.Errorf("candidate %q not in pool", c)
+			if !slices.ContainsFunc(maps, func(m mappool.Map) bool { return m.Name == c.Name }) {
+				t.Errorf("candidate %q not in pool", c.Name)
 			}
 		}
 	})
 
 	t.Run("no duplicates", func(t *testing.T) {
-		maps := []string{"a", "b", "c", "d", "e"}
-		got := pickCandidates(maps, 5)
+		got := pickCandidates(pool("a", "b", "c", "d", "e"), 5, nil)
 		seen := make(map[string]bool)
 		for _, c := range got {
-			if seen[c] {
-				t.Errorf("duplicate candidate: %q", c)
+			if seen[c.Name] {
+				t.Errorf("duplicate candidate: %q", c.Name)
 			}
-			seen[c] = true
+			seen[c.Name] = true
 		}
 	})
 
 	t.Run("empty pool", func(t *testing.T) {
-		got := pickCandidates(nil, 5)
+		got := pickCandidates(nil, 5, nil)
 		if len(got) != 0 {
 			t.Fatalf("got %d candidates from empty pool", len(got))
 		}
 	})
-}
-
-func TestPickWinner(t *testing.T) {
-	candidates := []string{"a", "b", "c"}
 
-	t.Run("clear winner", func(t *testing.T) {
-		tally := map[string]int{"a": 1, "b": 5, "c": 2}
-		winner := pickWinner(candidates, tally)
-		if winner != "b" {
-			t.Errorf("winner = %q, want %q", winner, "b")
+	t.Run("weight favours a map", func(t *testing.T) {
+		maps := pool("a", "b", "c", "d")
+		maps[3].Weight = 50
+		picked := 0
+		for range 200 {
+			if pickCandidates(maps, 1, nil)[0].Name == "d" {
+				picked++
+			}
 		}
-	})
-
-	t.Run("no votes picks random candidate", func(t *testing.T) {
-		winner := pickWinner(candidates, map[string]int{})
-		found := slices.Contains(candidates, winner)
-		if !found {
-			t.Errorf("winner %q not in candidates", winner)
+		// Expected 50/53 of the time; equal weights would give a quarter.
+		if picked < 150 {
+			t.Errorf("heavy map picked %d/200 times", picked)
 		}
 	})
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	records, err := LoadHistory(dir)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("LoadHistory() on empty dir = %v, %v; want no records", records, err)
+	}
 
-	t.Run("single voter", func(t *testing.T) {
-		tally := map[string]int{"c": 1}
-		winner := pickWinner(candidates, tally)
-		if winner != "c" {
-			t.Errorf("winner = %q, want %q", winner, "c")
+	for _, winner := range []string{"a", "b"} {
+		rec := &Record{
+			Candidates: []string{"a", "b"},
+			Votes:      map[string]int{winner: 1},
+			Ballots:    map[string]string{"Steve": winner},
+			Winner:     winner,
+			Voters:     1,
 		}
-	})
+		if err := AppendHistory(dir, rec); err != nil {
+			t.Fatalf("AppendHistory() error = %v", err)
+		}
+	}
 
-	t.Run("tie picks from tied candidates", func(t *testing.T) {
-		tally := map[string]int{"a": 3, "b": 3}
-		// Run multiple times to verify it picks from tied set.
-		for range 20 {
-			winner := pickWinner(candidates, tally)
-			if winner != "a" && winner != "b" {
-				t.Errorf("winner = %q, should be a or b", winner)
+	records, err = LoadHistory(dir)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if len(records) != 2 || records[0].Winner != "a" || records[1].Winner != "b" {
+		t.Fatalf("LoadHistory() = %+v, want winners a then b", records)
+	}
+	if records[1].Ballots["Steve"] != "b" {
+		t.Errorf("ballots = %v, want Steve -> b", records[1].Ballots)
+	}
+}
+
+func TestExcludeRecent(t *testing.T) {
+	tests := []struct {
+		name   string
+		pool   []mappool.Map
+		recent []string
+		want   []string
+	}{
+		{name: "none recent", pool: pool("a", "b", "c"), want: []string{"a", "b", "c"}},
+		{name: "recent left out", pool: pool("a", "b", "c", "d"), recent: []string{"c", "a"}, want: []string{"b", "d"}},
+		{name: "oldest let back in", pool: pool("a", "b", "c"), recent: []string{"c", "a"}, want: []string{"a", "b"}},
+		{name: "tiny pool kept", pool: pool("a", "b"), recent: []string{"a", "b"}, want: []string{"a", "b"}},
+		{name: "unknown winner", pool: pool("a", "b"), recent: []string{"gone"}, want: []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := excludeRecent(tt.pool, tt.recent)
+			names := make([]string, len(got))
+			for i := range got {
+				names[i] = got[i].Name
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("excludeRecent() = %v, want %v", names, tt.want)
 			}
+		})
+	}
+}
+
+func TestPickCandidatesFavoursLessPlayed(t *testing.T) {
+	maps := pool("a", "b")
+	plays := map[string]int{"a": 19}
+	picked := 0
+	for range 200 {
+		if pickCandidates(maps, 1, plays)[0].Name == "b" {
+			picked++
 		}
-	})
+	}
+	// Expected 20/21 of the time.
+	if picked < 150 {
+		t.Errorf("unplayed map picked %d/200 times", picked)
+	}
+}
+
+func TestRecentWinnersAndPlays(t *testing.T) {
+	records := []Record{{Winner: "a"}, {Winner: "b"}, {Winner: "a"}, {Winner: "c"}}
+
+	if got := RecentWinners(records, 2); !slices.Equal(got, []string{"c", "a"}) {
+		t.Errorf("RecentWinners(2) = %v", got)
+	}
+	if got := RecentWinners(records, 5); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Errorf("RecentWinners(5) = %v", got)
+	}
+	if got := RecentWinners(records, 0); len(got) != 0 {
+		t.Errorf("RecentWinners(0) = %v", got)
+	}
+
+	plays := Plays(records)
+	if plays["a"] != 2 || plays["b"] != 1 || plays["c"] != 1 {
+		t.Errorf("Plays() = %v", plays)
+	}
+}
+
+func TestStats(t *testing.T) {
+	day := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Time: day, Candidates: []string{"a", "b"}, Votes: map[string]int{"a": 2, "b": 1}, Winner: "a"},
+		{Time: day.AddDate(0, 0, 1), Candidates: []string{"a", "b", "c"}, Votes: map[string]int{"b": 3}, Winner: "b"},
+		{Time: day.AddDate(0, 0, 2), Candidates: []string{"a", "c"}, Votes: map[string]int{"a": 1}, Winner: "a"},
+	}
+	stats := Stats(records, []string{"a", "new"})
+
+	var names []string
+	for i := range stats {
+		names = append(names, stats[i].Name)
+	}
+	if want := []string{"a", "b", "c", "new"}; !slices.Equal(names, want) {
+		t.Fatalf("Stats() order = %v, want %v", names, want)
+	}
+	a := stats[0]
+	if a.Offered != 3 || a.Wins != 2 || a.Votes != 3 || !a.LastPlayed.Equal(day.AddDate(0, 0, 2)) {
+		t.Errorf("a = %+v", a)
+	}
+	if got := a.WinRate(); got < 0.66 || got > 0.67 {
+		t.Errorf("a.WinRate() = %v, want 2/3", got)
+	}
+	if n := stats[3]; n.Offered != 0 || n.WinRate() != 0 || !n.LastPlayed.IsZero() {
+		t.Errorf("new = %+v, want zero", n)
+	}
+}
+
+func TestReminderText(t *testing.T) {
+	tests := []struct {
+		mode Mode
+		want string
+	}{
+		{Plurality, "Vote reminder! 15 seconds left. Type a number to vote"},
+		{Runoff, "Vote reminder! 15 seconds left. Rank the maps, favourite first (e.g. 3 1 2)"},
+		{Approval, "Vote reminder! 15 seconds left. Type every map you'd play (e.g. 1 3)"},
+	}
+	for _, tt := range tests {
+		if got := reminderText(tt.mode, 15*time.Second); got != tt.want {
+			t.Errorf("reminderText(%s) = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
 }